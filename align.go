@@ -2,6 +2,12 @@ package brimtext
 
 import (
 	"bytes"
+	"context"
+	"io"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
 	"strings"
 )
 
@@ -11,6 +17,52 @@ const (
 	Left Alignment = iota
 	Right
 	Center
+	// Justify spreads the words of each line out to the full column width,
+	// except for the last line of each cell, which is left aligned.
+	Justify
+	// Decimal right aligns numbers on their decimal points, or their last
+	// digits if they have none, so columns of values with differing
+	// precision line up, as in:
+	//
+	//  "   12.5   "
+	//  "    3     "
+	//  "1,024.125%"
+	//  "       n/a"
+	//
+	// Cells without digits, and those of the header rows, are right
+	// aligned.
+	Decimal
+)
+
+// VerticalAlignment places the lines of a cell within a row that other cells
+// make taller; see AlignOptions.VerticalAlignments.
+type VerticalAlignment int
+
+const (
+	Top VerticalAlignment = iota
+	Middle
+	Bottom
+)
+
+// WrapMode selects how the cells of a column are fit to its width; see
+// AlignOptions.WrapModes.
+type WrapMode int
+
+const (
+	// WrapWords wraps cells at spaces, as Wrap does.
+	WrapWords WrapMode = iota
+	// WrapRunes breaks the lines of cells between any two runes, for values
+	// like hashes or base64 that have no spaces.
+	WrapRunes
+	// WrapTruncate cuts off each line of cells at the width, ending it with
+	// the AlignOptions.Ellipsis.
+	WrapTruncate
+	// WrapTruncateHead cuts off the start of each line of cells instead,
+	// such as for paths whose ends matter most.
+	WrapTruncateHead
+	// WrapTruncateMiddle cuts out the middle of each line of cells, keeping
+	// both ends, such as for long identifiers.
+	WrapTruncateMiddle
 )
 
 type AlignOptions struct {
@@ -48,6 +100,13 @@ type AlignOptions struct {
 	NilFirstUDLR string
 	NilUDLR      string
 	NilLastUDL   string
+	// FooterFirstUDR etc. control the separator output before the footer
+	// rows; see FooterRows. If none are set, the FirstNil* ones are used.
+	FooterFirstUDR  string
+	FooterLR        string
+	FooterFirstUDLR string
+	FooterUDLR      string
+	FooterLastUDL   string
 	// LastUR etc. control what is output for situations with an appended
 	// display row.
 	LastUR       string
@@ -58,6 +117,221 @@ type AlignOptions struct {
 	// NilBetweenEveryRow will add a nil data row between all rows; use to emit
 	// FirstNil* and Nil* row separators.
 	NilBetweenEveryRow bool
+	// NormalizeUnicode will convert each cell to NFC before measuring; see
+	// NormalizeUnicode.
+	NormalizeUnicode bool
+	// StripInvisible will remove zero width and control characters from each
+	// cell before measuring; see StripInvisible.
+	StripInvisible bool
+	// ControlChars selects whether control characters, which would corrupt
+	// the layout, are kept, stripped, or escaped in each cell before
+	// measuring; see SanitizeControl.
+	ControlChars ControlChars
+	// HeatScales, indexed by column, color each numeric cell in that column
+	// according to where its value falls in the column's range, so outliers
+	// in dense metric tables stand out. Nil entries leave their columns
+	// uncolored.
+	HeatScales []*HeatScale
+	// ContinuationAlignments, indexed by column, give the alignment for the
+	// second and subsequent lines of each cell in that column, such as Left
+	// for continuation lines of a Right aligned cell.
+	ContinuationAlignments []Alignment
+	// ContinuationIndent is prefixed to the second and subsequent lines of
+	// each cell in the columns with ContinuationAlignments.
+	ContinuationIndent string
+	// HeaderAlignments, if not nil, give the alignments for the header rows,
+	// those before the first nil row (or just the first row with
+	// NilBetweenEveryRow), instead of Alignments; columns beyond those given
+	// are Center, so []Alignment{} centers all the headers.
+	HeaderAlignments []Alignment
+	// RowCountFooter adds a line like "(3 rows)" after the table, counting
+	// the data rows other than header rows.
+	RowCountFooter bool
+	// Margin is prefixed to every line output, outside of any borders, such
+	// as "  " to indent the whole table.
+	Margin string
+	// Padding is the number of spaces added on each side of every cell's
+	// content, inside of any borders, in addition to those the Row* strings
+	// may have.
+	Padding int
+	// Formatters, indexed by column, convert each cell in that column other
+	// than those of the header rows into its display form, such as raw byte
+	// counts into BytesFormatter sizes. HeatScales see the cells before they
+	// are formatted. Nil entries leave their columns as is.
+	Formatters []ColumnFormatter
+	// MaxTableWidth, if > 0, is the widest each line of output may be,
+	// including any Margin, borders, and Padding; if the table would be
+	// wider, its widest columns are narrowed, rewrapping their cells, until
+	// it fits.
+	MaxTableWidth int
+	// FitWidth, when MaxTableWidth is 0, fits the table to the terminal as
+	// MaxTableWidth would, with the same meaning as a width of 0 given to
+	// Wrap, so tables reflow to whatever size terminal they are shown on.
+	FitWidth bool
+	// WidthFor, if not nil, is called for each column during layout with
+	// the width of its widest line and the width fitting to MaxTableWidth
+	// would give it (the same, if the table fits), and returns the width to
+	// use instead, allowing custom shrink and grow policies. Returning
+	// naturalWidth never shrinks the column, for example. The columns left
+	// at the available width are then refit around those changed.
+	WidthFor func(col int, naturalWidth int, available int) int
+	// Highlight, if not nil, marks each match within the lines of the cells
+	// with HighlightCode, as with the Highlight function, after layout so
+	// the widths are unaffected. Use regexp.QuoteMeta to highlight a plain
+	// substring, and "(?i)" to ignore case.
+	Highlight *regexp.Regexp
+	// HighlightCode is the ANSI escape code for Highlight; if nil,
+	// ANSIEscape.Reverse is used.
+	HighlightCode []byte
+	// WrapModes, indexed by column, select how cells wider than their
+	// column's width in Widths, or from MaxTableWidth, are fit to it;
+	// columns beyond those given use WrapWords.
+	WrapModes []WrapMode
+	// MaxWidths, indexed by column, limit the widths of columns by
+	// truncating each line of their cells that is wider, keeping every row
+	// to one line where wrapping isn't wanted, such as for log style tables.
+	// The columns' WrapModes choose how lines are truncated, with
+	// WrapTruncate used for those that aren't truncation modes. Values < 1
+	// leave their columns unlimited.
+	MaxWidths []int
+	// Ellipsis marks where truncated lines were cut; if empty, "…" is used.
+	Ellipsis string
+	// ResetCells ends the lines of cells that leave an ANSI color or other
+	// styling in effect with ANSIEscape.Reset, before any padding or borders
+	// are output, so an unterminated code can't bleed into the rest of the
+	// table. This is always done when HeatScales, Highlight, HeaderStyle,
+	// FooterStyle, or RowStyle are set, and by AlignCells for cells with a
+	// Style.
+	ResetCells bool
+	// Redact lists the columns whose cells, other than those of the header
+	// rows, are masked, so the same data can give both privileged and
+	// shareable versions of a report. Formatters and HeatScales only see
+	// the masked cells.
+	Redact []int
+	// RedactHeaders lists columns to mask by their text in the first header
+	// row, ignoring case, in addition to those in Redact.
+	RedactHeaders []string
+	// RedactText, if not empty, replaces each masked cell, such as "∗∗∗",
+	// hiding its length too. Otherwise each rune of the cell is replaced
+	// with "*", keeping its width.
+	RedactText string
+	// Markdown outputs the separator after the header rows as a GitHub
+	// flavored Markdown delimiter row, with colons giving the Alignments,
+	// and escapes "|" and newlines within cells so each row stays one
+	// table row; see NewMarkdownAlignOptions. Other separators are left
+	// out, and if the data has no nil row, the first row is the header.
+	// Cells aren't rewrapped to Widths or MaxTableWidth, which would split
+	// them over several table rows.
+	Markdown bool
+	// Types is the TypeRegistry that AlignStructs and AlignAny use to turn
+	// values into cells and to choose column alignments; if nil,
+	// DefaultTypeRegistry is used.
+	Types *TypeRegistry
+	// HeaderStyle, if not nil, styles the cells of the header rows, those
+	// before the first nil row (or just the first row with
+	// NilBetweenEveryRow), so callers needn't style the strings themselves
+	// and upset the widths.
+	HeaderStyle *HeaderStyle
+	// FooterRows is how many of the last data rows, not counting nil rows,
+	// are footer rows, such as totals. The footer rows are separated from
+	// the rest by the Footer* separator, in place of any nil row
+	// NilBetweenEveryRow would add, and aren't counted by RowCountFooter.
+	FooterRows int
+	// FooterStyle, if not nil, styles the cells of the footer rows as
+	// HeaderStyle does those of the header rows.
+	FooterStyle *HeaderStyle
+	// Title, if not empty, is output centered above the table, wrapped if
+	// it is wider than the table.
+	Title string
+	// Caption, if not empty, is output below the table, wrapped to the
+	// table's width.
+	Caption string
+	// SortBy, if not empty, sorts the data rows by the keys given, the
+	// first key deciding the order and the others breaking ties, before
+	// the cells are formatted. Header and footer rows stay in place, and
+	// nil rows stay where they are, with the rows between each sorted on
+	// their own.
+	SortBy []SortKey
+	// Columns, if not empty, selects the columns to output, in the order
+	// given, such as for a --columns flag; the others are hidden. The per
+	// column options, such as Alignments and Widths, stay with their
+	// columns, and SortBy and Redact still refer to the columns of the
+	// data. Cells spanning columns are given just their first column.
+	Columns []int
+	// ColumnNames selects columns as Columns does, after any of those,
+	// by their text in the first header row, ignoring case; names not
+	// found are skipped.
+	ColumnNames []string
+	// Transpose swaps the rows and columns of the data, as the Transpose
+	// function does, before anything else, giving record style output from
+	// the data of a wide table; the header row becomes the first column.
+	// Cells spanning columns are given just their first column.
+	Transpose bool
+	// RowStyle, if not nil, is called with the index of each data row
+	// after the header rows, from 0, and not counting nil or footer rows,
+	// and returns the ANSI escape code to style the whole row with, or nil
+	// for none, such as for the stripes of WithZebra. The codes don't
+	// affect the widths; styled rows keep their trailing whitespace so
+	// background colors reach the end of the row.
+	RowStyle func(row int) []byte
+	// VerticalAlignments, indexed by column, place the lines of cells
+	// shorter than others of their row, such as Bottom to line up values
+	// with the last line of a wrapped description; columns beyond those
+	// given are Top.
+	VerticalAlignments []VerticalAlignment
+	// ValueFormatters, indexed by column, convert the values AlignAny is
+	// given in that column, other than those of the header rows, into
+	// cells, such as TimeValueFormatter(time.Kitchen); nil entries leave
+	// their columns to the Types.
+	ValueFormatters []ValueFormatter
+}
+
+// HeaderStyle gives the styling of header rows; see AlignOptions.HeaderStyle.
+type HeaderStyle struct {
+	Bold      bool
+	Underline bool
+	// Uppercase converts the header text, but not its ANSI escape codes, to
+	// upper case.
+	Uppercase bool
+	// Code, if not nil, is an ANSI escape code also applied, such as
+	// ANSIEscape.FCyan.
+	Code []byte
+}
+
+// line returns the line of a header cell styled.
+func (style *HeaderStyle) line(line string) string {
+	if style.Uppercase {
+		var b strings.Builder
+		for i := 0; i < len(line); {
+			if n := sgrLen(line[i:]); n > 0 {
+				b.WriteString(line[i : i+n])
+				i += n
+				continue
+			}
+			j := i + 1
+			for j < len(line) && line[j] != '\x1b' {
+				j++
+			}
+			b.WriteString(strings.ToUpper(line[i:j]))
+			i = j
+		}
+		line = b.String()
+	}
+	if line == "" {
+		return line
+	}
+	var codes string
+	if style.Bold {
+		codes += string(ANSIEscape.Bold)
+	}
+	if style.Underline {
+		codes += string(ANSIEscape.Underline)
+	}
+	codes += string(style.Code)
+	if codes == "" {
+		return line
+	}
+	return codes + line + string(ANSIEscape.Reset)
 }
 
 // NewDefaultAlignOptions gives:
@@ -277,8 +551,344 @@ func NewUnicodeBoxedAlignOptions() *AlignOptions {
 	}
 }
 
+// NewBorderAlignOptions expands a simple border description, one vertical
+// glyph, one horizontal glyph, one junction glyph, and how many spaces to pad
+// cells with, into a full AlignOptions; for example,
+// NewBorderAlignOptions("|", "-", "+", 1) gives the same as
+// NewSimpleAlignOptions.
+func NewBorderAlignOptions(vertical string, horizontal string, junction string, padding int) *AlignOptions {
+	if padding < 0 {
+		padding = 0
+	}
+	pad := strings.Repeat(" ", padding)
+	hpad := strings.Repeat(horizontal, padding)
+	return &AlignOptions{
+		FirstDR:                 junction + hpad,
+		FirstLR:                 horizontal,
+		FirstFirstDLR:           hpad + junction + hpad,
+		FirstDLR:                hpad + junction + hpad,
+		FirstDL:                 hpad + junction,
+		RowFirstUD:              vertical + pad,
+		RowSecondUD:             pad + vertical + pad,
+		RowUD:                   pad + vertical + pad,
+		RowLastUD:               pad + vertical,
+		LeaveTrailingWhitespace: true,
+		FirstNilFirstUDR:        junction + hpad,
+		FirstNilLR:              horizontal,
+		FirstNilFirstUDLR:       hpad + junction + hpad,
+		FirstNilUDLR:            hpad + junction + hpad,
+		FirstNilLastUDL:         hpad + junction,
+		LastUR:                  junction + hpad,
+		LastLR:                  horizontal,
+		LastFirstULR:            hpad + junction + hpad,
+		LastULR:                 hpad + junction + hpad,
+		LastUL:                  hpad + junction,
+	}
+}
+
+// NewPSQLAlignOptions gives options matching the default output of psql,
+// with centered headers and a "(N rows)" footer:
+//
+//   name  | count
+//  -------+-------
+//   alpha | 1
+//   beta  | 22
+//  (2 rows)
+//
+// Set RowCountFooter false to leave off the footer.
+func NewPSQLAlignOptions() *AlignOptions {
+	return &AlignOptions{
+		RowFirstUD:        " ",
+		RowSecondUD:       " | ",
+		RowUD:             " | ",
+		FirstNilFirstUDR:  "-",
+		FirstNilLR:        "-",
+		FirstNilFirstUDLR: "-+-",
+		FirstNilUDLR:      "-+-",
+		FirstNilLastUDL:   "-",
+		HeaderAlignments:  []Alignment{},
+		RowCountFooter:    true,
+	}
+}
+
+// NewMarkdownAlignOptions gives:
+//
+//  &AlignOptions{
+//      RowFirstUD:              "| ",
+//      RowSecondUD:             " | ",
+//      RowUD:                   " | ",
+//      RowLastUD:               " |",
+//      LeaveTrailingWhitespace: true,
+//      Markdown:                true,
+//  }
+//
+// Which will format tables, with Alignments of Left and Right, like:
+//
+//  | Name  | Count |
+//  | :---- | ----: |
+//  | alpha |     1 |
+//  | a\|b  |    22 |
+func NewMarkdownAlignOptions() *AlignOptions {
+	return &AlignOptions{
+		RowFirstUD:              "| ",
+		RowSecondUD:             " | ",
+		RowUD:                   " | ",
+		RowLastUD:               " |",
+		LeaveTrailingWhitespace: true,
+		Markdown:                true,
+	}
+}
+
+// alignPresets maps the names given by AlignPresets to their constructors.
+var alignPresets = map[string]func() *AlignOptions{
+	"default":       NewDefaultAlignOptions,
+	"markdown":      NewMarkdownAlignOptions,
+	"psql":          NewPSQLAlignOptions,
+	"simple":        NewSimpleAlignOptions,
+	"boxed":         NewBoxedAlignOptions,
+	"unicode-boxed": NewUnicodeBoxedAlignOptions,
+}
+
+// AlignPresets returns the sorted names of the built in AlignOptions presets,
+// such as "boxed", for listing table styles a user may choose from.
+func AlignPresets() []string {
+	names := make([]string, 0, len(alignPresets))
+	for name := range alignPresets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// AlignPreset returns a new AlignOptions for the named preset, as listed by
+// AlignPresets, or nil if there is no such preset. Names are matched
+// ignoring case, and "_" may be used in place of "-".
+func AlignPreset(name string) *AlignOptions {
+	if preset, ok := alignPresets[strings.Replace(strings.ToLower(name), "_", "-", -1)]; ok {
+		return preset()
+	}
+	return nil
+}
+
+// Clone returns a copy of the options that shares no slices, or HeatScales,
+// with the original, so it may be changed freely, such as tweaking a preset
+// for one call site while other renders use it concurrently.
+func (opts *AlignOptions) Clone() *AlignOptions {
+	if opts == nil {
+		return nil
+	}
+	clone := *opts
+	v := reflect.ValueOf(&clone).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		f := v.Field(i)
+		if f.Kind() == reflect.Slice && !f.IsNil() {
+			f.Set(reflect.AppendSlice(reflect.MakeSlice(f.Type(), 0, f.Len()), f))
+		}
+	}
+	for i, scale := range clone.HeatScales {
+		if scale != nil {
+			s := *scale
+			s.Colors = append([][]byte(nil), scale.Colors...)
+			clone.HeatScales[i] = &s
+		}
+	}
+	return &clone
+}
+
+// Merge sets each field of opts that is set in overrides, that is, not the
+// zero value, and returns opts; if opts is nil, a Clone of overrides is
+// returned instead. Slices are copied rather than shared. Since false is the
+// zero value, Merge cannot turn off a bool option; set it directly instead.
+// For example:
+//
+//  opts := brimtext.NewBoxedAlignOptions().Merge(&brimtext.AlignOptions{
+//      Alignments: []brimtext.Alignment{brimtext.Left, brimtext.Right},
+//  })
+func (opts *AlignOptions) Merge(overrides *AlignOptions) *AlignOptions {
+	if opts == nil {
+		return overrides.Clone()
+	}
+	if overrides == nil {
+		return opts
+	}
+	overrides = overrides.Clone()
+	dst := reflect.ValueOf(opts).Elem()
+	src := reflect.ValueOf(overrides).Elem()
+	for i := 0; i < src.NumField(); i++ {
+		if f := src.Field(i); !alignIsZero(f) {
+			dst.Field(i).Set(f)
+		}
+	}
+	return opts
+}
+
+func alignIsZero(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.String:
+		return v.Len() == 0
+	case reflect.Bool:
+		return !v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return v.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return v.Float() == 0
+	case reflect.Slice, reflect.Map, reflect.Ptr, reflect.Func, reflect.Interface:
+		return v.IsNil()
+	}
+	return false
+}
+
+// AlignOption configures AlignOptions for NewAlignOptions.
+type AlignOption func(opts *AlignOptions)
+
+// NewAlignOptions returns NewDefaultAlignOptions with each of the options
+// applied in order, so common configurations read as one line, such as:
+//
+//  opts := brimtext.NewAlignOptions(
+//      brimtext.WithBorders(brimtext.NewUnicodeBoxedAlignOptions()),
+//      brimtext.WithAlignments(brimtext.Left, brimtext.Right),
+//  )
+func NewAlignOptions(options ...AlignOption) *AlignOptions {
+	opts := NewDefaultAlignOptions()
+	for _, option := range options {
+		option(opts)
+	}
+	return opts
+}
+
+// WithWidths sets the desired widths of each column; see AlignOptions.Widths.
+func WithWidths(widths ...int) AlignOption {
+	return func(opts *AlignOptions) {
+		opts.Widths = append([]int(nil), widths...)
+	}
+}
+
+// WithAlignments sets the alignments of each column.
+func WithAlignments(alignments ...Alignment) AlignOption {
+	return func(opts *AlignOptions) {
+		opts.Alignments = append([]Alignment(nil), alignments...)
+	}
+}
+
+// WithBorders copies the border and separator settings of the style, such as
+// NewBoxedAlignOptions(), leaving the other options as they are.
+func WithBorders(style *AlignOptions) AlignOption {
+	return func(opts *AlignOptions) {
+		opts.FirstDR = style.FirstDR
+		opts.FirstLR = style.FirstLR
+		opts.FirstFirstDLR = style.FirstFirstDLR
+		opts.FirstDLR = style.FirstDLR
+		opts.FirstDL = style.FirstDL
+		opts.RowFirstUD = style.RowFirstUD
+		opts.RowSecondUD = style.RowSecondUD
+		opts.RowUD = style.RowUD
+		opts.RowLastUD = style.RowLastUD
+		opts.LeaveTrailingWhitespace = style.LeaveTrailingWhitespace
+		opts.FirstNilFirstUDR = style.FirstNilFirstUDR
+		opts.FirstNilLR = style.FirstNilLR
+		opts.FirstNilFirstUDLR = style.FirstNilFirstUDLR
+		opts.FirstNilUDLR = style.FirstNilUDLR
+		opts.FirstNilLastUDL = style.FirstNilLastUDL
+		opts.NilFirstUDR = style.NilFirstUDR
+		opts.NilLR = style.NilLR
+		opts.NilFirstUDLR = style.NilFirstUDLR
+		opts.NilUDLR = style.NilUDLR
+		opts.NilLastUDL = style.NilLastUDL
+		opts.FooterFirstUDR = style.FooterFirstUDR
+		opts.FooterLR = style.FooterLR
+		opts.FooterFirstUDLR = style.FooterFirstUDLR
+		opts.FooterUDLR = style.FooterUDLR
+		opts.FooterLastUDL = style.FooterLastUDL
+		opts.LastUR = style.LastUR
+		opts.LastLR = style.LastLR
+		opts.LastFirstULR = style.LastFirstULR
+		opts.LastULR = style.LastULR
+		opts.LastUL = style.LastUL
+		opts.NilBetweenEveryRow = style.NilBetweenEveryRow
+		opts.Markdown = style.Markdown
+	}
+}
+
+// WithRowSeparators sets whether a separator is output between every row;
+// see AlignOptions.NilBetweenEveryRow.
+func WithRowSeparators(between bool) AlignOption {
+	return func(opts *AlignOptions) {
+		opts.NilBetweenEveryRow = between
+	}
+}
+
+// WithHeatScales sets the HeatScales for each column; see
+// AlignOptions.HeatScales.
+func WithHeatScales(scales ...*HeatScale) AlignOption {
+	return func(opts *AlignOptions) {
+		opts.HeatScales = append([]*HeatScale(nil), scales...)
+	}
+}
+
+// WithFormatters sets the Formatters for each column; see
+// AlignOptions.Formatters.
+func WithFormatters(formatters ...ColumnFormatter) AlignOption {
+	return func(opts *AlignOptions) {
+		opts.Formatters = append([]ColumnFormatter(nil), formatters...)
+	}
+}
+
+// WithMaxWidths sets the MaxWidths of each column; see
+// AlignOptions.MaxWidths.
+func WithMaxWidths(widths ...int) AlignOption {
+	return func(opts *AlignOptions) {
+		opts.MaxWidths = append([]int(nil), widths...)
+	}
+}
+
+// WithColumns sets the Columns to output; see AlignOptions.Columns.
+func WithColumns(columns ...int) AlignOption {
+	return func(opts *AlignOptions) {
+		opts.Columns = append([]int(nil), columns...)
+	}
+}
+
+// WithZebra sets the RowStyle to style every other data row with the code,
+// starting with the second, such as ANSIEscape.BBlack or ANSIEscape.Dim,
+// making wide tables easier to follow across.
+func WithZebra(code []byte) AlignOption {
+	return func(opts *AlignOptions) {
+		opts.RowStyle = func(row int) []byte {
+			if row%2 == 1 {
+				return code
+			}
+			return nil
+		}
+	}
+}
+
+// WithSortBy sets the SortBy keys; see AlignOptions.SortBy.
+func WithSortBy(keys ...SortKey) AlignOption {
+	return func(opts *AlignOptions) {
+		opts.SortBy = append([]SortKey(nil), keys...)
+	}
+}
+
+// WithHeaderStyle sets the HeaderStyle; see AlignOptions.HeaderStyle.
+func WithHeaderStyle(style *HeaderStyle) AlignOption {
+	return func(opts *AlignOptions) {
+		opts.HeaderStyle = style
+	}
+}
+
+// WithWrapModes sets the WrapModes for each column; see
+// AlignOptions.WrapModes.
+func WithWrapModes(modes ...WrapMode) AlignOption {
+	return func(opts *AlignOptions) {
+		opts.WrapModes = append([]WrapMode(nil), modes...)
+	}
+}
+
 // Align will format a table according to options. If opts is nil,
-// NewDefaultAlignOptions is used.
+// NewDefaultAlignOptions is used. Any tabs within cells are expanded, see
+// ExpandTabs, before the column widths are measured.
 func Align(data [][]string, opts *AlignOptions) string {
 	if len(data) == 0 {
 		return ""
@@ -286,29 +896,254 @@ func Align(data [][]string, opts *AlignOptions) string {
 	if opts == nil {
 		opts = NewDefaultAlignOptions()
 	}
+	out, _ := alignContext(context.Background(), data, opts)
+	return out
+}
+
+// AlignContext is like Align but returns ctx.Err() and no output if ctx is
+// done before the table is rendered, so interactive programs can abandon a
+// slow render, such as when the user presses Ctrl-C or changes a filter.
+func AlignContext(ctx context.Context, data [][]string, opts *AlignOptions) (string, error) {
+	if len(data) == 0 {
+		return "", ctx.Err()
+	}
+	if opts == nil {
+		opts = NewDefaultAlignOptions()
+	}
+	return alignContext(ctx, data, opts)
+}
+
+// AlignTo is like Align but writes the table to w as it is rendered, rather
+// than returning it, so large tables needn't be held in memory as a whole
+// string; the number of bytes written and any error writing are returned. If
+// opts is nil, NewDefaultAlignOptions is used.
+func AlignTo(w io.Writer, data [][]string, opts *AlignOptions) (int, error) {
+	if len(data) == 0 {
+		return 0, nil
+	}
+	if opts == nil {
+		opts = NewDefaultAlignOptions()
+	}
+	layout, err := newAlignLayout(context.Background(), data, nil, nil, -1, opts)
+	if err != nil {
+		return 0, err
+	}
+	return alignRenderTo(context.Background(), w, layout, opts)
+}
+
+func alignContext(ctx context.Context, data [][]string, opts *AlignOptions) (string, error) {
+	layout, err := newAlignLayout(ctx, data, nil, nil, -1, opts)
+	if err != nil {
+		return "", err
+	}
+	return alignRender(ctx, layout, opts)
+}
+
+// AlignLayout is the resolved row model Align renders from, exposed so tests
+// and alternative renderers can share its layout logic.
+type AlignLayout struct {
+	// Rows are the data rows after rewrapping to AlignOptions.Widths and
+	// expanding multiline cells into one row per line; each row keeps the
+	// number of cells its data row had. Nil rows are separators, including
+	// those added by AlignOptions.NilBetweenEveryRow.
+	Rows [][]string
+	// Sources give the index of the data row each of Rows came from, or -1
+	// for added separators, whatever order AlignOptions.SortBy put the rows
+	// in. With AlignOptions.Transpose, they index the transposed rows.
+	Sources []int
+	// Widths are the computed display widths of each column, ignoring ANSI
+	// escape codes; see DisplayWidth.
+	Widths []int
+	// Alignments are the alignments of each column, filled out with Left.
+	Alignments []Alignment
+	// HeaderRows is how many of the data rows, from the start, are header
+	// rows: those before the first nil row, or just the first row with
+	// AlignOptions.NilBetweenEveryRow.
+	HeaderRows int
+	// FooterRows is how many of Rows, from the end, are lines of the footer
+	// rows; see AlignOptions.FooterRows.
+	FooterRows int
+	// RowAlignments, if not nil, has an entry for each of Rows; an entry
+	// that is not nil overrides Alignments for that row, such as for the
+	// continuation lines of cells or the last line of a Justify cell.
+	RowAlignments [][]Alignment
+	// Spans, if not nil, has an entry for each of Rows; an entry that is
+	// not nil gives the number of columns each cell of the row covers, with
+	// 0 for the cells covered by an earlier one, such as for the Cell
+	// ColSpan of AlignCells.
+	Spans [][]int
+}
+
+// NewAlignLayout returns the layout Align would render for the data and
+// opts, without rendering it. If opts is nil, NewDefaultAlignOptions is
+// used.
+func NewAlignLayout(data [][]string, opts *AlignOptions) *AlignLayout {
+	if opts == nil {
+		opts = NewDefaultAlignOptions()
+	}
+	layout, _ := newAlignLayout(context.Background(), data, nil, nil, -1, opts)
+	return layout
+}
+
+// newAlignLayout is NewAlignLayout, stopping with ctx.Err() if ctx is done.
+// The cellAligns, if not nil, override the alignments of the cells of the
+// data they are given for, other than those that are cellAlignUnset. The
+// cellSpans, if not nil, give the spans of the cells of the data they are
+// given for; see AlignLayout.Spans. The headerRows, if not < 0, is how many
+// of the data rows are header rows, rather than those before the first nil
+// row.
+func newAlignLayout(ctx context.Context, data [][]string, cellAligns [][]Alignment, cellSpans [][]int, headerRows int, opts *AlignOptions) (*AlignLayout, error) {
+	if opts.Transpose {
+		if cellAligns != nil {
+			cellAligns = transposeAligns(data, cellAligns)
+		}
+		data, cellSpans = Transpose(data), nil
+	}
+	if len(data) == 0 {
+		return &AlignLayout{}, ctx.Err()
+	}
+	if headerRows < 0 {
+		headerRows = alignHeaderRows(data, opts)
+	}
+	markdownHeader := false
+	if opts.Markdown && headerRows == 0 && !opts.NilBetweenEveryRow && len(data) > 1 && data[0] != nil {
+		data = append([][]string{data[0], nil}, data[1:]...)
+		if len(cellAligns) > 0 {
+			cellAligns = append([][]Alignment{cellAligns[0], nil}, cellAligns[1:]...)
+		}
+		if len(cellSpans) > 0 {
+			cellSpans = append([][]int{cellSpans[0], nil}, cellSpans[1:]...)
+		}
+		headerRows = 1
+		markdownHeader = true
+	}
+	footerStart := -1
+	for i, n := len(data)-1, 0; i >= 0 && n < opts.FooterRows; i-- {
+		if data[i] != nil {
+			footerStart = i
+			n++
+		}
+	}
+	var order []int
+	if len(opts.SortBy) > 0 {
+		order = alignSortOrder(data, opts.SortBy, headerRows, footerStart)
+		sorted := make([][]string, len(data))
+		for i, r := range order {
+			sorted[i] = data[r]
+		}
+		data = sorted
+		if cellAligns != nil {
+			sorted := make([][]Alignment, len(data))
+			for i, r := range order {
+				if r < len(cellAligns) {
+					sorted[i] = cellAligns[r]
+				}
+			}
+			cellAligns = sorted
+		}
+		if cellSpans != nil {
+			sorted := make([][]int, len(data))
+			for i, r := range order {
+				if r < len(cellSpans) {
+					sorted[i] = cellSpans[r]
+				}
+			}
+			cellSpans = sorted
+		}
+	}
+	if len(opts.Redact) > 0 || len(opts.RedactHeaders) > 0 {
+		data = applyRedaction(data, opts, headerRows)
+	}
+	if columns := alignColumns(data, opts, headerRows); columns != nil {
+		data, cellAligns, opts = selectColumns(data, cellAligns, columns, opts)
+		cellSpans = nil
+	}
+	values := data
+	if len(opts.Formatters) > 0 {
+		data = applyFormatters(data, opts.Formatters, headerRows)
+	}
+	if len(opts.HeatScales) > 0 {
+		data = applyHeatScales(data, values, opts.HeatScales)
+	}
+	if opts.NormalizeUnicode || opts.StripInvisible || opts.ControlChars != KeepControlChars {
+		data = alignSanitize(data, opts)
+	}
+	if opts.Markdown {
+		data = alignMarkdownEscape(data)
+	}
+	wrapWidths := opts.Widths
+	var fitted []int
+	if opts.Markdown {
+		wrapWidths = nil
+	} else if opts.MaxTableWidth > 0 || opts.WidthFor != nil || opts.FitWidth {
+		wrapWidths, fitted = alignFitWidths(alignUnspanned(data, cellSpans), opts)
+	}
 	newData := make([][]string, 0, len(data))
-	for _, row := range data {
+	sources := make([]int, 0, len(data))
+	var rowAlignments [][]Alignment
+	footerIndex := -1
+	for source, row := range data {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
 		if row == nil {
 			if !opts.NilBetweenEveryRow {
 				newData = append(newData, nil)
+				sources = append(sources, source)
 			}
 			continue
 		}
-		if opts.Widths != nil {
+		if wrapWidths != nil || len(opts.MaxWidths) > 0 {
 			newRow := make([]string, 0, len(row))
 			for col, cell := range row {
-				if col >= len(opts.Widths) || opts.Widths[col] <= 0 {
+				if alignSpan(cellSpans, source, col) > 1 {
 					newRow = append(newRow, cell)
 					continue
 				}
-				newRow = append(newRow, Wrap(cell, opts.Widths[col], "", ""))
+				mode := WrapWords
+				if col < len(opts.WrapModes) {
+					mode = opts.WrapModes[col]
+				}
+				indent := 0
+				if opts.ContinuationIndent != "" && col < len(opts.ContinuationAlignments) {
+					indent = DisplayWidth(opts.ContinuationIndent)
+				}
+				if col < len(wrapWidths) && wrapWidths[col] > 0 {
+					cell = alignWrapIndented(cell, wrapWidths[col], indent, mode, opts.Ellipsis)
+				}
+				if col < len(opts.MaxWidths) && opts.MaxWidths[col] > 0 {
+					if mode != WrapTruncateHead && mode != WrapTruncateMiddle {
+						mode = WrapTruncate
+					}
+					cell = alignWrapIndented(cell, opts.MaxWidths[col], indent, mode, opts.Ellipsis)
+				}
+				newRow = append(newRow, cell)
 			}
 			row = newRow
 		}
 		work := make([][]string, 0, len(row))
-		for _, cell := range row {
+		for col, cell := range row {
 			cell = strings.Replace(cell, "\r\n", "\n", -1)
-			work = append(work, strings.Split(cell, "\n"))
+			cell = ExpandTabs(breakHints.Replace(cell), 8)
+			lines := strings.Split(cell, "\n")
+			if opts.ContinuationIndent != "" && col < len(opts.ContinuationAlignments) {
+				for i := 1; i < len(lines); i++ {
+					if lines[i] != "" {
+						lines[i] = opts.ContinuationIndent + lines[i]
+					}
+				}
+			}
+			if opts.HeaderStyle != nil && source < headerRows {
+				for i, line := range lines {
+					lines[i] = opts.HeaderStyle.line(line)
+				}
+			}
+			if opts.FooterStyle != nil && footerStart >= 0 && source >= footerStart {
+				for i, line := range lines {
+					lines[i] = opts.FooterStyle.line(line)
+				}
+			}
+			work = append(work, lines)
 		}
 		maxCells := 0
 		for _, cells := range work {
@@ -318,34 +1153,142 @@ func Align(data [][]string, opts *AlignOptions) string {
 			}
 		}
 		newRows := make([][]string, 0)
-		if opts.NilBetweenEveryRow && len(newData) != 0 {
+		if source == footerStart {
+			footerIndex = len(newData)
+		} else if opts.NilBetweenEveryRow && len(newData) != 0 {
 			newData = append(newData, nil)
+			sources = append(sources, -1)
 		}
-		for c := 0; c < maxCells; c++ {
+		offsets := make([]int, len(work))
+		for col := range work {
+			if col < len(opts.VerticalAlignments) {
+				switch opts.VerticalAlignments[col] {
+				case Middle:
+					offsets[col] = (maxCells - len(work[col])) / 2
+				case Bottom:
+					offsets[col] = maxCells - len(work[col])
+				}
+			}
+		}
+		for line := 0; line < maxCells; line++ {
 			newRow := make([]string, 0, len(work))
+			var aligns []Alignment
 			for col := 0; col < len(work); col++ {
-				if c < len(work[col]) {
+				c := line - offsets[col]
+				if c >= 0 && c < len(work[col]) {
 					newRow = append(newRow, work[col][c])
 				} else {
 					newRow = append(newRow, "")
 				}
+				align, custom := Left, false
+				if col < len(opts.Alignments) {
+					align = opts.Alignments[col]
+				}
+				if source < headerRows && opts.HeaderAlignments != nil {
+					align, custom = Center, true
+					if col < len(opts.HeaderAlignments) {
+						align = opts.HeaderAlignments[col]
+					}
+				}
+				if c > 0 && col < len(opts.ContinuationAlignments) {
+					align, custom = opts.ContinuationAlignments[col], true
+				}
+				if source < len(cellAligns) && col < len(cellAligns[source]) && cellAligns[source][col] != cellAlignUnset {
+					align, custom = cellAligns[source][col], true
+				}
+				if align == Justify && c >= len(work[col])-1 {
+					align, custom = Left, true
+				}
+				if custom && aligns == nil {
+					aligns = make([]Alignment, len(work))
+					for i := range aligns {
+						aligns[i] = Left
+						if i < len(opts.Alignments) {
+							aligns[i] = opts.Alignments[i]
+						}
+					}
+				}
+				if aligns != nil {
+					aligns[col] = align
+				}
+			}
+			if aligns != nil || rowAlignments != nil {
+				for len(rowAlignments) < len(newData)+len(newRows) {
+					rowAlignments = append(rowAlignments, nil)
+				}
+				rowAlignments = append(rowAlignments, aligns)
 			}
 			newRows = append(newRows, newRow)
+			sources = append(sources, source)
 		}
 		newData = append(newData, newRows...)
 	}
-	data = newData
-	widths := make([]int, 0, len(data[0]))
-	for _, row := range data {
+	for col, align := range opts.Alignments {
+		if align == Decimal {
+			alignDecimal(newData, sources, headerRows, col)
+		}
+	}
+	var spans [][]int
+	if cellSpans != nil {
+		spans = make([][]int, len(newData))
+		for i, source := range sources {
+			if newData[i] != nil && source < len(cellSpans) {
+				spans[i] = cellSpans[source]
+			}
+		}
+	}
+	var widths []int
+	for i, row := range newData {
 		if row == nil {
 			continue
 		}
 		for len(row) > len(widths) {
-			widths = append(widths, RuneLenStripANSIEscapes(row[len(widths)]))
+			widths = append(widths, 0)
 		}
 		for c, v := range row {
-			if RuneLenStripANSIEscapes(v) > widths[c] {
-				widths[c] = RuneLenStripANSIEscapes(v)
+			if spans != nil && c < len(spans[i]) && spans[i][c] > 1 {
+				continue
+			}
+			if DisplayWidth(v) > widths[c] {
+				widths[c] = DisplayWidth(v)
+			}
+		}
+	}
+	for c, w := range fitted {
+		if c < len(widths) && w > widths[c] {
+			widths[c] = w
+		}
+	}
+	for i, row := range spans {
+		for c, n := range row {
+			if n < 2 || c+n > len(widths) {
+				continue
+			}
+			if extra := DisplayWidth(newData[i][c]) - alignSpanWidth(opts, widths, c, n); extra > 0 {
+				widths[c+n-1] += extra
+			}
+		}
+	}
+	if opts.Markdown {
+		for c, w := range widths {
+			if w < 3 {
+				widths[c] = 3
+			}
+		}
+	}
+	if order != nil {
+		for i, source := range sources {
+			if source >= 0 {
+				sources[i] = order[source]
+			}
+		}
+	}
+	if markdownHeader {
+		for i, source := range sources {
+			if source == 1 {
+				sources[i] = -1
+			} else if source > 1 {
+				sources[i] = source - 1
 			}
 		}
 	}
@@ -357,115 +1300,709 @@ func Align(data [][]string, opts *AlignOptions) string {
 		}
 		alignments = newal
 	}
-	est := RuneLenStripANSIEscapes(opts.RowFirstUD)
-	for _, w := range widths {
-		est += w + RuneLenStripANSIEscapes(opts.RowUD)
+	for rowAlignments != nil && len(rowAlignments) < len(newData) {
+		rowAlignments = append(rowAlignments, nil)
 	}
-	est += RuneLenStripANSIEscapes(opts.RowLastUD) + 1
-	est *= len(data)
-	buf := bytes.NewBuffer(make([]byte, 0, est))
-	if !AllEqual("", opts.FirstDR, opts.FirstFirstDLR, opts.FirstDLR, opts.FirstLR, opts.FirstDL) {
-		buf.WriteString(opts.FirstDR)
-		for col, width := range widths {
-			if col == 1 {
-				buf.WriteString(opts.FirstFirstDLR)
-			} else if col != 0 {
-				buf.WriteString(opts.FirstDLR)
+	footerRows := 0
+	if footerIndex >= 0 {
+		footerRows = len(newData) - footerIndex
+	}
+	return &AlignLayout{Rows: newData, Sources: sources, Widths: widths, Alignments: alignments, RowAlignments: rowAlignments, HeaderRows: headerRows, FooterRows: footerRows, Spans: spans}, nil
+}
+
+// alignSpan returns the span of the cell from the cellSpans given to
+// newAlignLayout, 1 if it has none.
+func alignSpan(cellSpans [][]int, row int, col int) int {
+	if row < len(cellSpans) && col < len(cellSpans[row]) {
+		return cellSpans[row][col]
+	}
+	return 1
+}
+
+// alignUnspanned returns the data with the cells spanning columns blanked,
+// for measuring the widths of the columns on their own.
+func alignUnspanned(data [][]string, cellSpans [][]int) [][]string {
+	if cellSpans == nil {
+		return data
+	}
+	out := make([][]string, len(data))
+	for r, row := range data {
+		if row == nil {
+			continue
+		}
+		out[r] = append([]string(nil), row...)
+		for c := range row {
+			if alignSpan(cellSpans, r, c) > 1 {
+				out[r][c] = ""
 			}
-			for i := 0; i < width; i++ {
-				buf.WriteString(opts.FirstLR)
+		}
+	}
+	return out
+}
+
+// alignSpanWidth returns the width of the content of a cell at column c
+// spanning n columns, including the padding and row separators of the
+// columns it covers.
+func alignSpanWidth(opts *AlignOptions, widths []int, c int, n int) int {
+	padding := opts.Padding
+	if padding < 0 {
+		padding = 0
+	}
+	width := widths[c]
+	for col := c + 1; col < c+n && col < len(widths); col++ {
+		width += widths[col] + 2*padding
+		if col == 1 {
+			width += DisplayWidth(opts.RowSecondUD)
+		} else {
+			width += DisplayWidth(opts.RowUD)
+		}
+	}
+	return width
+}
+
+// alignDecimal pads the cells of the column after the header rows with
+// spaces so their integer parts end at the same place once right aligned.
+func alignDecimal(rows [][]string, sources []int, headerRows int, col int) {
+	most := 0
+	for i, row := range rows {
+		if row != nil && col < len(row) && sources[i] >= headerRows {
+			if n := decimalFraction(row[col]); n > most {
+				most = n
 			}
 		}
-		buf.WriteString(opts.FirstDL)
-		buf.WriteByte('\n')
 	}
-	firstNil := true
-	for _, row := range data {
-		if row == nil {
-			if firstNil {
-				if !AllEqual("", opts.FirstNilFirstUDR, opts.FirstNilFirstUDLR, opts.FirstNilUDLR, opts.FirstNilLR, opts.FirstNilLastUDL) {
-					buf.WriteString(opts.FirstNilFirstUDR)
-					for col, width := range widths {
-						if col == 1 {
-							buf.WriteString(opts.FirstNilFirstUDLR)
-						} else if col != 0 {
-							buf.WriteString(opts.FirstNilUDLR)
-						}
-						for i := 0; i < width; i++ {
-							buf.WriteString(opts.FirstNilLR)
-						}
-					}
-					buf.WriteString(opts.FirstNilLastUDL)
-				}
-				firstNil = false
+	for i, row := range rows {
+		if row != nil && col < len(row) && sources[i] >= headerRows {
+			if n := decimalFraction(row[col]); n >= 0 && n < most {
+				row[col] += strings.Repeat(" ", most-n)
+			}
+		}
+	}
+}
+
+// decimalFraction returns how many columns of the cell, ignoring ANSI escape
+// codes and trailing spaces, follow the integer part of the first number in
+// it, which may have thousands separators, or -1 if it has no digits.
+func decimalFraction(cell string) int {
+	rs := []rune(strings.TrimRight(StripANSIEscapes(cell), " "))
+	i := 0
+	for i < len(rs) && (rs[i] < '0' || rs[i] > '9') {
+		i++
+	}
+	if i == len(rs) {
+		return -1
+	}
+	for i < len(rs) && (rs[i] >= '0' && rs[i] <= '9' || rs[i] == ',' && i+1 < len(rs) && rs[i+1] >= '0' && rs[i+1] <= '9') {
+		i++
+	}
+	return DisplayWidth(string(rs[i:]))
+}
+
+// alignWrapIndented is alignWrap with the second and subsequent lines fit to
+// indent fewer columns, at least 1, leaving room for a ContinuationIndent.
+func alignWrapIndented(cell string, width int, indent int, mode WrapMode, ellipsis string) string {
+	cell = alignWrap(cell, width, mode, ellipsis)
+	if indent < 1 {
+		return cell
+	}
+	i := strings.IndexByte(cell, '\n')
+	if i < 0 {
+		return cell
+	}
+	rest := width - indent
+	if rest < 1 {
+		rest = 1
+	}
+	return cell[:i+1] + alignWrap(cell[i+1:], rest, mode, ellipsis)
+}
+
+// alignWrap fits the cell to the width according to the mode, with the
+// ellipsis for the truncation modes.
+func alignWrap(cell string, width int, mode WrapMode, ellipsis string) string {
+	switch mode {
+	case WrapRunes, WrapTruncate, WrapTruncateHead, WrapTruncateMiddle:
+		cell = breakHints.Replace(ExpandTabs(strings.Replace(cell, "\r\n", "\n", -1), 8))
+		lines := strings.Split(cell, "\n")
+		out := make([]string, 0, len(lines))
+		for _, line := range lines {
+			if mode == WrapRunes {
+				out = append(out, wrapRunes(line, width)...)
 			} else {
-				if !AllEqual("", opts.NilFirstUDR, opts.NilFirstUDLR, opts.NilUDLR, opts.NilLR, opts.NilLastUDL) {
-					buf.WriteString(opts.NilFirstUDR)
-					for col, width := range widths {
-						if col == 1 {
-							buf.WriteString(opts.NilFirstUDLR)
-						} else if col != 0 {
-							buf.WriteString(opts.NilUDLR)
-						}
-						for i := 0; i < width; i++ {
-							buf.WriteString(opts.NilLR)
-						}
-					}
-					buf.WriteString(opts.NilLastUDL)
-				}
+				out = append(out, truncateText(line, width, mode, ellipsis))
+			}
+		}
+		return strings.Join(out, "\n")
+	}
+	return Wrap(cell, width, "", "")
+}
+
+// alignSanitize returns the data with each cell passed through the
+// NormalizeUnicode, SanitizeControl, and StripInvisible options.
+func alignSanitize(data [][]string, opts *AlignOptions) [][]string {
+	newData := make([][]string, len(data))
+	for r, row := range data {
+		if row == nil {
+			continue
+		}
+		newRow := make([]string, 0, len(row))
+		for _, cell := range row {
+			if opts.NormalizeUnicode {
+				cell = NormalizeUnicode(cell)
+			}
+			if opts.ControlChars != KeepControlChars {
+				cell = SanitizeControl(cell, opts.ControlChars)
+			}
+			if opts.StripInvisible {
+				cell = StripInvisible(cell)
 			}
-			buf.WriteByte('\n')
+			newRow = append(newRow, cell)
+		}
+		newData[r] = newRow
+	}
+	return newData
+}
+
+// alignMarkdownEscape returns the data with "|" escaped and newlines
+// replaced with "<br>" in each cell, for the Markdown option.
+func alignMarkdownEscape(data [][]string) [][]string {
+	newData := make([][]string, len(data))
+	for r, row := range data {
+		if row == nil {
 			continue
 		}
-		buf.WriteString(opts.RowFirstUD)
-		for c, v := range row {
-			if c == 1 {
-				buf.WriteString(opts.RowSecondUD)
-			} else if c != 0 {
-				buf.WriteString(opts.RowUD)
+		newRow := make([]string, 0, len(row))
+		for _, cell := range row {
+			cell = strings.Replace(cell, "|", "\\|", -1)
+			cell = strings.Replace(cell, "\r\n", "<br>", -1)
+			cell = strings.Replace(cell, "\n", "<br>", -1)
+			newRow = append(newRow, cell)
+		}
+		newData[r] = newRow
+	}
+	return newData
+}
+
+// alignFitWidths returns the widths to wrap each column of the data to,
+// 0 for those that need no wrapping, and the widths the columns should be at
+// least, according to the Widths, MaxTableWidth, and WidthFor options.
+func alignFitWidths(data [][]string, opts *AlignOptions) ([]int, []int) {
+	var natural []int
+	for _, row := range data {
+		for c, cell := range row {
+			for len(natural) <= c {
+				natural = append(natural, 0)
 			}
-			switch alignments[c] {
-			case Right:
-				for i := widths[c] - RuneLenStripANSIEscapes(v); i > 0; i-- {
-					buf.WriteRune(' ')
+			cell = ExpandTabs(breakHints.Replace(strings.Replace(cell, "\r\n", "\n", -1)), 8)
+			for _, line := range strings.Split(cell, "\n") {
+				if n := DisplayWidth(line); n > natural[c] {
+					natural[c] = n
 				}
-				buf.WriteString(v)
-			case Center:
-				for i := (widths[c] - RuneLenStripANSIEscapes(v)) / 2; i > 0; i-- {
-					buf.WriteRune(' ')
-				}
-				buf.WriteString(v)
-				if opts.LeaveTrailingWhitespace || c < len(row)-1 {
-					for i := widths[c] - ((widths[c]-RuneLenStripANSIEscapes(v))/2 + RuneLenStripANSIEscapes(v)); i > 0; i-- {
-						buf.WriteRune(' ')
-					}
+			}
+		}
+	}
+	for c := range natural {
+		if c < len(opts.Widths) && opts.Widths[c] > 0 && opts.Widths[c] < natural[c] {
+			natural[c] = opts.Widths[c]
+		}
+		if c < len(opts.MaxWidths) && opts.MaxWidths[c] > 0 && opts.MaxWidths[c] < natural[c] {
+			natural[c] = opts.MaxWidths[c]
+		}
+	}
+	available := -1
+	maxTableWidth := opts.MaxTableWidth
+	if maxTableWidth < 1 && opts.FitWidth {
+		maxTableWidth = alignTTYWidth() - 1
+	}
+	if maxTableWidth > 0 {
+		available = maxTableWidth - DisplayWidth(opts.Margin) - DisplayWidth(opts.RowFirstUD) - DisplayWidth(opts.RowLastUD)
+		for c := range natural {
+			available -= 2 * opts.Padding
+			if c == 1 {
+				available -= DisplayWidth(opts.RowSecondUD)
+			} else if c > 1 {
+				available -= DisplayWidth(opts.RowUD)
+			}
+		}
+	}
+	pinned := make([]int, len(natural))
+	fitted := alignFit(natural, pinned, available)
+	if opts.WidthFor != nil {
+		refit := false
+		for c := range natural {
+			if w := opts.WidthFor(c, natural[c], fitted[c]); w != fitted[c] {
+				if w < 1 {
+					w = 1
 				}
-			default:
-				buf.WriteString(v)
-				if opts.LeaveTrailingWhitespace || c < len(row)-1 {
-					for i := widths[c] - RuneLenStripANSIEscapes(v); i > 0; i-- {
-						buf.WriteRune(' ')
-					}
+				pinned[c] = w
+				refit = true
+			}
+		}
+		if refit {
+			fitted = alignFit(natural, pinned, available)
+		}
+	}
+	wrapWidths := make([]int, len(fitted))
+	for c, w := range fitted {
+		if w < natural[c] || (c < len(opts.Widths) && opts.Widths[c] > 0) {
+			wrapWidths[c] = w
+		}
+	}
+	return wrapWidths, fitted
+}
+
+// alignTTYWidth gives the terminal width for FitWidth.
+var alignTTYWidth = GetTTYWidth
+
+// alignFit returns the natural widths, or the pinned widths of the columns
+// that are > 0, with the widest of the other columns narrowed one at a time
+// until their total is no more than available, if available is >= 0, or
+// they are all 1 wide.
+func alignFit(natural []int, pinned []int, available int) []int {
+	widths := make([]int, len(natural))
+	total := 0
+	for c, w := range natural {
+		if pinned[c] > 0 {
+			w = pinned[c]
+		}
+		widths[c] = w
+		total += w
+	}
+	for available >= 0 && total > available {
+		widest := -1
+		for c, w := range widths {
+			if pinned[c] == 0 && w > 1 && (widest < 0 || w > widths[widest]) {
+				widest = c
+			}
+		}
+		if widest < 0 {
+			break
+		}
+		widths[widest]--
+		total--
+	}
+	return widths
+}
+
+// alignHeaderRows returns how many of the data rows, from the start, are
+// header rows: those before the first nil row, or just the first row with
+// NilBetweenEveryRow.
+func alignHeaderRows(data [][]string, opts *AlignOptions) int {
+	if opts.NilBetweenEveryRow {
+		if len(data) > 0 {
+			return 1
+		}
+		return 0
+	}
+	for i, row := range data {
+		if row == nil {
+			return i
+		}
+	}
+	return 0
+}
+
+func alignRender(ctx context.Context, layout *AlignLayout, opts *AlignOptions) (string, error) {
+	data, widths := layout.Rows, layout.Widths
+	if len(data) == 0 {
+		return "", ctx.Err()
+	}
+	est := DisplayWidth(opts.RowFirstUD)
+	for _, w := range widths {
+		est += w + DisplayWidth(opts.RowUD)
+	}
+	est += DisplayWidth(opts.RowLastUD) + 1
+	est *= len(data)
+	buf := bytes.NewBuffer(make([]byte, 0, est))
+	if _, err := alignRenderTo(ctx, buf, layout, opts); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// alignFlushSize is how much output alignRenderTo holds before writing it.
+const alignFlushSize = 32 * 1024
+
+// alignRenderTo writes the table for the layout to w, returning the number
+// of bytes written, stopping with ctx.Err() if ctx is done. Output is held
+// until there is alignFlushSize of it, unless w is a *bytes.Buffer, which is
+// written to directly.
+func alignRenderTo(ctx context.Context, w io.Writer, layout *AlignLayout, opts *AlignOptions) (int, error) {
+	data := layout.Rows
+	if len(data) == 0 {
+		return 0, ctx.Err()
+	}
+	buf, direct := w.(*bytes.Buffer)
+	if !direct {
+		buf = bytes.NewBuffer(make([]byte, 0, alignFlushSize*2))
+	}
+	n := 0
+	flush := func(force bool) error {
+		if direct || buf.Len() == 0 || (!force && buf.Len() < alignFlushSize) {
+			return nil
+		}
+		written, err := w.Write(buf.Bytes())
+		n += written
+		buf.Reset()
+		return err
+	}
+	r := newAlignRenderer(opts, layout.Widths, layout.Alignments)
+	footerIndex := len(data) - layout.FooterRows
+	last := -1
+	for i, source := range layout.Sources {
+		if i >= footerIndex {
+			break
+		}
+		if data[i] != nil && source >= layout.HeaderRows && source != last {
+			r.rowCount++
+			last = source
+		}
+	}
+	start := buf.Len()
+	r.below = layoutNextSpans(layout, 0)
+	r.first(buf)
+	stripe, stripeSource := -1, -1
+	for i, row := range data {
+		if err := ctx.Err(); err != nil {
+			return n, err
+		}
+		if opts.RowStyle != nil {
+			r.style = nil
+			if source := layout.Sources[i]; row != nil && source >= layout.HeaderRows && i < footerIndex {
+				if source != stripeSource {
+					stripe++
+					stripeSource = source
 				}
+				r.style = opts.RowStyle(stripe)
 			}
 		}
-		buf.WriteString(opts.RowLastUD)
-		buf.WriteByte('\n')
+		r.below = layoutNextSpans(layout, i)
+		if i == footerIndex && i > 0 {
+			r.footer(buf)
+		}
+		r.row(buf, row, layoutRowAlignments(layout, i), layoutRowSpans(layout, i))
+		if err := flush(false); err != nil {
+			return n, err
+		}
+	}
+	r.last(buf)
+	if direct {
+		return buf.Len() - start, nil
+	}
+	return n, flush(true)
+}
+
+// alignRenderer outputs the parts of a table for the widths and alignments
+// resolved by NewAlignLayout, letting a table be output all at once or a
+// row at a time.
+type alignRenderer struct {
+	opts       *AlignOptions
+	widths     []int
+	alignments []Alignment
+	firstNil   bool
+	// rowCount is the number of data rows for the RowCountFooter.
+	rowCount int
+	// resetCells is set for the ResetCells option or its implied uses.
+	resetCells bool
+	// above and below are the spans, as with AlignLayout.Spans, of the rows
+	// above and below the next separator line, for its junctions.
+	above []int
+	below []int
+	// style is the RowStyle code for the next row.
+	style []byte
+}
+
+func newAlignRenderer(opts *AlignOptions, widths []int, alignments []Alignment) *alignRenderer {
+	resetCells := opts.ResetCells || len(opts.HeatScales) > 0 || opts.Highlight != nil || opts.HeaderStyle != nil || opts.FooterStyle != nil || opts.RowStyle != nil
+	return &alignRenderer{opts: opts, widths: widths, alignments: alignments, firstNil: true, resetCells: resetCells}
+}
+
+// justifyLine widens the spaces between the words of v so it fills width.
+func justifyLine(v string, width int) string {
+	words := strings.Fields(v)
+	if len(words) < 2 {
+		return v
+	}
+	extra := width
+	for _, word := range words {
+		extra -= DisplayWidth(word)
+	}
+	gaps := len(words) - 1
+	if extra < gaps {
+		return v
+	}
+	var buf bytes.Buffer
+	for i, word := range words {
+		if i > 0 {
+			n := extra / gaps
+			if i <= extra%gaps {
+				n++
+			}
+			buf.WriteString(strings.Repeat(" ", n))
+		}
+		buf.WriteString(word)
+	}
+	return buf.String()
+}
+
+// line outputs a border or separator line, if any of its parts are set. The
+// junctions are adjusted to the spans of the rows above and below, with nil
+// meaning no spans; above and below should be the same for the top and
+// bottom borders.
+func (r *alignRenderer) line(buf *bytes.Buffer, first, fill, firstJoin, join, last string, above, below []int) bool {
+	if AllEqual("", first, firstJoin, join, fill, last) {
+		return false
 	}
-	if !AllEqual("", opts.LastUR, opts.LastFirstULR, opts.LastULR, opts.LastLR, opts.LastUL) {
-		buf.WriteString(opts.LastUR)
-		for col, width := range widths {
+	buf.WriteString(r.opts.Margin)
+	buf.WriteString(first)
+	for col, width := range r.widths {
+		if col != 0 {
+			j := join
 			if col == 1 {
-				buf.WriteString(opts.LastFirstULR)
-			} else if col != 0 {
-				buf.WriteString(opts.LastULR)
+				j = firstJoin
 			}
-			for i := 0; i < width; i++ {
-				buf.WriteString(opts.LastLR)
+			buf.WriteString(spanJunction(j, fill, spanSplits(above, col), spanSplits(below, col)))
+		}
+		for i := 0; i < width+2*r.padding(); i++ {
+			buf.WriteString(fill)
+		}
+	}
+	buf.WriteString(last)
+	return true
+}
+
+// markdownDelimiters outputs the Markdown delimiter row, its colons giving
+// the alignments of the columns.
+func (r *alignRenderer) markdownDelimiters(buf *bytes.Buffer) {
+	opts := r.opts
+	buf.WriteString(opts.Margin)
+	buf.WriteString(opts.RowFirstUD)
+	pad := strings.Repeat(" ", r.padding())
+	for col, width := range r.widths {
+		if col == 1 {
+			buf.WriteString(opts.RowSecondUD)
+		} else if col != 0 {
+			buf.WriteString(opts.RowUD)
+		}
+		buf.WriteString(pad)
+		switch r.alignments[col] {
+		case Right, Decimal:
+			buf.WriteString(strings.Repeat("-", width-1) + ":")
+		case Center:
+			buf.WriteString(":" + strings.Repeat("-", width-2) + ":")
+		default:
+			buf.WriteString(":" + strings.Repeat("-", width-1))
+		}
+		buf.WriteString(pad)
+	}
+	buf.WriteString(opts.RowLastUD)
+	buf.WriteByte('\n')
+}
+
+// spanJunctionsUp and spanJunctionsDown replace the box drawing junctions
+// of lines with those for when only the row above, or below, has a
+// separator at the junction.
+var (
+	spanJunctionsUp   = strings.NewReplacer("\u253c", "\u2534", "\u256a", "\u2567", "\u256c", "\u2569", "\u256b", "\u2568")
+	spanJunctionsDown = strings.NewReplacer("\u253c", "\u252c", "\u256a", "\u2564", "\u256c", "\u2566", "\u256b", "\u2565")
+)
+
+// spanJunction returns the junction of a line where up and down say whether
+// the rows above and below have separators.
+func spanJunction(join string, fill string, up bool, down bool) string {
+	switch {
+	case up && down:
+		return join
+	case up:
+		return spanJunctionsUp.Replace(join)
+	case down:
+		return spanJunctionsDown.Replace(join)
+	}
+	if fill == "" {
+		fill = " "
+	}
+	return strings.Repeat(fill, DisplayWidth(join))
+}
+
+// spanSplits returns true if a row with the spans has a separator before
+// the column.
+func spanSplits(spans []int, col int) bool {
+	return col >= len(spans) || spans[col] != 0
+}
+
+func (r *alignRenderer) padding() int {
+	if r.opts.Padding < 0 {
+		return 0
+	}
+	return r.opts.Padding
+}
+
+// tableWidth returns the display width of the table's lines, not counting
+// the Margin.
+func (r *alignRenderer) tableWidth() int {
+	opts := r.opts
+	width := DisplayWidth(opts.RowFirstUD) + DisplayWidth(opts.RowLastUD)
+	for col, w := range r.widths {
+		width += w + 2*r.padding()
+		if col == 1 {
+			width += DisplayWidth(opts.RowSecondUD)
+		} else if col > 1 {
+			width += DisplayWidth(opts.RowUD)
+		}
+	}
+	return width
+}
+
+// text outputs the Title or Caption wrapped to the table's width, centering
+// each line if center is true.
+func (r *alignRenderer) text(buf *bytes.Buffer, text string, center bool) {
+	width := r.tableWidth()
+	if width < 1 {
+		width = 1
+	}
+	for _, line := range strings.Split(Wrap(text, width, "", ""), "\n") {
+		buf.WriteString(r.opts.Margin)
+		if pad := (width - DisplayWidth(line)) / 2; center && pad > 0 {
+			buf.WriteString(strings.Repeat(" ", pad))
+		}
+		buf.WriteString(line)
+		buf.WriteByte('\n')
+	}
+}
+
+func (r *alignRenderer) first(buf *bytes.Buffer) {
+	opts := r.opts
+	if opts.Title != "" {
+		r.text(buf, opts.Title, true)
+	}
+	if r.line(buf, opts.FirstDR, opts.FirstLR, opts.FirstFirstDLR, opts.FirstDLR, opts.FirstDL, r.below, r.below) {
+		buf.WriteByte('\n')
+	}
+}
+
+// footer outputs the separator before the footer rows.
+func (r *alignRenderer) footer(buf *bytes.Buffer) {
+	opts := r.opts
+	if opts.Markdown {
+		return
+	}
+	if AllEqual("", opts.FooterFirstUDR, opts.FooterLR, opts.FooterFirstUDLR, opts.FooterUDLR, opts.FooterLastUDL) {
+		r.line(buf, opts.FirstNilFirstUDR, opts.FirstNilLR, opts.FirstNilFirstUDLR, opts.FirstNilUDLR, opts.FirstNilLastUDL, r.above, r.below)
+	} else {
+		r.line(buf, opts.FooterFirstUDR, opts.FooterLR, opts.FooterFirstUDLR, opts.FooterUDLR, opts.FooterLastUDL, r.above, r.below)
+	}
+	buf.WriteByte('\n')
+}
+
+func (r *alignRenderer) last(buf *bytes.Buffer) {
+	opts := r.opts
+	if r.line(buf, opts.LastUR, opts.LastLR, opts.LastFirstULR, opts.LastULR, opts.LastUL, r.above, r.above) {
+		buf.WriteByte('\n')
+	}
+	if opts.RowCountFooter {
+		buf.WriteString(opts.Margin)
+		buf.WriteString("(" + strconv.Itoa(r.rowCount) + " " + Plural(r.rowCount, "row", "") + ")\n")
+	}
+	if opts.Caption != "" {
+		r.text(buf, opts.Caption, false)
+	}
+}
+
+// row outputs the row; aligns, if not nil, overrides the column alignments,
+// and spans, if not nil, gives the columns each cell covers, as with
+// AlignLayout.Spans.
+func (r *alignRenderer) row(buf *bytes.Buffer, row []string, aligns []Alignment, spans []int) {
+	opts := r.opts
+	widths, alignments := r.widths, r.alignments
+	if aligns != nil {
+		alignments = aligns
+	}
+	if row == nil {
+		if opts.Markdown {
+			if r.firstNil {
+				r.markdownDelimiters(buf)
+				r.firstNil = false
 			}
+			return
+		}
+		if r.firstNil {
+			r.line(buf, opts.FirstNilFirstUDR, opts.FirstNilLR, opts.FirstNilFirstUDLR, opts.FirstNilUDLR, opts.FirstNilLastUDL, r.above, r.below)
+			r.firstNil = false
+		} else {
+			r.line(buf, opts.NilFirstUDR, opts.NilLR, opts.NilFirstUDLR, opts.NilUDLR, opts.NilLastUDL, r.above, r.below)
 		}
-		buf.WriteString(opts.LastUL)
 		buf.WriteByte('\n')
+		return
 	}
-	return buf.String()
+	r.above = spans
+	buf.WriteString(opts.Margin)
+	buf.WriteString(opts.RowFirstUD)
+	pad := strings.Repeat(" ", r.padding())
+	style, reset := string(r.style), string(ANSIEscape.Reset)
+	trailing := opts.LeaveTrailingWhitespace || style != ""
+	buf.WriteString(style)
+	for c := 0; c < len(row); c++ {
+		v := row[c]
+		width, n := widths[c], 1
+		if c < len(spans) && spans[c] > 1 {
+			n = spans[c]
+			if c+n > len(row) {
+				n = len(row) - c
+			}
+			width = alignSpanWidth(opts, widths, c, n)
+		}
+		more := c+n < len(row)
+		if c == 1 {
+			buf.WriteString(opts.RowSecondUD)
+		} else if c != 0 {
+			buf.WriteString(opts.RowUD)
+		}
+		buf.WriteString(pad)
+		if opts.Highlight != nil {
+			v = Highlight(v, opts.Highlight, opts.HighlightCode)
+		}
+		align := alignments[c]
+		if align == Justify {
+			v = justifyLine(v, width)
+			align = Left
+		}
+		if r.resetCells && sgrUnterminated(v) {
+			v += reset
+		}
+		if style != "" {
+			v = strings.Replace(v, reset, reset+style, -1)
+		}
+		switch align {
+		case Right, Decimal:
+			for i := width - DisplayWidth(v); i > 0; i-- {
+				buf.WriteRune(' ')
+			}
+			buf.WriteString(v)
+		case Center:
+			for i := (width - DisplayWidth(v)) / 2; i > 0; i-- {
+				buf.WriteRune(' ')
+			}
+			buf.WriteString(v)
+			if trailing || more {
+				for i := width - ((width-DisplayWidth(v))/2 + DisplayWidth(v)); i > 0; i-- {
+					buf.WriteRune(' ')
+				}
+			}
+		default:
+			buf.WriteString(v)
+			if trailing || more {
+				for i := width - DisplayWidth(v); i > 0; i-- {
+					buf.WriteRune(' ')
+				}
+			}
+		}
+		if trailing || more {
+			buf.WriteString(pad)
+		}
+		c += n - 1
+	}
+	if style != "" {
+		buf.WriteString(reset)
+	}
+	buf.WriteString(opts.RowLastUD)
+	buf.WriteByte('\n')
 }
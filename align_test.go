@@ -1,7 +1,12 @@
 package brimtext_test
 
 import (
+	"bytes"
+	"context"
 	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
 	"testing"
 
 	"github.com/gholt/brimtext"
@@ -20,13 +25,13 @@ func TestAlignUnicode(t *testing.T) {
 		[]string{"", "one", "two", "three"},
 		[]string{"a", "one a b \u0041 \u00c0 \uff21 \U0001d400 d efg", "two abc d ef \u0041 \u00c0 \uff21 \U0001d400", "three a \u0041 \u00c0 \uff21 \U0001d400 bcd efg hij"},
 	}, opts)
-	exp := `>>> ||  one||       two||three<<<
->>>a||one a|| two abc d||three<<<
->>> ||b A À||ef A À Ａ 𝐀||a A À<<<
->>> ||Ａ 𝐀 d||          ||Ａ 𝐀  <<<
->>> ||  efg||          ||bcd  <<<
->>> ||     ||          ||efg  <<<
->>> ||     ||          ||hij  <<<
+	exp := `>>> ||  one||      two||three<<<
+>>>a||one a||two abc d||three<<<
+>>> ||b A À||ef A À Ａ||a A À<<<
+>>> || Ａ 𝐀||        𝐀||Ａ 𝐀 <<<
+>>> ||d efg||         ||bcd  <<<
+>>> ||     ||         ||efg  <<<
+>>> ||     ||         ||hij  <<<
 `
 	if out != exp {
 		t.Errorf("%#v != %#v", out, exp)
@@ -388,3 +393,917 @@ func ExampleAlign_unicodeCustom() {
 	// ║ Shooting Stars │     19 │       7 ║
 	// ╚════════════════╧════════╧═════════╝
 }
+
+func TestNewAlignLayout(t *testing.T) {
+	opts := brimtext.NewBoxedAlignOptions()
+	opts.Alignments = []brimtext.Alignment{brimtext.Right}
+	layout := brimtext.NewAlignLayout([][]string{
+		[]string{"", "Bob"},
+		nil,
+		[]string{"Home", "San\nAntonio", "TX"},
+		[]string{"Mother", "Bessie"},
+	}, opts)
+	exp := &brimtext.AlignLayout{
+		Rows: [][]string{
+			[]string{"", "Bob"},
+			nil,
+			[]string{"Home", "San", "TX"},
+			[]string{"", "Antonio", ""},
+			nil,
+			[]string{"Mother", "Bessie"},
+		},
+		Sources:    []int{0, -1, 2, 2, -1, 3},
+		Widths:     []int{6, 7, 2},
+		Alignments: []brimtext.Alignment{brimtext.Right, brimtext.Left, brimtext.Left},
+		HeaderRows: 1,
+	}
+	if !reflect.DeepEqual(layout, exp) {
+		t.Errorf("%#v != %#v", layout, exp)
+	}
+	layout = brimtext.NewAlignLayout([][]string{[]string{"a"}, nil, []string{"b"}}, nil)
+	if !reflect.DeepEqual(layout.Sources, []int{0, 1, 2}) {
+		t.Errorf("%#v", layout.Sources)
+	}
+	layout = brimtext.NewAlignLayout(nil, nil)
+	if layout.Rows != nil || layout.Widths != nil {
+		t.Errorf("%#v", layout)
+	}
+}
+
+func TestNewAlignOptions(t *testing.T) {
+	if !reflect.DeepEqual(brimtext.NewAlignOptions(), brimtext.NewDefaultAlignOptions()) {
+		t.Errorf("%#v", brimtext.NewAlignOptions())
+	}
+	widths := []int{5, 0}
+	opts := brimtext.NewAlignOptions(
+		brimtext.WithBorders(brimtext.NewBoxedAlignOptions()),
+		brimtext.WithWidths(widths...),
+		brimtext.WithAlignments(brimtext.Left, brimtext.Right),
+		brimtext.WithRowSeparators(false),
+	)
+	exp := brimtext.NewBoxedAlignOptions()
+	exp.Widths = []int{5, 0}
+	exp.Alignments = []brimtext.Alignment{brimtext.Left, brimtext.Right}
+	exp.NilBetweenEveryRow = false
+	if !reflect.DeepEqual(opts, exp) {
+		t.Errorf("%#v != %#v", opts, exp)
+	}
+	widths[0] = 9
+	if opts.Widths[0] != 5 {
+		t.Errorf("WithWidths shared the slice: %#v", opts.Widths)
+	}
+	out := brimtext.Align([][]string{
+		[]string{"Name", "Count"},
+		nil,
+		[]string{"alpha", "7"},
+	}, opts)
+	expOut := `+=======+=======+
+| Name  | Count |
++=======+=======+
+| alpha |     7 |
++=======+=======+
+`
+	if out != expOut {
+		t.Errorf("%#v != %#v", out, expOut)
+	}
+	opts = brimtext.NewAlignOptions(brimtext.WithHeatScales(nil, brimtext.NewHeatScale()))
+	if len(opts.HeatScales) != 2 || opts.HeatScales[0] != nil || opts.HeatScales[1] == nil {
+		t.Errorf("%#v", opts.HeatScales)
+	}
+}
+
+func TestAlignOptionsClone(t *testing.T) {
+	opts := brimtext.NewSimpleAlignOptions()
+	opts.Widths = []int{3, 4}
+	opts.Alignments = []brimtext.Alignment{brimtext.Right}
+	opts.HeatScales = []*brimtext.HeatScale{brimtext.NewHeatScale()}
+	clone := opts.Clone()
+	if !reflect.DeepEqual(opts, clone) {
+		t.Errorf("%#v != %#v", clone, opts)
+	}
+	clone.Widths[0] = 9
+	clone.Alignments[0] = brimtext.Center
+	clone.HeatScales[0].Min = 5
+	clone.RowUD = "!"
+	if opts.Widths[0] != 3 || opts.Alignments[0] != brimtext.Right || opts.HeatScales[0].Min != 0 || opts.RowUD != " | " {
+		t.Errorf("Clone shared state: %#v", opts)
+	}
+	var nilOpts *brimtext.AlignOptions
+	if nilOpts.Clone() != nil {
+		t.Errorf("nil Clone not nil")
+	}
+}
+
+func TestAlignOptionsMerge(t *testing.T) {
+	alignments := []brimtext.Alignment{brimtext.Left, brimtext.Right}
+	opts := brimtext.NewBoxedAlignOptions().Merge(&brimtext.AlignOptions{
+		Alignments: alignments,
+		RowUD:      " : ",
+	})
+	exp := brimtext.NewBoxedAlignOptions()
+	exp.Alignments = []brimtext.Alignment{brimtext.Left, brimtext.Right}
+	exp.RowUD = " : "
+	if !reflect.DeepEqual(opts, exp) {
+		t.Errorf("%#v != %#v", opts, exp)
+	}
+	alignments[0] = brimtext.Center
+	if opts.Alignments[0] != brimtext.Left {
+		t.Errorf("Merge shared the slice: %#v", opts.Alignments)
+	}
+	if !opts.Merge(&brimtext.AlignOptions{NilBetweenEveryRow: false}).NilBetweenEveryRow {
+		t.Errorf("Merge cleared a bool")
+	}
+	if opts.Merge(nil) != opts {
+		t.Errorf("Merge(nil) did not return opts")
+	}
+	var none *brimtext.AlignOptions
+	if merged := none.Merge(opts); merged == opts || !reflect.DeepEqual(merged, opts) {
+		t.Errorf("nil Merge %#v != %#v", merged, opts)
+	}
+}
+
+func TestAlignPresets(t *testing.T) {
+	names := brimtext.AlignPresets()
+	exp := []string{"boxed", "default", "markdown", "psql", "simple", "unicode-boxed"}
+	if !reflect.DeepEqual(names, exp) {
+		t.Errorf("%#v != %#v", names, exp)
+	}
+	for _, name := range names {
+		if brimtext.AlignPreset(name) == nil {
+			t.Errorf("AlignPreset(%#v) was nil", name)
+		}
+	}
+	if !reflect.DeepEqual(brimtext.AlignPreset("Unicode_Boxed"), brimtext.NewUnicodeBoxedAlignOptions()) {
+		t.Errorf("AlignPreset(\"Unicode_Boxed\") mismatch")
+	}
+	if brimtext.AlignPreset("fancy") != nil {
+		t.Errorf("AlignPreset(\"fancy\") was not nil")
+	}
+	a := brimtext.AlignPreset("simple")
+	a.RowUD = "!"
+	if brimtext.AlignPreset("simple").RowUD != " | " {
+		t.Errorf("AlignPreset shared state")
+	}
+}
+
+func TestNewPSQLAlignOptions(t *testing.T) {
+	data := [][]string{
+		[]string{"name", "count"},
+		nil,
+		[]string{"alpha", "1"},
+		[]string{"beta", "22"},
+	}
+	out := brimtext.Align(data, brimtext.NewPSQLAlignOptions())
+	exp := " name  | count\n" +
+		"-------+-------\n" +
+		" alpha | 1\n" +
+		" beta  | 22\n" +
+		"(2 rows)\n"
+	if out != exp {
+		t.Errorf("%#v != %#v", out, exp)
+	}
+	out = brimtext.Align(data[:3], brimtext.NewPSQLAlignOptions())
+	exp = " name  | count\n" +
+		"-------+-------\n" +
+		" alpha | 1\n" +
+		"(1 row)\n"
+	if out != exp {
+		t.Errorf("%#v != %#v", out, exp)
+	}
+}
+
+func TestAlignContinuationAlignments(t *testing.T) {
+	opts := brimtext.NewAlignOptions(
+		brimtext.WithWidths(0, 12),
+		brimtext.WithAlignments(brimtext.Left, brimtext.Right),
+	)
+	opts.ContinuationAlignments = []brimtext.Alignment{brimtext.Left, brimtext.Left}
+	opts.ContinuationIndent = "  "
+	opts.LeaveTrailingWhitespace = true
+	out := brimtext.Align([][]string{
+		[]string{"Item", "Description"},
+		[]string{"a", "a short one"},
+		[]string{"b", "this one wraps onto more lines"},
+	}, opts)
+	exp := "Item  Description\n" +
+		"a     a short one\n" +
+		"b        this one\n" +
+		"       wraps onto\n" +
+		"       more lines\n"
+	if out != exp {
+		t.Errorf("%#v != %#v", out, exp)
+	}
+	opts = brimtext.NewAlignOptions(
+		brimtext.WithBorders(brimtext.NewSimpleAlignOptions()),
+		brimtext.WithWidths(0, 10),
+	)
+	opts.ContinuationAlignments = []brimtext.Alignment{brimtext.Left, brimtext.Left}
+	opts.ContinuationIndent = "    "
+	out = brimtext.Align([][]string{
+		[]string{"b", "this one wraps onto more lines"},
+	}, opts)
+	for _, line := range strings.Split(strings.TrimSuffix(out, "\n"), "\n") {
+		if w := brimtext.DisplayWidth(line); w > len("| b | ")+10+len(" |") {
+			t.Errorf("%#v is %d columns, wider than the column's 10", line, w)
+		}
+	}
+	exp = "+---+-----------+\n" +
+		"| b | this one  |\n" +
+		"|   |     wraps |\n" +
+		"|   |     onto  |\n" +
+		"|   |     more  |\n" +
+		"|   |     lines |\n" +
+		"+---+-----------+\n"
+	if out != exp {
+		t.Errorf("%#v != %#v", out, exp)
+	}
+}
+
+func TestAlignJustify(t *testing.T) {
+	opts := brimtext.NewAlignOptions(
+		brimtext.WithBorders(brimtext.NewSimpleAlignOptions()),
+		brimtext.WithWidths(0, 20),
+		brimtext.WithAlignments(brimtext.Left, brimtext.Justify),
+	)
+	out := brimtext.Align([][]string{
+		[]string{"x", "Lorem ipsum dolor sit amet, consectetur adipiscing elit."},
+		[]string{"y", "Short."},
+	}, opts)
+	exp := `+---+-------------------+
+| x | Lorem ipsum dolor |
+|   | sit         amet, |
+|   | consectetur       |
+|   | adipiscing elit.  |
+| y | Short.            |
++---+-------------------+
+`
+	if out != exp {
+		t.Errorf("%#v != %#v", out, exp)
+	}
+	layout := brimtext.NewAlignLayout([][]string{[]string{"a", "b"}}, brimtext.NewAlignOptions())
+	if layout.RowAlignments != nil {
+		t.Errorf("%#v", layout.RowAlignments)
+	}
+}
+
+func TestNewBorderAlignOptions(t *testing.T) {
+	if !reflect.DeepEqual(brimtext.NewBorderAlignOptions("|", "-", "+", 1), brimtext.NewSimpleAlignOptions()) {
+		t.Errorf("%#v != %#v", brimtext.NewBorderAlignOptions("|", "-", "+", 1), brimtext.NewSimpleAlignOptions())
+	}
+	data := [][]string{
+		[]string{"a", "bb"},
+		nil,
+		[]string{"ccc", "d"},
+	}
+	out := brimtext.Align(data, brimtext.NewBorderAlignOptions("│", "─", "┼", 2))
+	exp := `┼───────┼──────┼
+│  a    │  bb  │
+┼───────┼──────┼
+│  ccc  │  d   │
+┼───────┼──────┼
+`
+	if out != exp {
+		t.Errorf("%#v != %#v", out, exp)
+	}
+	out = brimtext.Align(data, brimtext.NewBorderAlignOptions("|", "", "", 0))
+	exp = `|a  |bb|
+
+|ccc|d |
+`
+	if out != exp {
+		t.Errorf("%#v != %#v", out, exp)
+	}
+}
+
+func TestAlignMarginPadding(t *testing.T) {
+	data := [][]string{
+		[]string{"a", "bb"},
+		nil,
+		[]string{"ccc", "d"},
+	}
+	opts := brimtext.NewSimpleAlignOptions()
+	opts.Margin = "    "
+	opts.Padding = 1
+	out := brimtext.Align(data, opts)
+	exp := `    +-------+------+
+    |  a    |  bb  |
+    +-------+------+
+    |  ccc  |  d   |
+    +-------+------+
+`
+	if out != exp {
+		t.Errorf("%#v != %#v", out, exp)
+	}
+	opts = brimtext.NewDefaultAlignOptions()
+	opts.Margin = "> "
+	opts.Padding = 2
+	out = brimtext.Align(data, opts)
+	exp = ">   a       bb\n" +
+		"\n" +
+		">   ccc     d\n"
+	if out != exp {
+		t.Errorf("%#v != %#v", out, exp)
+	}
+}
+
+func TestAlignHeaderAlignments(t *testing.T) {
+	data := [][]string{
+		[]string{"Name", "Count"},
+		nil,
+		[]string{"alpha", "1"},
+		[]string{"beta", "22222"},
+	}
+	opts := brimtext.NewAlignOptions(
+		brimtext.WithBorders(brimtext.NewSimpleAlignOptions()),
+		brimtext.WithAlignments(brimtext.Left, brimtext.Right),
+	)
+	opts.HeaderAlignments = []brimtext.Alignment{}
+	out := brimtext.Align(data, opts)
+	exp := `+-------+-------+
+| Name  | Count |
++-------+-------+
+| alpha |     1 |
+| beta  | 22222 |
++-------+-------+
+`
+	if out != exp {
+		t.Errorf("%#v != %#v", out, exp)
+	}
+	data[0] = []string{"N", "C"}
+	out = brimtext.Align(data, opts)
+	exp = `+-------+-------+
+|   N   |   C   |
++-------+-------+
+| alpha |     1 |
+| beta  | 22222 |
++-------+-------+
+`
+	if out != exp {
+		t.Errorf("%#v != %#v", out, exp)
+	}
+	opts.HeaderAlignments = []brimtext.Alignment{brimtext.Right}
+	out = brimtext.Align(data, opts)
+	exp = `+-------+-------+
+|     N |   C   |
++-------+-------+
+| alpha |     1 |
+| beta  | 22222 |
++-------+-------+
+`
+	if out != exp {
+		t.Errorf("%#v != %#v", out, exp)
+	}
+	boxed := brimtext.NewBoxedAlignOptions()
+	boxed.HeaderAlignments = []brimtext.Alignment{}
+	out = brimtext.Align([][]string{[]string{"N", "C"}, []string{"alpha", "1"}}, boxed)
+	exp = `+=======+===+
+|   N   | C |
++=======+===+
+| alpha | 1 |
++=======+===+
+`
+	if out != exp {
+		t.Errorf("%#v != %#v", out, exp)
+	}
+	out = brimtext.Align([][]string{[]string{"N", "C"}, []string{"alpha", "1"}}, opts)
+	exp = `+-------+---+
+| N     | C |
+| alpha | 1 |
++-------+---+
+`
+	if out != exp {
+		t.Errorf("%#v != %#v", out, exp)
+	}
+}
+
+func TestAlignMaxTableWidth(t *testing.T) {
+	data := [][]string{
+		[]string{"ID", "Note"},
+		nil,
+		[]string{"aa bb cc dd", "one two three four"},
+	}
+	opts := brimtext.NewSimpleAlignOptions()
+	opts.MaxTableWidth = 24
+	out := brimtext.Align(data, opts)
+	exp := "+----------+-----------+\n" +
+		"| ID       | Note      |\n" +
+		"+----------+-----------+\n" +
+		"| aa bb cc | one two   |\n" +
+		"| dd       | three     |\n" +
+		"|          | four      |\n" +
+		"+----------+-----------+\n"
+	if out != exp {
+		t.Errorf("%#v != %#v", out, exp)
+	}
+	opts.MaxTableWidth = 100
+	if out := brimtext.Align(data, opts); out != brimtext.Align(data, brimtext.NewSimpleAlignOptions()) {
+		t.Errorf("MaxTableWidth changed a table that fit: %#v", out)
+	}
+}
+
+func TestAlignWidthFor(t *testing.T) {
+	data := [][]string{
+		[]string{"ID", "Note"},
+		nil,
+		[]string{"aa bb cc dd", "one two three four"},
+	}
+	opts := brimtext.NewSimpleAlignOptions()
+	opts.MaxTableWidth = 24
+	opts.WidthFor = func(col int, naturalWidth int, available int) int {
+		if col == 0 {
+			return naturalWidth
+		}
+		return available
+	}
+	out := brimtext.Align(data, opts)
+	exp := "+-------------+--------+\n" +
+		"| ID          | Note   |\n" +
+		"+-------------+--------+\n" +
+		"| aa bb cc dd | one    |\n" +
+		"|             | two    |\n" +
+		"|             | three  |\n" +
+		"|             | four   |\n" +
+		"+-------------+--------+\n"
+	if out != exp {
+		t.Errorf("%#v != %#v", out, exp)
+	}
+	opts.MaxTableWidth = 0
+	opts.WidthFor = func(col int, naturalWidth int, available int) int {
+		if col == 1 {
+			return 20
+		}
+		return available
+	}
+	out = brimtext.Align(data, opts)
+	exp = "+-------------+----------------------+\n" +
+		"| ID          | Note                 |\n" +
+		"+-------------+----------------------+\n" +
+		"| aa bb cc dd | one two three four   |\n" +
+		"+-------------+----------------------+\n"
+	if out != exp {
+		t.Errorf("%#v != %#v", out, exp)
+	}
+}
+
+func TestAlignHighlight(t *testing.T) {
+	opts := brimtext.NewSimpleAlignOptions()
+	opts.Highlight = regexp.MustCompile("(?i)an")
+	out := brimtext.Align([][]string{
+		[]string{"Name", "City"},
+		nil,
+		[]string{"Ann", "San Antonio"},
+	}, opts)
+	r, z := "\x1b[7m", "\x1b[0m"
+	exp := "+------+-------------+\n" +
+		"| Name | City        |\n" +
+		"+------+-------------+\n" +
+		"| " + r + "An" + z + "n  | S" + r + "an" + z + " " + r + "An" + z + "tonio |\n" +
+		"+------+-------------+\n"
+	if out != exp {
+		t.Errorf("%#v != %#v", out, exp)
+	}
+}
+
+func TestAlignBreakHints(t *testing.T) {
+	data := [][]string{
+		[]string{"ID", "Size"},
+		[]string{"4f2a\u200b9c31\u200bb7e0", "1"},
+	}
+	out := brimtext.Align(data, nil)
+	exp := "ID           Size\n" +
+		"4f2a9c31b7e0 1\n"
+	if out != exp {
+		t.Errorf("%#v != %#v", out, exp)
+	}
+	out = brimtext.Align(data, brimtext.NewAlignOptions(brimtext.WithWidths(8)))
+	exp = "ID       Size\n" +
+		"4f2a9c31 1\n" +
+		"b7e0     \n"
+	if out != exp {
+		t.Errorf("%#v != %#v", out, exp)
+	}
+}
+
+func TestAlignContext(t *testing.T) {
+	data := [][]string{
+		[]string{"a", "b"},
+		nil,
+		[]string{"c", "d"},
+	}
+	out, err := brimtext.AlignContext(context.Background(), data, nil)
+	if err != nil || out != brimtext.Align(data, nil) {
+		t.Errorf("%#v %v", out, err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	out, err = brimtext.AlignContext(ctx, data, nil)
+	if err != context.Canceled || out != "" {
+		t.Errorf("%#v %v", out, err)
+	}
+	if _, err = brimtext.AlignContext(ctx, nil, nil); err != context.Canceled {
+		t.Errorf("%v", err)
+	}
+}
+
+func TestAlignWrapModes(t *testing.T) {
+	data := [][]string{
+		[]string{"Hash", "Note", "Path"},
+		[]string{"0123456789abcdef", "a short note", "/usr/local/share"},
+	}
+	opts := brimtext.NewAlignOptions(
+		brimtext.WithWidths(6, 6, 8),
+		brimtext.WithWrapModes(brimtext.WrapRunes, brimtext.WrapWords, brimtext.WrapTruncate),
+	)
+	out := brimtext.Align(data, opts)
+	exp := "Hash   Note  Path\n" +
+		"012345 a     /usr/lo…\n" +
+		"6789ab short \n" +
+		"cdef   note  \n"
+	if out != exp {
+		t.Errorf("%#v != %#v", out, exp)
+	}
+}
+
+func TestAlignMarkdown(t *testing.T) {
+	data := [][]string{
+		[]string{"Name", "Count", "Note"},
+		nil,
+		[]string{"alpha", "1", "a|b"},
+		nil,
+		[]string{"beta", "22", "two\nlines"},
+	}
+	opts := brimtext.NewMarkdownAlignOptions()
+	opts.Alignments = []brimtext.Alignment{brimtext.Left, brimtext.Right, brimtext.Center}
+	out := brimtext.Align(data, opts)
+	exp := "| Name  | Count |     Note     |\n" +
+		"| :---- | ----: | :----------: |\n" +
+		"| alpha |     1 |     a\\|b     |\n" +
+		"| beta  |    22 | two<br>lines |\n"
+	if out != exp {
+		t.Errorf("%#v != %#v", out, exp)
+	}
+	out = brimtext.Align([][]string{[]string{"A", "B"}, []string{"1", "2"}}, brimtext.AlignPreset("markdown"))
+	exp = "| A   | B   |\n" +
+		"| :-- | :-- |\n" +
+		"| 1   | 2   |\n"
+	if out != exp {
+		t.Errorf("%#v != %#v", out, exp)
+	}
+	opts = brimtext.NewMarkdownAlignOptions()
+	opts.MaxTableWidth = 20
+	out = brimtext.Align([][]string{[]string{"Name", "Note"}, []string{"a", "a long note that would wrap"}}, opts)
+	exp = "| Name | Note                        |\n" +
+		"| :--- | :-------------------------- |\n" +
+		"| a    | a long note that would wrap |\n"
+	if out != exp {
+		t.Errorf("%#v != %#v", out, exp)
+	}
+}
+
+func TestAlignDecimal(t *testing.T) {
+	data := [][]string{
+		[]string{"Item", "Amount"},
+		nil,
+		[]string{"a", "12.5"},
+		[]string{"b", "3"},
+		[]string{"c", "1,024.125%"},
+		[]string{"d", "n/a"},
+		[]string{"e", "\x1b[31m-0.25\x1b[0m"},
+	}
+	opts := brimtext.NewAlignOptions(brimtext.WithAlignments(brimtext.Left, brimtext.Decimal))
+	out := brimtext.Align(data, opts)
+	exp := "Item     Amount\n" +
+		"\n" +
+		"a       12.5   \n" +
+		"b        3     \n" +
+		"c    1,024.125%\n" +
+		"d           n/a\n" +
+		"e       \x1b[31m-0.25\x1b[0m  \n"
+	if out != exp {
+		t.Errorf("%#v != %#v", out, exp)
+	}
+}
+
+func TestAlignMaxWidths(t *testing.T) {
+	data := [][]string{
+		[]string{"Path", "Message", "ID"},
+		[]string{"/usr/local/share/doc", "a fairly long message", "0123456789abcdef"},
+		[]string{"/tmp", "short", "42"},
+	}
+	opts := brimtext.NewAlignOptions(
+		brimtext.WithMaxWidths(10, 12, 8),
+		brimtext.WithWrapModes(brimtext.WrapTruncateHead, brimtext.WrapWords, brimtext.WrapTruncateMiddle),
+	)
+	opts.Ellipsis = "~"
+	out := brimtext.Align(data, opts)
+	exp := "Path       Message      ID\n" +
+		"~share/doc a fairly lo~ 0123~def\n" +
+		"/tmp       short        42\n"
+	if out != exp {
+		t.Errorf("%#v != %#v", out, exp)
+	}
+	out = brimtext.Align([][]string{
+		[]string{"日本語のパス", "x"},
+		[]string{"ab", "y"},
+	}, brimtext.NewAlignOptions(brimtext.WithMaxWidths(5)))
+	exp = "日本… x\n" +
+		"ab    y\n"
+	if out != exp {
+		t.Errorf("%#v != %#v", out, exp)
+	}
+}
+
+func TestAlignHeaderStyle(t *testing.T) {
+	data := [][]string{
+		[]string{"Name", "Count"},
+		nil,
+		[]string{"alpha", "1"},
+	}
+	opts := brimtext.NewAlignOptions(
+		brimtext.WithAlignments(brimtext.Left, brimtext.Right),
+		brimtext.WithHeaderStyle(&brimtext.HeaderStyle{Bold: true, Uppercase: true}),
+	)
+	out := brimtext.Align(data, opts)
+	exp := "\x1b[1mNAME\x1b[0m  \x1b[1mCOUNT\x1b[0m\n" +
+		"\n" +
+		"alpha     1\n"
+	if out != exp {
+		t.Errorf("%#v != %#v", out, exp)
+	}
+	opts.HeaderStyle = &brimtext.HeaderStyle{Underline: true, Code: brimtext.ANSIEscape.FCyan}
+	out = brimtext.Align(data, opts)
+	exp = "\x1b[4m\x1b[36mName\x1b[0m  \x1b[4m\x1b[36mCount\x1b[0m\n" +
+		"\n" +
+		"alpha     1\n"
+	if out != exp {
+		t.Errorf("%#v != %#v", out, exp)
+	}
+	out = brimtext.Align([][]string{
+		[]string{"\x1b[31mName\x1b[0m", "Count"},
+		nil,
+		[]string{"alpha", "1"},
+	}, brimtext.NewAlignOptions(
+		brimtext.WithHeaderStyle(&brimtext.HeaderStyle{Uppercase: true}),
+	))
+	exp = "\x1b[31mNAME\x1b[0m  COUNT\n" +
+		"\n" +
+		"alpha 1\n"
+	if out != exp {
+		t.Errorf("%#v != %#v", out, exp)
+	}
+}
+
+func TestAlignFooterRows(t *testing.T) {
+	data := [][]string{
+		[]string{"Item", "Cost"},
+		nil,
+		[]string{"apples", "3"},
+		[]string{"pears", "12"},
+		[]string{"Total", "15"},
+	}
+	opts := brimtext.NewBoxedAlignOptions()
+	opts.Alignments = []brimtext.Alignment{brimtext.Left, brimtext.Right}
+	opts.FooterRows = 1
+	opts.RowCountFooter = true
+	out := brimtext.Align(data, opts)
+	exp := "+========+======+\n" +
+		"| Item   | Cost |\n" +
+		"+========+======+\n" +
+		"| apples |    3 |\n" +
+		"+--------+------+\n" +
+		"| pears  |   12 |\n" +
+		"+========+======+\n" +
+		"| Total  |   15 |\n" +
+		"+========+======+\n" +
+		"(2 rows)\n"
+	if out != exp {
+		t.Errorf("%#v != %#v", out, exp)
+	}
+	opts = brimtext.NewSimpleAlignOptions()
+	opts.FooterRows = 1
+	opts.FooterFirstUDR = "+."
+	opts.FooterLR = "."
+	opts.FooterFirstUDLR = ".+."
+	opts.FooterLastUDL = ".+"
+	opts.FooterStyle = &brimtext.HeaderStyle{Bold: true}
+	out = brimtext.Align(data, opts)
+	exp = "+--------+------+\n" +
+		"| Item   | Cost |\n" +
+		"+--------+------+\n" +
+		"| apples | 3    |\n" +
+		"| pears  | 12   |\n" +
+		"+........+......+\n" +
+		"| \x1b[1mTotal\x1b[0m  | \x1b[1m15\x1b[0m   |\n" +
+		"+--------+------+\n"
+	if out != exp {
+		t.Errorf("%#v != %#v", out, exp)
+	}
+}
+
+func TestAlignTitleCaption(t *testing.T) {
+	data := [][]string{
+		[]string{"Name", "Count"},
+		nil,
+		[]string{"alpha", "1"},
+	}
+	opts := brimtext.NewSimpleAlignOptions()
+	opts.Title = "Totals"
+	opts.Caption = "Counts are as of the last full run."
+	out := brimtext.Align(data, opts)
+	exp := "     Totals\n" +
+		"+-------+-------+\n" +
+		"| Name  | Count |\n" +
+		"+-------+-------+\n" +
+		"| alpha | 1     |\n" +
+		"+-------+-------+\n" +
+		"Counts are as of\n" +
+		"the last full\n" +
+		"run.\n"
+	if out != exp {
+		t.Errorf("%#v != %#v", out, exp)
+	}
+}
+
+func TestAlignTitleWiderThanTable(t *testing.T) {
+	opts := brimtext.NewSimpleAlignOptions()
+	opts.Title = "Supercalifragilisticexpialidocious"
+	out := brimtext.Align([][]string{[]string{"a", "b"}}, opts)
+	exp := "Supercalifragilisticexpialidocious\n" +
+		"+---+---+\n" +
+		"| a | b |\n" +
+		"+---+---+\n"
+	if out != exp {
+		t.Errorf("%#v != %#v", out, exp)
+	}
+}
+
+func TestAlignSortBy(t *testing.T) {
+	data := [][]string{
+		[]string{"Name", "Size"},
+		nil,
+		[]string{"file10", "2"},
+		[]string{"file2", "1,024"},
+		[]string{"file1", "n/a"},
+		[]string{"file3", "2"},
+		[]string{"Total", "1,028"},
+	}
+	opts := brimtext.NewAlignOptions(brimtext.WithSortBy(brimtext.SortKey{Column: 0, Mode: brimtext.SortNatural}))
+	opts.FooterRows = 1
+	out := brimtext.Align(data, opts)
+	exp := "Name   Size\n" +
+		"\n" +
+		"file1  n/a\n" +
+		"file2  1,024\n" +
+		"file3  2\n" +
+		"file10 2\n" +
+		"\n" +
+		"Total  1,028\n"
+	if out != exp {
+		t.Errorf("%#v != %#v", out, exp)
+	}
+	opts.SortBy = []brimtext.SortKey{
+		{Column: 1, Mode: brimtext.SortNumeric, Descending: true},
+		{Column: 0, Less: func(a string, b string) bool { return a > b }},
+	}
+	out = brimtext.Align(data, opts)
+	exp = "Name   Size\n" +
+		"\n" +
+		"file2  1,024\n" +
+		"file3  2\n" +
+		"file10 2\n" +
+		"file1  n/a\n" +
+		"\n" +
+		"Total  1,028\n"
+	if out != exp {
+		t.Errorf("%#v != %#v", out, exp)
+	}
+}
+
+func TestAlignZebra(t *testing.T) {
+	data := [][]string{
+		[]string{"Name", "Count"},
+		nil,
+		[]string{"alpha", "1"},
+		[]string{"beta", "\x1b[31m2\x1b[0m"},
+		[]string{"gamma\ndelta", "3"},
+		[]string{"epsilon", "4"},
+	}
+	opts := brimtext.NewAlignOptions(brimtext.WithZebra(brimtext.ANSIEscape.Dim))
+	out := brimtext.Align(data, opts)
+	d, z := "\x1b[2m", "\x1b[0m"
+	exp := "Name    Count\n" +
+		"\n" +
+		"alpha   1\n" +
+		d + "beta    \x1b[31m2" + z + d + "    " + z + "\n" +
+		"gamma   3\n" +
+		"delta   \n" +
+		d + "epsilon 4    " + z + "\n"
+	if out != exp {
+		t.Errorf("%#v != %#v", out, exp)
+	}
+	out = brimtext.Align([][]string{[]string{"a", "b"}, []string{"\x1b[31mred", "x"}}, opts)
+	exp = "a   b\n" +
+		d + "\x1b[31mred" + z + d + " x" + z + "\n"
+	if out != exp {
+		t.Errorf("%#v != %#v", out, exp)
+	}
+}
+
+func TestAlignVerticalAlignments(t *testing.T) {
+	data := [][]string{
+		[]string{"top", "one\ntwo\nthree\nfour", "middle", "bottom"},
+	}
+	opts := brimtext.NewAlignOptions()
+	opts.VerticalAlignments = []brimtext.VerticalAlignment{brimtext.Top, brimtext.Top, brimtext.Middle, brimtext.Bottom}
+	out := brimtext.Align(data, opts)
+	exp := "top one          \n" +
+		"    two   middle \n" +
+		"    three        \n" +
+		"    four         bottom\n"
+	if out != exp {
+		t.Errorf("%#v != %#v", out, exp)
+	}
+}
+
+func TestWithSortByCopies(t *testing.T) {
+	keys := []brimtext.SortKey{brimtext.SortKey{Column: 1}}
+	opts := brimtext.NewAlignOptions(brimtext.WithSortBy(keys...))
+	keys[0].Column = 2
+	if opts.SortBy[0].Column != 1 {
+		t.Errorf("%#v", opts.SortBy)
+	}
+}
+
+func TestAlignLayoutSortBySources(t *testing.T) {
+	data := [][]string{
+		[]string{"Name"},
+		nil,
+		[]string{"b"},
+		[]string{"a"},
+	}
+	opts := brimtext.NewAlignOptions(brimtext.WithSortBy(brimtext.SortKey{Column: 0}))
+	layout := brimtext.NewAlignLayout(data, opts)
+	if !reflect.DeepEqual(layout.Rows, [][]string{[]string{"Name"}, nil, []string{"a"}, []string{"b"}}) {
+		t.Errorf("%#v", layout.Rows)
+	}
+	if !reflect.DeepEqual(layout.Sources, []int{0, 1, 3, 2}) {
+		t.Errorf("%#v", layout.Sources)
+	}
+}
+
+func TestAlignFitWidth(t *testing.T) {
+	defer brimtext.SetAlignTTYWidth(func() int { return 16 })()
+	data := [][]string{
+		[]string{"Name", "Notes"},
+		[]string{"alpha", "one two three four"},
+	}
+	opts := brimtext.NewAlignOptions()
+	opts.FitWidth = true
+	out := brimtext.Align(data, opts)
+	exp := "Name  Notes\n" +
+		"alpha one two\n" +
+		"      three\n" +
+		"      four\n"
+	if out != exp {
+		t.Errorf("%#v != %#v", out, exp)
+	}
+	opts.MaxTableWidth = 40
+	if out = brimtext.Align(data, opts); out != brimtext.Align(data, nil) {
+		t.Errorf("%#v != %#v", out, brimtext.Align(data, nil))
+	}
+}
+
+type failWriter struct{}
+
+func (failWriter) Write(p []byte) (int, error) {
+	return 0, fmt.Errorf("write failed")
+}
+
+func TestAlignTo(t *testing.T) {
+	data := [][]string{[]string{"name", "value"}, nil}
+	for i := 0; i < 5000; i++ {
+		data = append(data, []string{fmt.Sprintf("row%d", i), strings.Repeat("x", i%40)})
+	}
+	opts := brimtext.NewBoxedAlignOptions()
+	exp := brimtext.Align(data, opts)
+	var b strings.Builder
+	n, err := brimtext.AlignTo(&b, data, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b.String() != exp || n != len(exp) {
+		t.Errorf("AlignTo wrote %d bytes, differing from Align's %d", n, len(exp))
+	}
+	var buf bytes.Buffer
+	buf.WriteString("> ")
+	n, err = brimtext.AlignTo(&buf, data[:3], nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e := brimtext.Align(data[:3], nil); buf.String() != "> "+e || n != len(e) {
+		t.Errorf("%#v != %#v", buf.String(), "> "+e)
+	}
+	if _, err = brimtext.AlignTo(failWriter{}, data, nil); err == nil {
+		t.Errorf("expected an error")
+	}
+}
@@ -2,6 +2,7 @@ package brimtext
 
 import (
 	"bytes"
+	"regexp"
 	"strconv"
 	"strings"
 )
@@ -11,30 +12,36 @@ import (
 type ANSIEscapeCodes struct {
 	Reset                                                         []byte
 	Bold                                                          []byte
+	Dim                                                           []byte
+	Reverse                                                       []byte
+	Underline                                                     []byte
 	BBlack, BRed, BGreen, BYellow, BBlue, BMagenta, BCyan, BWhite []byte
 	FBlack, FRed, FGreen, FYellow, FBlue, FMagenta, FCyan, FWhite []byte
 }
 
 // ANSIEscape provides ease of access to common ANSI Escape Codes.
 var ANSIEscape = ANSIEscapeCodes{
-	Reset:    []byte{27, '[', '0', 'm'},
-	Bold:     []byte{27, '[', '1', 'm'},
-	BBlack:   []byte{27, '[', '4', '0', 'm'},
-	BRed:     []byte{27, '[', '4', '1', 'm'},
-	BGreen:   []byte{27, '[', '4', '2', 'm'},
-	BYellow:  []byte{27, '[', '4', '3', 'm'},
-	BBlue:    []byte{27, '[', '4', '4', 'm'},
-	BMagenta: []byte{27, '[', '4', '5', 'm'},
-	BCyan:    []byte{27, '[', '4', '6', 'm'},
-	BWhite:   []byte{27, '[', '4', '7', 'm'},
-	FBlack:   []byte{27, '[', '3', '0', 'm'},
-	FRed:     []byte{27, '[', '3', '1', 'm'},
-	FGreen:   []byte{27, '[', '3', '2', 'm'},
-	FYellow:  []byte{27, '[', '3', '3', 'm'},
-	FBlue:    []byte{27, '[', '3', '4', 'm'},
-	FMagenta: []byte{27, '[', '3', '5', 'm'},
-	FCyan:    []byte{27, '[', '3', '6', 'm'},
-	FWhite:   []byte{27, '[', '3', '7', 'm'},
+	Reset:     []byte{27, '[', '0', 'm'},
+	Bold:      []byte{27, '[', '1', 'm'},
+	Dim:       []byte{27, '[', '2', 'm'},
+	Reverse:   []byte{27, '[', '7', 'm'},
+	Underline: []byte{27, '[', '4', 'm'},
+	BBlack:    []byte{27, '[', '4', '0', 'm'},
+	BRed:      []byte{27, '[', '4', '1', 'm'},
+	BGreen:    []byte{27, '[', '4', '2', 'm'},
+	BYellow:   []byte{27, '[', '4', '3', 'm'},
+	BBlue:     []byte{27, '[', '4', '4', 'm'},
+	BMagenta:  []byte{27, '[', '4', '5', 'm'},
+	BCyan:     []byte{27, '[', '4', '6', 'm'},
+	BWhite:    []byte{27, '[', '4', '7', 'm'},
+	FBlack:    []byte{27, '[', '3', '0', 'm'},
+	FRed:      []byte{27, '[', '3', '1', 'm'},
+	FGreen:    []byte{27, '[', '3', '2', 'm'},
+	FYellow:   []byte{27, '[', '3', '3', 'm'},
+	FBlue:     []byte{27, '[', '3', '4', 'm'},
+	FMagenta:  []byte{27, '[', '3', '5', 'm'},
+	FCyan:     []byte{27, '[', '3', '6', 'm'},
+	FWhite:    []byte{27, '[', '3', '7', 'm'},
 }
 
 // ClosestANSIForegroundString translates the CSS-style color (e.g. "#ac8"
@@ -132,6 +139,7 @@ func StripANSIEscapes(v string) string {
 				if bs[j] == 'm' {
 					copy(bs[i:], bs[j+1:])
 					ln -= j + 1 - i
+					i--
 					break
 				}
 			}
@@ -143,3 +151,145 @@ func StripANSIEscapes(v string) string {
 func RuneLenStripANSIEscapes(v string) int {
 	return len([]rune(StripANSIEscapes(v)))
 }
+
+// ColorTags maps the tag names understood by Colorize to their ANSI escape
+// codes; add to it to define application specific tags, such as
+// ColorTags["warn"] = ANSIEscape.FYellow.
+var ColorTags = map[string][]byte{
+	"reset":     ANSIEscape.Reset,
+	"bold":      ANSIEscape.Bold,
+	"dim":       ANSIEscape.Dim,
+	"reverse":   ANSIEscape.Reverse,
+	"underline": ANSIEscape.Underline,
+	"black":     ANSIEscape.FBlack,
+	"red":       ANSIEscape.FRed,
+	"green":     ANSIEscape.FGreen,
+	"yellow":    ANSIEscape.FYellow,
+	"blue":      ANSIEscape.FBlue,
+	"magenta":   ANSIEscape.FMagenta,
+	"cyan":      ANSIEscape.FCyan,
+	"white":     ANSIEscape.FWhite,
+	"bgblack":   ANSIEscape.BBlack,
+	"bgred":     ANSIEscape.BRed,
+	"bggreen":   ANSIEscape.BGreen,
+	"bgyellow":  ANSIEscape.BYellow,
+	"bgblue":    ANSIEscape.BBlue,
+	"bgmagenta": ANSIEscape.BMagenta,
+	"bgcyan":    ANSIEscape.BCyan,
+	"bgwhite":   ANSIEscape.BWhite,
+}
+
+// Colorize expands color tags like "{red}error:{reset} {bold}%s{reset}" into
+// ANSI escape codes, letting application strings carry their styling while
+// staying readable. Tag names are those in ColorTags, or CSS-style colors
+// like "{#f80}" which use ClosestANSIForegroundString. Use "{{" for a
+// literal "{"; unknown tags are left as is.
+func Colorize(text string) string {
+	return colorize(text, false)
+}
+
+// ColorizePlain is like Colorize but removes the color tags instead, for
+// output that isn't going to a terminal.
+func ColorizePlain(text string) string {
+	return colorize(text, true)
+}
+
+func colorize(text string, plain bool) string {
+	var buf bytes.Buffer
+	for {
+		i := strings.IndexByte(text, '{')
+		if i < 0 || i == len(text)-1 {
+			buf.WriteString(text)
+			break
+		}
+		buf.WriteString(text[:i])
+		text = text[i:]
+		if text[1] == '{' {
+			buf.WriteByte('{')
+			text = text[2:]
+			continue
+		}
+		j := strings.IndexByte(text, '}')
+		if j < 0 {
+			buf.WriteString(text)
+			break
+		}
+		name := text[1:j]
+		code, ok := ColorTags[name]
+		if !ok && strings.HasPrefix(name, "#") {
+			code = ClosestANSIForegroundString(name)
+			ok = len(code) > 0
+		}
+		if !ok {
+			buf.WriteByte('{')
+			text = text[1:]
+			continue
+		}
+		if !plain {
+			buf.Write(code)
+		}
+		text = text[j+1:]
+	}
+	return buf.String()
+}
+
+// Highlight wraps each match of re in v with code, or ANSIEscape.Reverse if
+// code is nil, and ANSIEscape.Reset. Matches are found in v as it would be
+// displayed, ignoring any ANSI escape codes it has; those codes are kept and
+// given again after each highlight's Reset so the styling around a match
+// continues past it.
+func Highlight(v string, re *regexp.Regexp, code []byte) string {
+	if re == nil || v == "" {
+		return v
+	}
+	if code == nil {
+		code = ANSIEscape.Reverse
+	}
+	var matches [][]int
+	for _, m := range re.FindAllStringIndex(StripANSIEscapes(v), -1) {
+		if m[1] > m[0] {
+			matches = append(matches, m)
+		}
+	}
+	if len(matches) == 0 {
+		return v
+	}
+	var buf bytes.Buffer
+	var active []byte
+	m, p, in := 0, 0, false
+	for i := 0; i <= len(v); {
+		if in && p == matches[m][1] {
+			buf.Write(ANSIEscape.Reset)
+			buf.Write(active)
+			in = false
+			m++
+		}
+		if !in && m < len(matches) && p == matches[m][0] {
+			buf.Write(code)
+			in = true
+		}
+		if i == len(v) {
+			break
+		}
+		if v[i] == 27 {
+			if j := strings.IndexByte(v[i:], 'm'); j >= 0 {
+				seq := v[i : i+j+1]
+				buf.WriteString(seq)
+				if seq == string(ANSIEscape.Reset) {
+					active = active[:0]
+				} else {
+					active = append(active, seq...)
+				}
+				if in {
+					buf.Write(code)
+				}
+				i += j + 1
+				continue
+			}
+		}
+		buf.WriteByte(v[i])
+		i++
+		p++
+	}
+	return buf.String()
+}
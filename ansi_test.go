@@ -1,6 +1,7 @@
 package brimtext
 
 import (
+	"regexp"
 	"testing"
 )
 
@@ -165,9 +166,11 @@ func TestClosestANSIForeground(t *testing.T) {
 
 func TestStripANSIEscapes(t *testing.T) {
 	for in, exp := range map[string]string{
-		"link":                  "link",
-		"link\x1bstuffm":        "link",
-		"link\x1bstuffmandmore": "linkandmore",
+		"link":                       "link",
+		"link\x1bstuffm":             "link",
+		"link\x1bstuffmandmore":      "linkandmore",
+		"\x1b[1m\x1b[4mx":            "x",
+		"\x1b[4m\x1b[36mlink\x1b[0m": "link",
 	} {
 		out := StripANSIEscapes(in)
 		if out != exp {
@@ -175,3 +178,58 @@ func TestStripANSIEscapes(t *testing.T) {
 		}
 	}
 }
+
+func TestColorize(t *testing.T) {
+	for _, v := range []struct {
+		in    string
+		exp   string
+		plain string
+	}{
+		{"", "", ""},
+		{"plain", "plain", "plain"},
+		{"{red}error:{reset} {bold}%s{reset}", "\x1b[31merror:\x1b[0m \x1b[1m%s\x1b[0m", "error: %s"},
+		{"{bgblue}{white}x", "\x1b[44m\x1b[37mx", "x"},
+		{"{#ff0000}hot", "\x1b[1m\x1b[31mhot", "hot"},
+		{"{{red}", "{red}", "{red}"},
+		{"{unknown} {} {#zz} {", "{unknown} {} {#zz} {", "{unknown} {} {#zz} {"},
+		{"map{a}{red", "map{a}{red", "map{a}{red"},
+	} {
+		if out := Colorize(v.in); out != v.exp {
+			t.Errorf("Colorize(%#v) %#v != %#v", v.in, out, v.exp)
+		}
+		if out := ColorizePlain(v.in); out != v.plain {
+			t.Errorf("ColorizePlain(%#v) %#v != %#v", v.in, out, v.plain)
+		}
+	}
+	ColorTags["warn"] = ANSIEscape.FYellow
+	defer delete(ColorTags, "warn")
+	if out := Colorize("{warn}!"); out != "\x1b[33m!" {
+		t.Errorf("Colorize custom tag %#v", out)
+	}
+}
+
+func TestHighlight(t *testing.T) {
+	for _, v := range []struct {
+		in  string
+		re  string
+		exp string
+	}{
+		{"", "a", ""},
+		{"abc", "x", "abc"},
+		{"abc", "x*", "abc"},
+		{"abcabc", "b", "a\x1b[7mb\x1b[0mca\x1b[7mb\x1b[0mc"},
+		{"abc", "abc", "\x1b[7mabc\x1b[0m"},
+		{"\x1b[31mred\x1b[0m text", "d t", "\x1b[31mre\x1b[7md\x1b[0m\x1b[7m t\x1b[0mext"},
+		{"a\x1b[1mb\x1b[0mc", "(?i)ABC", "\x1b[7ma\x1b[1m\x1b[7mb\x1b[0m\x1b[7mc\x1b[0m"},
+	} {
+		if out := Highlight(v.in, regexp.MustCompile(v.re), nil); out != v.exp {
+			t.Errorf("Highlight(%#v, %#v) %#v != %#v", v.in, v.re, out, v.exp)
+		}
+	}
+	if out := Highlight("abc", regexp.MustCompile("b"), ANSIEscape.FRed); out != "a\x1b[31mb\x1b[0mc" {
+		t.Errorf("Highlight with code %#v", out)
+	}
+	if out := Highlight("abc", nil, nil); out != "abc" {
+		t.Errorf("Highlight with nil re %#v", out)
+	}
+}
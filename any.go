@@ -0,0 +1,127 @@
+package brimtext
+
+import (
+	"reflect"
+	"time"
+)
+
+// ValueFormatter converts a cell value of any type into its display form;
+// see AlignOptions.ValueFormatters.
+type ValueFormatter func(value interface{}) string
+
+// StringValueFormatter returns a ValueFormatter giving the value as the
+// DefaultTypeRegistry formats it passed through f, so the ColumnFormatters
+// can be used for values, such as StringValueFormatter(ThousandsFormatter(","))
+// for ints.
+func StringValueFormatter(f ColumnFormatter) ValueFormatter {
+	return func(value interface{}) string {
+		return f(DefaultTypeRegistry.Format(value))
+	}
+}
+
+// TimeValueFormatter returns a ValueFormatter giving time.Time values, and
+// pointers to them, in the layout, such as time.Kitchen; the zero time is
+// blank. Values of other types are given as the DefaultTypeRegistry formats
+// them.
+func TimeValueFormatter(layout string) ValueFormatter {
+	return func(value interface{}) string {
+		if p, ok := value.(*time.Time); ok && p != nil {
+			value = *p
+		}
+		t, ok := value.(time.Time)
+		if !ok {
+			return DefaultTypeRegistry.Format(value)
+		}
+		if t.IsZero() {
+			return ""
+		}
+		return t.Format(layout)
+	}
+}
+
+// AlignAny is like Align but for rows of values of any type, so they needn't
+// all be turned into strings first:
+//
+//	data := [][]interface{}{
+//		{"Name", "Size", "Modified"},
+//		nil,
+//		{"a.txt", brimtext.ByteSize(1536), modified},
+//		{"b.txt", brimtext.ByteSize(42), time.Time{}},
+//		nil,
+//		{brimtext.AlignedCell("Total", brimtext.Right), brimtext.ByteSize(1578)},
+//	}
+//	opts := brimtext.NewDefaultAlignOptions()
+//	opts.ValueFormatters = []brimtext.ValueFormatter{nil, nil, brimtext.TimeValueFormatter(time.Kitchen)}
+//
+// Values in the columns with ValueFormatters, outside of the header rows,
+// are given by them; the others are given as opts.Types formats them. Cell
+// and *Cell values are used as they are, as with AlignCells. Columns beyond
+// opts.Alignments are aligned as opts.Types gives for the type of their
+// first value after the header rows. If opts is nil, NewDefaultAlignOptions
+// is used.
+func AlignAny(data [][]interface{}, opts *AlignOptions) string {
+	if len(data) == 0 {
+		return ""
+	}
+	if opts == nil {
+		opts = NewDefaultAlignOptions()
+	}
+	types := opts.Types
+	if types == nil {
+		types = DefaultTypeRegistry
+	}
+	headerRows := 0
+	if opts.NilBetweenEveryRow {
+		headerRows = 1
+	} else {
+		for i, row := range data {
+			if row == nil {
+				headerRows = i
+				break
+			}
+		}
+	}
+	var alignments []Alignment
+	cells := make([][]Cell, len(data))
+	for r, row := range data {
+		if row == nil {
+			continue
+		}
+		cells[r] = make([]Cell, len(row))
+		for c, value := range row {
+			switch v := value.(type) {
+			case Cell:
+				cells[r][c] = v
+				continue
+			case *Cell:
+				if v != nil {
+					cells[r][c] = *v
+				}
+				continue
+			}
+			if r >= headerRows && c < len(opts.ValueFormatters) && opts.ValueFormatters[c] != nil {
+				cells[r][c].Value = opts.ValueFormatters[c](value)
+			} else {
+				cells[r][c].Value = types.Format(value)
+			}
+			if r >= headerRows && c >= len(opts.Alignments) && value != nil {
+				for len(alignments) <= c {
+					alignments = append(alignments, cellAlignUnset)
+				}
+				if alignments[c] == cellAlignUnset {
+					alignments[c] = types.Style(reflect.TypeOf(value)).Alignment
+				}
+			}
+		}
+	}
+	if len(alignments) > len(opts.Alignments) {
+		opts = opts.Clone()
+		opts.Alignments = append(opts.Alignments, alignments[len(opts.Alignments):]...)
+		for c, align := range opts.Alignments {
+			if align == cellAlignUnset {
+				opts.Alignments[c] = Left
+			}
+		}
+	}
+	return AlignCells(cells, opts)
+}
@@ -0,0 +1,33 @@
+package brimtext
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAlignAny(t *testing.T) {
+	modified := time.Date(2020, 3, 4, 15, 4, 0, 0, time.UTC)
+	data := [][]interface{}{
+		{"Name", "Size", "Modified", "Count"},
+		nil,
+		{"a.txt", ByteSize(1536), modified, 1234567},
+		{"b.txt", ByteSize(42), time.Time{}, nil},
+		nil,
+		{AlignedCell("Total", Right), ByteSize(1578), &Cell{Value: "-"}, 1234567},
+	}
+	opts := NewDefaultAlignOptions()
+	opts.ValueFormatters = []ValueFormatter{nil, nil, TimeValueFormatter(time.Kitchen), StringValueFormatter(ThousandsFormatter(","))}
+	out := AlignAny(data, opts)
+	exp := "Name     Size Modified     Count\n" +
+		"\n" +
+		"a.txt  1.5KiB 3:04PM   1,234,567\n" +
+		"b.txt     42B                   \n" +
+		"\n" +
+		"Total 1.54KiB -        1,234,567\n"
+	if out != exp {
+		t.Errorf("%#v != %#v", out, exp)
+	}
+	if out = AlignAny(nil, nil); out != "" {
+		t.Errorf("%#v != \"\"", out)
+	}
+}
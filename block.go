@@ -0,0 +1,129 @@
+package brimtext
+
+import (
+	"strconv"
+	"strings"
+)
+
+// blockLines splits the block of text into its lines, ignoring a single
+// trailing newline.
+func blockLines(block string) []string {
+	if block == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(block, "\n"), "\n")
+}
+
+// blockWidth returns the width of the widest line, ignoring ANSI escape
+// codes.
+func blockWidth(lines []string) int {
+	width := 0
+	for _, line := range lines {
+		if w := DisplayWidth(line); w > width {
+			width = w
+		}
+	}
+	return width
+}
+
+// MeasureBlock returns the display width of the widest line of the block of
+// text and how many lines it has, ignoring ANSI escape codes and a single
+// trailing newline, and counting wide runes as two columns; see
+// DisplayWidth. Use it to fit Align, Wrap, and other output into larger
+// layouts.
+func MeasureBlock(s string) (width int, height int) {
+	lines := blockLines(s)
+	for _, line := range lines {
+		if w := DisplayWidth(line); w > width {
+			width = w
+		}
+	}
+	return width, len(lines)
+}
+
+// JoinHorizontal places the blocks of text side by side, separated by gap,
+// such as to show several tables, trees, or plots next to each other. Each
+// block's lines are padded to its widest line and shorter blocks are padded
+// with blank lines; trailing whitespace is removed from the joined lines,
+// each of which ends with a newline.
+func JoinHorizontal(gap string, blocks ...string) string {
+	lines := make([][]string, len(blocks))
+	widths := make([]int, len(blocks))
+	height := 0
+	for i, block := range blocks {
+		lines[i] = blockLines(block)
+		widths[i] = blockWidth(lines[i])
+		if len(lines[i]) > height {
+			height = len(lines[i])
+		}
+	}
+	var out []string
+	for l := 0; l < height; l++ {
+		parts := make([]string, len(blocks))
+		for i := range blocks {
+			line := ""
+			if l < len(lines[i]) {
+				line = lines[i][l]
+			}
+			parts[i] = line + strings.Repeat(" ", widths[i]-DisplayWidth(line))
+		}
+		out = append(out, strings.TrimRight(strings.Join(parts, gap), " ")+"\n")
+	}
+	return strings.Join(out, "")
+}
+
+// FitBlockOptions controls the finer points of FitBlock.
+type FitBlockOptions struct {
+	// More, if not empty, replaces the last line that fits when text is
+	// cropped to the height, with any "%d" replaced by how many lines of the
+	// text aren't shown, such as "(+%d lines)". If empty, the last line that
+	// fits is ended with "…" instead.
+	More string
+}
+
+// FitBlock returns the text wrapped to width, breaking words too long for it,
+// and cropped to height lines, with every line padded to exactly width
+// display columns and blank lines added to make exactly height lines, such
+// as for fixed size panes and cards. Each line ends with a newline. A width
+// or height < 1 leaves that dimension unconstrained. If opts is nil, cropped
+// text ends with "…"; see FitBlockOptions.
+func FitBlock(text string, width int, height int, opts *FitBlockOptions) string {
+	if opts == nil {
+		opts = &FitBlockOptions{}
+	}
+	var lines []string
+	for _, line := range blockLines(text) {
+		if width > 0 && DisplayWidth(line) > width {
+			line = Wrap(line, width, "", "")
+		}
+		for _, wrapped := range strings.Split(line, "\n") {
+			lines = append(lines, wrapRunes(wrapped, width)...)
+		}
+	}
+	if height > 0 && len(lines) > height {
+		last := lines[height-1]
+		if opts.More != "" {
+			last = strings.Replace(opts.More, "%d", strconv.Itoa(len(lines)-height+1), -1)
+			last = wrapRunes(last, width)[0]
+		} else if width < 1 || DisplayWidth(last) < width {
+			last += "…"
+		} else if width == 1 {
+			last = "…"
+		} else {
+			last = wrapRunes(last, width-1)[0] + "…"
+		}
+		lines = append(lines[:height-1], last)
+	}
+	for height > 0 && len(lines) < height {
+		lines = append(lines, "")
+	}
+	var buf strings.Builder
+	for _, line := range lines {
+		buf.WriteString(line)
+		if pad := width - DisplayWidth(line); pad > 0 {
+			buf.WriteString(strings.Repeat(" ", pad))
+		}
+		buf.WriteByte('\n')
+	}
+	return buf.String()
+}
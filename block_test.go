@@ -0,0 +1,77 @@
+package brimtext
+
+import (
+	"testing"
+)
+
+func TestJoinHorizontal(t *testing.T) {
+	out := JoinHorizontal("|", "a\nbbb\n", "cc", "d\ne\nf\n")
+	exp := "a  |cc|d\n" +
+		"bbb|  |e\n" +
+		"   |  |f\n"
+	if out != exp {
+		t.Errorf("JoinHorizontal %#v != %#v", out, exp)
+	}
+	out = JoinHorizontal("  ", "\x1b[1mab\x1b[0m\nc", "d\n")
+	exp = "\x1b[1mab\x1b[0m  d\n" +
+		"c\n"
+	if out != exp {
+		t.Errorf("JoinHorizontal ANSI %#v != %#v", out, exp)
+	}
+	if out = JoinHorizontal(" "); out != "" {
+		t.Errorf("JoinHorizontal() %#v", out)
+	}
+}
+
+func TestMeasureBlock(t *testing.T) {
+	for _, v := range []struct {
+		in     string
+		width  int
+		height int
+	}{
+		{"", 0, 0},
+		{"\n", 0, 1},
+		{"abc", 3, 1},
+		{"a\nbbb\ncc\n", 3, 3},
+		{"a\n\n", 1, 2},
+		{"\x1b[1mbold\x1b[0m\nx", 4, 2},
+		{"日本語\nabcd", 6, 2},
+	} {
+		width, height := MeasureBlock(v.in)
+		if width != v.width || height != v.height {
+			t.Errorf("MeasureBlock(%#v) %d, %d != %d, %d", v.in, width, height, v.width, v.height)
+		}
+	}
+}
+
+func TestFitBlock(t *testing.T) {
+	for _, v := range []struct {
+		in     string
+		width  int
+		height int
+		more   string
+		exp    string
+	}{
+		{"", 3, 2, "", "   \n   \n"},
+		{"ab\ncd", 4, 3, "", "ab  \ncd  \n    \n"},
+		{"one two three", 7, 0, "", "one two\nthree  \n"},
+		{"  keep  spacing", 16, 1, "", "  keep  spacing \n"},
+		{"abcdefghij", 4, 0, "", "abcd\nefgh\nij  \n"},
+		{"one two three four", 7, 2, "", "one two\nthree… \n"},
+		{"one two threes four", 6, 2, "", "one   \ntwo…  \n"},
+		{"a\nb\nc\nd", 9, 2, "(+%d more)", "a        \n(+3 more)\n"},
+		{"a\nb\nc\nd", 5, 2, "(+%d more)", "a    \n(+3 m\n"},
+		{"a\nb", 1, 1, "", "…\n"},
+		{"a\nb\nc", 0, 2, "", "a\nb…\n"},
+		{"日本語", 4, 0, "", "日本\n語  \n"},
+		{"\x1b[1mbold text\x1b[0m", 4, 0, "", "\x1b[1mbold\ntext\x1b[0m\n"},
+	} {
+		out := FitBlock(v.in, v.width, v.height, &FitBlockOptions{More: v.more})
+		if out != v.exp {
+			t.Errorf("FitBlock(%#v, %d, %d, %#v) %#v != %#v", v.in, v.width, v.height, v.more, out, v.exp)
+		}
+	}
+	if out := FitBlock("abc\ndef", 3, 1, nil); out != "ab…\n" {
+		t.Errorf("FitBlock nil opts %#v", out)
+	}
+}
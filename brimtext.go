@@ -9,6 +9,7 @@ import (
 	"math"
 	"strconv"
 	"strings"
+	"unicode"
 	"unicode/utf8"
 )
 
@@ -67,7 +68,92 @@ func ThousandsSepU(v uint64, sep string) string {
 //  func HumanSize1024(v float64) string {
 //  	return HumanSize(v, 1024, []string{"", "K", "M", "G", "T", "P", "E", "Z", "Y"})
 //  }
+//
+// For control over the precision and rounding, see HumanSizeWithOptions.
 func HumanSize(v float64, u float64, s []string) string {
+	return humanSize(v, u, s, false)
+}
+
+// HumanSizeFull is like HumanSize but also appends s[0] to values below the
+// first tier, where HumanSize leaves them bare, so a column of sizes reads
+// consistently: "123B", "1.23kB", "1MB" rather than "123", "1.23kB", "1MB".
+func HumanSizeFull(v float64, u float64, s []string) string {
+	return humanSize(v, u, s, true)
+}
+
+func humanSize(v float64, u float64, s []string, full bool) string {
+	return HumanSizeWithOptions(v, &HumanSizeOptions{Base: u, Suffixes: s, Full: full})
+}
+
+// HumanSizeRounding selects how HumanSizeWithOptions rounds values; see
+// HumanSizeOptions.
+type HumanSizeRounding int
+
+const (
+	// HumanSizeRoundNearest rounds to the nearest value shown.
+	HumanSizeRoundNearest HumanSizeRounding = iota
+	// HumanSizeRoundUp rounds away from zero, such as for space needed.
+	HumanSizeRoundUp
+	// HumanSizeRoundDown rounds toward zero, such as for space free.
+	HumanSizeRoundDown
+)
+
+// HumanSizeOptions controls the output of HumanSizeWithOptions.
+type HumanSizeOptions struct {
+	// Base is the factor between the tiers of suffixes, usually 1000 or
+	// 1024; if < 2, 1000 is used.
+	Base float64
+	// Suffixes are the suffixes of each tier, with Suffixes[0] for values
+	// below the first; if empty, those of HumanSize1024 are used if Base is
+	// 1024 and those of HumanSize1000 otherwise.
+	Suffixes []string
+	// Full appends Suffixes[0] to values below the first tier; see
+	// HumanSizeFull.
+	Full bool
+	// Precision is the number of significant digits shown for values above
+	// the first tier, though whole numbers are never cut short; if < 1,
+	// values below 1 in their tier show 2 and others show 3.
+	Precision int
+	// Rounding selects how values are rounded to the digits shown.
+	Rounding HumanSizeRounding
+}
+
+// NewHumanSizeOptions gives the options matching HumanSize1000:
+//
+//  &HumanSizeOptions{
+//      Base:     1000,
+//      Suffixes: []string{"", "k", "m", "g", "t", "p", "e", "z", "y"},
+//  }
+func NewHumanSizeOptions() *HumanSizeOptions {
+	return &HumanSizeOptions{
+		Base:     1000,
+		Suffixes: []string{"", "k", "m", "g", "t", "p", "e", "z", "y"},
+	}
+}
+
+// HumanSizeWithOptions is like HumanSize but with the output controlled by
+// opts; see HumanSizeOptions. If opts is nil, NewHumanSizeOptions is used.
+// Negative values are given as their size with a leading "-", so
+// HumanSizeWithOptions(-1500, nil) gives "-1.5k".
+func HumanSizeWithOptions(v float64, opts *HumanSizeOptions) string {
+	if opts == nil {
+		opts = NewHumanSizeOptions()
+	}
+	if v < 0 {
+		return "-" + HumanSizeWithOptions(-v, opts)
+	}
+	u := opts.Base
+	if u < 2 {
+		u = 1000
+	}
+	s := opts.Suffixes
+	if len(s) == 0 {
+		if u == 1024 {
+			s = []string{"", "K", "M", "G", "T", "P", "E", "Z", "Y"}
+		} else {
+			s = NewHumanSizeOptions().Suffixes
+		}
+	}
 	n := v
 	i := 0
 	for ; i < len(s); i++ {
@@ -80,12 +166,46 @@ func HumanSize(v float64, u float64, s []string) string {
 		return fmt.Sprintf("%.0f%s", n*u, s[len(s)-1])
 	}
 	if i == 0 {
+		n = humanSizeRound(n, 4, opts.Rounding)
+		if opts.Full {
+			return fmt.Sprintf("%.4g%s", n, s[0])
+		}
 		return fmt.Sprintf("%.4g", n)
 	}
-	if n < 1 {
-		return fmt.Sprintf("%.2g%s", n, s[i])
+	digits := opts.Precision
+	if digits < 1 {
+		digits = 3
+		if n < 1 {
+			digits = 2
+		}
+	}
+	if whole := humanSizeMagnitude(n); digits < whole {
+		digits = whole
 	}
-	return fmt.Sprintf("%.3g%s", n, s[i])
+	n = humanSizeRound(n, digits, opts.Rounding)
+	return fmt.Sprintf("%.*g%s", digits, n, s[i])
+}
+
+// humanSizeMagnitude returns the number of digits before the decimal point
+// of n, or for n < 1 a negative count of the zeros after it.
+func humanSizeMagnitude(n float64) int {
+	if n <= 0 {
+		return 0
+	}
+	return int(math.Floor(math.Log10(n))) + 1
+}
+
+// humanSizeRound rounds n up or down to the significant digits given; with
+// HumanSizeRoundNearest, n is returned as is for fmt to round.
+func humanSizeRound(n float64, digits int, rounding HumanSizeRounding) float64 {
+	if rounding == HumanSizeRoundNearest || n <= 0 {
+		return n
+	}
+	scale := math.Pow(10, float64(digits-humanSizeMagnitude(n)))
+	if rounding == HumanSizeRoundUp {
+		return math.Ceil(n*scale-1e-9) / scale
+	}
+	return math.Floor(n*scale+1e-9) / scale
 }
 
 // HumanSize1000 returns a more readable size format, such as
@@ -102,21 +222,203 @@ func HumanSize1024(v float64) string {
 	return HumanSize(v, 1024, []string{"", "K", "M", "G", "T", "P", "E", "Z", "Y"})
 }
 
+// HumanSizeBytes1000 returns a byte size with its unit at every tier, such as
+// HumanSizeBytes1000(123) giving "123B" and HumanSizeBytes1000(1234567)
+// giving "1.23MB". These are 1,000 unit based: 1kB = 1000, 1MB = 1000000,
+// etc.
+func HumanSizeBytes1000(v float64) string {
+	return HumanSizeFull(v, 1000, []string{"B", "kB", "MB", "GB", "TB", "PB", "EB", "ZB", "YB"})
+}
+
+// HumanSizeBytes1024 returns a byte size with its IEC unit at every tier,
+// such as HumanSizeBytes1024(123) giving "123B" and
+// HumanSizeBytes1024(1234567) giving "1.18MiB". These are 1,024 unit based:
+// 1KiB = 1024, 1MiB = 1048576, etc.
+func HumanSizeBytes1024(v float64) string {
+	return HumanSizeFull(v, 1024, []string{"B", "KiB", "MiB", "GiB", "TiB", "PiB", "EiB", "ZiB", "YiB"})
+}
+
+// SentenceTerminators are the characters Sentence accepts as already ending a
+// sentence; SentenceOptions.Terminators can give others.
+const SentenceTerminators = ".?!:…"
+
 // Sentence converts the value into a sentence, uppercasing the first character
-// and ensuring the string ends with a period. Useful to output better looking
-// error.Error() messages, which are all lower case with no trailing period by
-// convention.
+// and ensuring the string ends with a period, unless it already ends with one
+// of the SentenceTerminators. Useful to output better looking error.Error()
+// messages, which are all lower case with no trailing period by convention.
 func Sentence(value string) string {
-	if value != "" {
-		if value[len(value)-1] != '.' {
-			value = strings.ToUpper(value[:1]) + value[1:] + "."
-		} else {
-			value = strings.ToUpper(value[:1]) + value[1:]
+	if value == "" {
+		return value
+	}
+	value = upperFirst(value)
+	if r, _ := utf8.DecodeLastRuneInString(value); !strings.ContainsRune(SentenceTerminators, r) {
+		value += "."
+	}
+	return value
+}
+
+// SentenceOptions controls the finer points of SentenceWithOptions.
+type SentenceOptions struct {
+	// Terminators lists the characters accepted as already ending a
+	// sentence; a value ending with any of these will not have a period
+	// appended. If empty, SentenceTerminators is used.
+	Terminators string
+	// KeepCodeEndings will not append a period when the value ends with
+	// something that looks like a URL, a path, or a code snippet, where an
+	// extra period would be confusing.
+	KeepCodeEndings bool
+	// KeepIdentifierCase will not uppercase the first character when the
+	// first word looks like an identifier, such as "json:", "os.Open", or
+	// "maxRetries".
+	KeepIdentifierCase bool
+}
+
+// NewSentenceOptions gives:
+//
+//  &SentenceOptions{
+//      Terminators:        SentenceTerminators,
+//      KeepCodeEndings:    true,
+//      KeepIdentifierCase: true,
+//  }
+func NewSentenceOptions() *SentenceOptions {
+	return &SentenceOptions{
+		Terminators:        SentenceTerminators,
+		KeepCodeEndings:    true,
+		KeepIdentifierCase: true,
+	}
+}
+
+// SentenceWithOptions is like Sentence but with rules controlled by opts; see
+// SentenceOptions. If opts is nil the behavior is the same as Sentence.
+func SentenceWithOptions(value string, opts *SentenceOptions) string {
+	if value == "" {
+		return value
+	}
+	if opts == nil {
+		return Sentence(value)
+	}
+	first := value
+	if i := strings.IndexAny(first, " \t\n"); i != -1 {
+		first = first[:i]
+	}
+	if !opts.KeepIdentifierCase || !looksLikeIdentifier(first) {
+		value = upperFirst(value)
+	}
+	last := value
+	if i := strings.LastIndexAny(last, " \t\n"); i != -1 {
+		last = last[i+1:]
+	}
+	terminators := opts.Terminators
+	if terminators == "" {
+		terminators = SentenceTerminators
+	}
+	r, _ := utf8.DecodeLastRuneInString(value)
+	if strings.ContainsRune(terminators, r) {
+		return value
+	}
+	if opts.KeepCodeEndings && looksLikeCode(last) {
+		return value
+	}
+	return value + "."
+}
+
+// looksLikeIdentifier returns true if the word appears to be a program
+// identifier or label rather than an ordinary word.
+func looksLikeIdentifier(word string) bool {
+	if strings.HasSuffix(word, ":") {
+		return true
+	}
+	word = strings.TrimRight(word, ".,;!?")
+	if word == "" {
+		return false
+	}
+	if strings.ContainsAny(word, "_.()[]=/`") {
+		return true
+	}
+	for i, r := range word {
+		if i > 0 && unicode.IsUpper(r) {
+			return true
+		}
+	}
+	return false
+}
+
+// looksLikeCode returns true if the word appears to be a URL, path, or code
+// snippet that shouldn't have punctuation appended.
+func looksLikeCode(word string) bool {
+	return strings.Contains(word, "://") ||
+		strings.HasPrefix(word, "/") ||
+		strings.HasPrefix(word, "./") ||
+		strings.HasSuffix(word, "`") ||
+		strings.HasSuffix(word, "}") ||
+		strings.HasSuffix(word, ";")
+}
+
+// Sentences is like Sentence but for text made of several sentences, such as
+// a chain of concatenated error messages. Runs of whitespace are collapsed to
+// single spaces, the first character after each '.', '!', or '?' that ends a
+// word is uppercased, and the text is ensured to end with a period or another
+// of the SentenceTerminators.
+func Sentences(value string) string {
+	fields := strings.Fields(value)
+	if len(fields) == 0 {
+		return ""
+	}
+	capNext := true
+	for i, field := range fields {
+		if capNext {
+			fields[i] = upperFirst(field)
+		}
+		switch field[len(field)-1] {
+		case '.', '!', '?':
+			capNext = true
+		default:
+			capNext = false
 		}
 	}
+	value = strings.Join(fields, " ")
+	if r, _ := utf8.DecodeLastRuneInString(value); !strings.ContainsRune(SentenceTerminators, r) {
+		value += "."
+	}
 	return value
 }
 
+// upperFirst returns the value with its first rune uppercased.
+func upperFirst(value string) string {
+	r, size := utf8.DecodeRuneInString(value)
+	if r == utf8.RuneError {
+		return value
+	}
+	return string(unicode.ToUpper(r)) + value[size:]
+}
+
+// SentenceJoin joins the items into a readable list, such as "a", "a and b",
+// or "a, b, and c" when conj is "and". The serial (Oxford) comma is used; see
+// SentenceJoinOxford to control that.
+func SentenceJoin(items []string, conj string) string {
+	return SentenceJoinOxford(items, conj, true)
+}
+
+// SentenceJoinOxford is SentenceJoin with control over whether the serial
+// (Oxford) comma is used for lists of three or more items: "a, b, and c"
+// versus "a, b and c".
+func SentenceJoinOxford(items []string, conj string, oxford bool) string {
+	switch len(items) {
+	case 0:
+		return ""
+	case 1:
+		return items[0]
+	case 2:
+		return items[0] + " " + conj + " " + items[1]
+	}
+	last := len(items) - 1
+	s := strings.Join(items[:last], ", ")
+	if oxford {
+		s += ","
+	}
+	return s + " " + conj + " " + items[last]
+}
+
 // StringSliceToLowerSort provides a sort.Interface that will sort a []string
 // by their strings.ToLower values. This isn't exactly a case insensitive sort
 // due to Unicode situations, but is usually good enough.
@@ -143,58 +445,150 @@ func (s StringSliceToLowerSort) Less(x int, y int) bool {
 // The indent1 is the prefix for the first line.
 //
 // The indent2 is the prefix for any second or subsequent lines.
+//
+// Any tabs are expanded, see ExpandTabs, before wrapping.
+//
+// Soft hyphens (U+00AD) mark where a long word may be broken; they are
+// removed, with a "-" shown only at the end of a line broken at one. Zero
+// width spaces (U+200B) mark where a long token, like an ID or URL, may be
+// broken without a hyphen, and word joiners (U+2060) mark where it may not;
+// both are removed.
 func Wrap(text string, width int, indent1 string, indent2 string) string {
 	if width < 1 {
 		width = GetTTYWidth() - 1 + width
 	}
 	bs := []byte(text)
-	bs = wrap(bs, width, []byte(indent1), []byte(indent2))
+	bs = wrap(bs, width, []byte(indent1), []byte(indent2), nil)
 	return string(bytes.Trim(bs, "\n"))
 }
 
-func wrap(text []byte, width int, indent1 []byte, indent2 []byte) []byte {
+// WrapOptions controls the finer points of WrapWithOptions.
+type WrapOptions struct {
+	// Width has the same meaning as the width given to Wrap.
+	Width int
+	// Indent1 is the prefix for the first line.
+	Indent1 string
+	// Indent2 is the prefix for any second or subsequent lines.
+	Indent2 string
+	// NormalizeUnicode will convert the text to NFC before wrapping; see
+	// NormalizeUnicode.
+	NormalizeUnicode bool
+	// StripInvisible will remove zero width and control characters before
+	// wrapping; see StripInvisible.
+	StripInvisible bool
+	// ControlChars selects whether control characters, other than ANSI SGR
+	// sequences, are kept, stripped, or escaped before wrapping; see
+	// SanitizeControl.
+	ControlChars ControlChars
+	// BreakAfter lists extra characters a line may break after, such as
+	// "-/_," to let long hyphenated terms and path-like tokens wrap at
+	// sensible points rather than overflow. No space is added at these
+	// breaks.
+	BreakAfter string
+	// HardBreaks keeps Markdown style hard line breaks, lines ending with
+	// two or more spaces or a backslash, as line breaks that survive
+	// rewrapping, such as for addresses or verse.
+	HardBreaks bool
+}
+
+// WrapWithOptions is like Wrap but with the behavior controlled by opts; see
+// WrapOptions. If opts is nil, the defaults are the same as Wrap(text, 0, "",
+// "").
+func WrapWithOptions(text string, opts *WrapOptions) string {
+	if opts == nil {
+		opts = &WrapOptions{}
+	}
+	if opts.NormalizeUnicode {
+		text = NormalizeUnicode(text)
+	}
+	if opts.ControlChars != KeepControlChars {
+		text = SanitizeControl(text, opts.ControlChars)
+	}
+	if opts.StripInvisible {
+		text = StripInvisible(text)
+	}
+	width := opts.Width
+	if width < 1 {
+		width = GetTTYWidth() - 1 + width
+	}
+	bs := wrap([]byte(text), width, []byte(opts.Indent1), []byte(opts.Indent2), opts)
+	return string(bytes.Trim(bs, "\n"))
+}
+
+func wrap(text []byte, width int, indent1 []byte, indent2 []byte, opts *WrapOptions) []byte {
 	if utf8.RuneCount(text) == 0 {
 		return text
 	}
+	if opts == nil {
+		opts = &WrapOptions{}
+	}
 	text = bytes.Replace(text, []byte{'\r', '\n'}, []byte{'\n'}, -1)
+	if bytes.IndexByte(text, '\t') != -1 {
+		text = []byte(ExpandTabs(string(text), 8))
+	}
 	var out bytes.Buffer
 	for _, par := range bytes.Split([]byte(text), []byte{'\n', '\n'}) {
-		par = bytes.Replace(par, []byte{'\n'}, []byte{' '}, -1)
 		lineLen := 0
 		start := true
-		for _, word := range bytes.Split(par, []byte{' '}) {
-			wordLen := utf8.RuneCount(word)
-			if wordLen == 0 {
-				continue
-			}
-			scan := word
-			for len(scan) > 1 {
-				i := bytes.IndexByte(scan, '\x1b')
-				if i == -1 {
-					break
+		for s, segment := range wrapSegments(par, opts.HardBreaks) {
+			fresh := false
+			if s > 0 {
+				if start {
+					out.Write(indent1)
+					start = false
 				}
-				j := bytes.IndexByte(scan[i+1:], 'm')
-				if j != -1 {
-					j += 2
-					wordLen -= j
-					scan = scan[i+j:]
-				}
-			}
-			if start {
-				out.Write(indent1)
-				lineLen += utf8.RuneCount(indent1)
-				out.Write(word)
-				lineLen += wordLen
-				start = false
-			} else if lineLen+1+wordLen > width {
 				out.WriteByte('\n')
 				out.Write(indent2)
-				out.Write(word)
-				lineLen = utf8.RuneCount(indent2) + wordLen
-			} else {
-				out.WriteByte(' ')
-				out.Write(word)
-				lineLen += 1 + wordLen
+				lineLen = utf8.RuneCount(indent2)
+				fresh = true
+			}
+			for _, word := range bytes.Split(segment, []byte{' '}) {
+				if len(word) == 0 {
+					continue
+				}
+				pieces := wrapPieces(word, opts.BreakAfter)
+				hyphen := false
+				for i, piece := range pieces {
+					shy := bytes.HasSuffix(piece, softHyphen)
+					if shy {
+						piece = piece[:len(piece)-len(softHyphen)]
+					}
+					if bytes.Contains(piece, wordJoiner) {
+						piece = bytes.Replace(piece, wordJoiner, nil, -1)
+					}
+					pieceLen := DisplayWidth(string(piece))
+					need := pieceLen
+					if shy && i < len(pieces)-1 {
+						need++
+					}
+					if start {
+						out.Write(indent1)
+						lineLen += utf8.RuneCount(indent1)
+						out.Write(piece)
+						lineLen += pieceLen
+						start = false
+					} else if fresh {
+						out.Write(piece)
+						lineLen += pieceLen
+						fresh = false
+					} else if i > 0 && lineLen+need <= width {
+						out.Write(piece)
+						lineLen += pieceLen
+					} else if i == 0 && lineLen+1+need <= width {
+						out.WriteByte(' ')
+						out.Write(piece)
+						lineLen += 1 + pieceLen
+					} else {
+						if hyphen {
+							out.WriteByte('-')
+						}
+						out.WriteByte('\n')
+						out.Write(indent2)
+						out.Write(piece)
+						lineLen = utf8.RuneCount(indent2) + pieceLen
+					}
+					hyphen = shy
+				}
 			}
 		}
 		out.WriteByte('\n')
@@ -203,6 +597,133 @@ func wrap(text []byte, width int, indent1 []byte, indent2 []byte) []byte {
 	return out.Bytes()
 }
 
+// wrapSegments joins the lines of the paragraph with spaces, except, if
+// hardBreaks is true, after lines ending with two or more spaces or a
+// backslash; the paragraph is split there instead, with the break markers
+// removed.
+func wrapSegments(par []byte, hardBreaks bool) [][]byte {
+	if !hardBreaks {
+		return [][]byte{bytes.Replace(par, []byte{'\n'}, []byte{' '}, -1)}
+	}
+	var segments [][]byte
+	var segment []byte
+	lines := bytes.Split(par, []byte{'\n'})
+	for i, line := range lines {
+		hard := false
+		if i < len(lines)-1 {
+			if bytes.HasSuffix(line, []byte("  ")) {
+				line = bytes.TrimRight(line, " ")
+				hard = true
+			} else if bytes.HasSuffix(line, []byte{'\\'}) {
+				line = line[:len(line)-1]
+				hard = true
+			}
+		}
+		if len(segment) > 0 {
+			segment = append(segment, ' ')
+		}
+		segment = append(segment, line...)
+		if hard {
+			segments = append(segments, segment)
+			segment = nil
+		}
+	}
+	return append(segments, segment)
+}
+
+// softHyphen is U+00AD, which marks where a word may be hyphenated.
+var softHyphen = []byte("\u00ad")
+
+// wordJoiner is U+2060, which prevents a break where there would otherwise
+// be one.
+var wordJoiner = []byte("\u2060")
+
+// breakHints removes the soft hyphens, zero width spaces, and word joiners
+// that mark break points for wrapping, for text that isn't wrapped.
+var breakHints = strings.NewReplacer("\u00ad", "", "\u200b", "", "\u2060", "")
+
+// wrapPieces splits the word after any of the breakAfter characters that sit
+// between two other characters, giving the points a line may break within
+// the word. Runs of break characters, like the "--" of a flag, are not
+// broken. The word is also split after each soft hyphen, which is left at the
+// end of its piece; wrap removes them, showing a "-" only where a line
+// breaks. Zero width spaces (U+200B) split the word too, and are removed,
+// and a word joiner (U+2060) after a breakAfter character prevents the break
+// there.
+func wrapPieces(word []byte, breakAfter string) [][]byte {
+	if breakAfter == "" && !bytes.ContainsAny(word, "\u00ad\u200b") {
+		return [][]byte{word}
+	}
+	var pieces [][]byte
+	start := 0
+	var prev rune = -1
+	for i := 0; i < len(word); {
+		r, size := utf8.DecodeRune(word[i:])
+		next := i + size
+		if r == '\u00ad' {
+			if i > start {
+				pieces = append(pieces, word[start:next])
+			}
+			start = next
+		} else if r == '\u200b' {
+			if i > start {
+				pieces = append(pieces, word[start:i])
+			}
+			start = next
+		} else if breakAfter != "" && next < len(word) && prev != -1 && strings.ContainsRune(breakAfter, r) && !strings.ContainsRune(breakAfter, prev) {
+			if n, _ := utf8.DecodeRune(word[next:]); !strings.ContainsRune(breakAfter, n) && n != '\u2060' {
+				pieces = append(pieces, word[start:next])
+				start = next
+			}
+		}
+		prev = r
+		i = next
+	}
+	if start < len(word) || len(pieces) == 0 {
+		pieces = append(pieces, word[start:])
+	}
+	return pieces
+}
+
+// NumberLines prefixes each line of the text with its line number, starting
+// at start and right aligned to the width of the last line number. The format
+// is given the padded number as a string; if empty, "%s " is used, but
+// something like "%s | " may be more readable. A trailing newline does not
+// start a new numbered line. For example, NumberLines("a\nb\n", 9, "%s: ")
+// gives:
+//
+//   9: a
+//  10: b
+func NumberLines(text string, start int, format string) string {
+	if text == "" {
+		return text
+	}
+	if format == "" {
+		format = "%s "
+	}
+	trailing := strings.HasSuffix(text, "\n")
+	if trailing {
+		text = text[:len(text)-1]
+	}
+	lines := strings.Split(text, "\n")
+	width := len(strconv.Itoa(start + len(lines) - 1))
+	if w := len(strconv.Itoa(start)); w > width {
+		width = w
+	}
+	var buf bytes.Buffer
+	for i, line := range lines {
+		if i > 0 {
+			buf.WriteByte('\n')
+		}
+		fmt.Fprintf(&buf, format, fmt.Sprintf("%*d", width, start+i))
+		buf.WriteString(line)
+	}
+	if trailing {
+		buf.WriteByte('\n')
+	}
+	return buf.String()
+}
+
 // AllEqual returns true if all the values are equal strings; no strings,
 // AllEqual() or AllEqual([]string{}...), are considered AllEqual.
 func AllEqual(values ...string) bool {
@@ -218,6 +739,82 @@ func AllEqual(values ...string) bool {
 	return true
 }
 
+// AllEqualFold is like AllEqual but compares the values with Unicode case
+// folding, so AllEqualFold("Yes", "YES", "yes") is true; useful for checking
+// user provided values that may differ only by case.
+func AllEqualFold(values ...string) bool {
+	if len(values) < 2 {
+		return true
+	}
+	compare := foldString(values[0])
+	for _, v := range values[1:] {
+		if foldString(v) != compare {
+			return false
+		}
+	}
+	return true
+}
+
+// CommonPrefix returns the longest prefix shared by all the values; no values
+// give an empty string. The prefix will not end in the middle of a UTF-8
+// encoded rune; bytes that aren't valid UTF-8 are compared one at a time.
+func CommonPrefix(values ...string) string {
+	if len(values) == 0 {
+		return ""
+	}
+	prefix := values[0]
+	for _, v := range values[1:] {
+		i := 0
+		for i < len(prefix) && i < len(v) {
+			_, n := utf8.DecodeRuneInString(prefix[i:])
+			if _, m := utf8.DecodeRuneInString(v[i:]); m != n || prefix[i:i+n] != v[i:i+m] {
+				break
+			}
+			i += n
+		}
+		prefix = prefix[:i]
+	}
+	return prefix
+}
+
+// CommonSuffix returns the longest suffix shared by all the values; no values
+// give an empty string. The suffix will not begin in the middle of a UTF-8
+// encoded rune; bytes that aren't valid UTF-8 are compared one at a time.
+func CommonSuffix(values ...string) string {
+	if len(values) == 0 {
+		return ""
+	}
+	suffix := values[0]
+	for _, v := range values[1:] {
+		i := 0
+		for i < len(suffix) && i < len(v) {
+			_, n := utf8.DecodeLastRuneInString(suffix[:len(suffix)-i])
+			if _, m := utf8.DecodeLastRuneInString(v[:len(v)-i]); m != n || suffix[len(suffix)-i-n:len(suffix)-i] != v[len(v)-i-m:len(v)-i] {
+				break
+			}
+			i += n
+		}
+		suffix = suffix[len(suffix)-i:]
+	}
+	return suffix
+}
+
+// TrimCommonPrefix returns a new slice with the CommonPrefix of the values
+// removed from each, such as turning []string{"/var/log/a", "/var/log/b"} into
+// []string{"a", "b"}. With fewer than two values nothing is trimmed, since the
+// whole value would be the common prefix.
+func TrimCommonPrefix(values []string) []string {
+	out := make([]string, len(values))
+	prefix := ""
+	if len(values) > 1 {
+		prefix = CommonPrefix(values...)
+	}
+	for i, v := range values {
+		out[i] = v[len(prefix):]
+	}
+	return out
+}
+
 // TrueString returns true if the string contains a recognized true value, such
 // as "true", "True", "TRUE", "yes", "on", etc. Yes, there is already
 // strconv.ParseBool, but this function is often easier to work with since it
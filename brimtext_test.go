@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"math"
 	"sort"
+	"strings"
 	"testing"
 )
 
@@ -154,6 +155,50 @@ func TestHumanSize1024(t *testing.T) {
 	}
 }
 
+func TestHumanSizeBytes1000(t *testing.T) {
+	for i, v := range map[float64]string{
+		0:       "0B",
+		123:     "123B",
+		999:     "999B",
+		1000:    "1kB",
+		1234:    "1.23kB",
+		1234567: "1.23MB",
+		1e9:     "1GB",
+	} {
+		o := HumanSizeBytes1000(i)
+		if o != v {
+			t.Errorf("HumanSizeBytes1000(%f) %s != %s", i, o, v)
+		}
+	}
+}
+
+func TestHumanSizeBytes1024(t *testing.T) {
+	for i, v := range map[float64]string{
+		0:          "0B",
+		123:        "123B",
+		1000:       "0.98KiB",
+		1024:       "1KiB",
+		1234567:    "1.18MiB",
+		1073741824: "1GiB",
+	} {
+		o := HumanSizeBytes1024(i)
+		if o != v {
+			t.Errorf("HumanSizeBytes1024(%f) %s != %s", i, o, v)
+		}
+	}
+}
+
+func TestHumanSizeFull(t *testing.T) {
+	o := HumanSizeFull(12, 1000, []string{" b", " kb"})
+	if o != "12 b" {
+		t.Errorf("HumanSizeFull(12) %#v", o)
+	}
+	o = HumanSize(12, 1000, []string{" b", " kb"})
+	if o != "12" {
+		t.Errorf("HumanSize(12) %#v", o)
+	}
+}
+
 // func TestHumanSize1024(t *testing.T) {
 // 	for i, v := range map[float64]string{
 // 		0:                   "0",
@@ -183,6 +228,11 @@ func TestSentence(t *testing.T) {
 		"'testing'": "'testing'.",
 		"Testing.":  "Testing.",
 		"testing.":  "Testing.",
+		"really?":   "Really?",
+		"stop!":     "Stop!",
+		"note:":     "Note:",
+		"wait…":     "Wait…",
+		"été":       "Été.",
 	} {
 		out := Sentence(in)
 		if out != exp {
@@ -191,6 +241,95 @@ func TestSentence(t *testing.T) {
 	}
 }
 
+func TestSentenceWithOptions(t *testing.T) {
+	for in, exp := range map[string]string{
+		"":                             "",
+		"testing":                      "Testing.",
+		"testing.":                     "Testing.",
+		"really?":                      "Really?",
+		"stop!":                        "Stop!",
+		"json: cannot unmarshal":       "json: cannot unmarshal.",
+		"os.Open failed":               "os.Open failed.",
+		"maxRetries exceeded":          "maxRetries exceeded.",
+		"open file":                    "Open file.",
+		"see https://example.com/docs": "See https://example.com/docs",
+		"no such file /etc/app.conf":   "No such file /etc/app.conf",
+		"try running `make clean`":     "Try running `make clean`",
+		"\u00e9t\u00e9 is over":        "\u00c9t\u00e9 is over.",
+	} {
+		out := SentenceWithOptions(in, NewSentenceOptions())
+		if out != exp {
+			t.Errorf("SentenceWithOptions(%#v) %#v != %#v", in, out, exp)
+		}
+	}
+	for in, exp := range map[string]string{
+		"really?":      "Really?",
+		"json: failed": "Json: failed.",
+	} {
+		out := SentenceWithOptions(in, nil)
+		if out != exp {
+			t.Errorf("SentenceWithOptions(%#v, nil) %#v != %#v", in, out, exp)
+		}
+	}
+	out := SentenceWithOptions("done;", &SentenceOptions{Terminators: ";"})
+	if out != "Done;" {
+		t.Errorf("SentenceWithOptions custom terminators %#v != %#v", out, "Done;")
+	}
+}
+
+func TestSentences(t *testing.T) {
+	for in, exp := range map[string]string{
+		"":                  "",
+		"   ":               "",
+		"testing":           "Testing.",
+		"testing.":          "Testing.",
+		"one. two":          "One. Two.",
+		"one.  two!  three": "One. Two! Three.",
+		"really? yes":       "Really? Yes.",
+		"one. really?":      "One. Really?",
+		"open config: permission denied.\nretry failed": "Open config: permission denied. Retry failed.",
+		"version 1.5 is out. \u00e9t\u00e9 arrives":     "Version 1.5 is out. \u00c9t\u00e9 arrives.",
+	} {
+		out := Sentences(in)
+		if out != exp {
+			t.Errorf("Sentences(%#v) %#v != %#v", in, out, exp)
+		}
+	}
+}
+
+func TestSentenceJoin(t *testing.T) {
+	for _, v := range []struct {
+		items  []string
+		conj   string
+		oxford bool
+		exp    string
+	}{
+		{nil, "and", true, ""},
+		{[]string{"a"}, "and", true, "a"},
+		{[]string{"a", "b"}, "and", true, "a and b"},
+		{[]string{"a", "b"}, "or", false, "a or b"},
+		{[]string{"a", "b", "c"}, "and", true, "a, b, and c"},
+		{[]string{"a", "b", "c"}, "and", false, "a, b and c"},
+		{[]string{"a", "b", "c", "d"}, "or", true, "a, b, c, or d"},
+	} {
+		out := SentenceJoinOxford(v.items, v.conj, v.oxford)
+		if out != v.exp {
+			t.Errorf("SentenceJoinOxford(%#v, %#v, %v) %#v != %#v", v.items, v.conj, v.oxford, out, v.exp)
+		}
+		if v.oxford {
+			out = SentenceJoin(v.items, v.conj)
+			if out != v.exp {
+				t.Errorf("SentenceJoin(%#v, %#v) %#v != %#v", v.items, v.conj, out, v.exp)
+			}
+		}
+	}
+	out := Sentence(SentenceJoin([]string{"missing name", "bad port"}, "and"))
+	exp := "Missing name and bad port."
+	if out != exp {
+		t.Errorf("Sentence(SentenceJoin(...)) %#v != %#v", out, exp)
+	}
+}
+
 func TestStringSliceToLowerSort(t *testing.T) {
 	out := []string{"DEF", "abc"}
 	sort.Sort(StringSliceToLowerSort(out))
@@ -302,6 +441,27 @@ paragraph.`
 	}
 }
 
+func TestNumberLines(t *testing.T) {
+	for _, v := range []struct {
+		text   string
+		start  int
+		format string
+		exp    string
+	}{
+		{"", 1, "", ""},
+		{"one", 1, "", "1 one"},
+		{"a\nb\n", 9, "%s: ", " 9: a\n10: b\n"},
+		{"a\n\nc", 1, "%s | ", "1 | a\n2 | \n3 | c"},
+		{"a\nb", -1, "", "-1 a\n 0 b"},
+		{"\x1b[1mbold\x1b[0m", 100, "%s|", "100|\x1b[1mbold\x1b[0m"},
+	} {
+		out := NumberLines(v.text, v.start, v.format)
+		if out != v.exp {
+			t.Errorf("NumberLines(%#v, %d, %#v) %#v != %#v", v.text, v.start, v.format, out, v.exp)
+		}
+	}
+}
+
 func TestAllEqual(t *testing.T) {
 	if !AllEqual() {
 		t.Fatal("")
@@ -332,6 +492,90 @@ func TestAllEqual(t *testing.T) {
 	}
 }
 
+func TestAllEqualFold(t *testing.T) {
+	if !AllEqualFold() {
+		t.Fatal("")
+	}
+	if !AllEqualFold("Bob") {
+		t.Fatal("")
+	}
+	if !AllEqualFold("bob", "Bob", "BOB") {
+		t.Fatal("")
+	}
+	if !AllEqualFold("Straße", "STRASSE", "strasse") {
+		t.Fatal("")
+	}
+	if !AllEqualFold("ΣΑΣ", "σας", "σασ") {
+		t.Fatal("")
+	}
+	if AllEqualFold("bob", "Bob", "sue") {
+		t.Fatal("")
+	}
+}
+
+func TestCommonPrefix(t *testing.T) {
+	for _, v := range []struct {
+		in  []string
+		exp string
+	}{
+		{nil, ""},
+		{[]string{"abc"}, "abc"},
+		{[]string{"abc", "abd"}, "ab"},
+		{[]string{"abc", "abd", "xyz"}, ""},
+		{[]string{"/var/log/a", "/var/log/b", "/var/lib"}, "/var/l"},
+		{[]string{"abc", "ab"}, "ab"},
+		{[]string{"\u00c0b", "\u00c1b"}, ""},
+		{[]string{"a\xffb", "a\xffc"}, "a\xff"},
+		{[]string{"a\xc3", "a\u00e0"}, "a"},
+		{[]string{"\xc3\xa0\xff", "\u00e0\u00e1"}, "\u00e0"},
+	} {
+		out := CommonPrefix(v.in...)
+		if out != v.exp {
+			t.Errorf("CommonPrefix(%#v) %#v != %#v", v.in, out, v.exp)
+		}
+	}
+}
+
+func TestCommonSuffix(t *testing.T) {
+	for _, v := range []struct {
+		in  []string
+		exp string
+	}{
+		{nil, ""},
+		{[]string{"abc"}, "abc"},
+		{[]string{"abc", "xbc"}, "bc"},
+		{[]string{"a.go", "bb.go", "c.txt"}, ""},
+		{[]string{"a.go", "bb.go"}, ".go"},
+		{[]string{"b\u00c0", "b\u0100"}, ""},
+		{[]string{"a\xffb", "c\xffb"}, "\xffb"},
+		{[]string{"\xa0b", "\u00e0b"}, "b"},
+		{[]string{"\xff\u00e0", "\u00e1\u00e0"}, "\u00e0"},
+	} {
+		out := CommonSuffix(v.in...)
+		if out != v.exp {
+			t.Errorf("CommonSuffix(%#v) %#v != %#v", v.in, out, v.exp)
+		}
+	}
+}
+
+func TestTrimCommonPrefix(t *testing.T) {
+	in := []string{"/var/log/a", "/var/log/b", "/var/log/cc"}
+	out := TrimCommonPrefix(in)
+	exp := []string{"a", "b", "cc"}
+	for i := range exp {
+		if out[i] != exp[i] {
+			t.Fatalf("TrimCommonPrefix fail at index %d %#v != %#v", i, out[i], exp[i])
+		}
+	}
+	if in[0] != "/var/log/a" {
+		t.Fatalf("TrimCommonPrefix modified its input")
+	}
+	out = TrimCommonPrefix([]string{"alone"})
+	if out[0] != "alone" {
+		t.Fatalf("TrimCommonPrefix of one value %#v != %#v", out[0], "alone")
+	}
+}
+
 func TestTrueString(t *testing.T) {
 	if TrueString("") {
 		t.Fatal("")
@@ -403,3 +647,150 @@ func TestFalseString(t *testing.T) {
 		t.Fatal("2")
 	}
 }
+
+func TestWrapWithOptionsBreakAfter(t *testing.T) {
+	in := "see a-very-long-hyphenated-term and /usr/local/share/doc/brimtext now"
+	out := WrapWithOptions(in, &WrapOptions{Width: 16, BreakAfter: "-/"})
+	exp := `see a-very-long-
+hyphenated-term
+and /usr/local/
+share/doc/
+brimtext now`
+	if out != exp {
+		t.Errorf("WrapWithOptions(%#v) %#v != %#v", in, out, exp)
+	}
+	in = "use --long-flag or x- or -y"
+	out = WrapWithOptions(in, &WrapOptions{Width: 8, BreakAfter: "-"})
+	exp = `use
+--long-
+flag or
+x- or -y`
+	if out != exp {
+		t.Errorf("WrapWithOptions(%#v) %#v != %#v", in, out, exp)
+	}
+	out = WrapWithOptions(in, &WrapOptions{Width: 8})
+	exp = `use
+--long-flag
+or x- or
+-y`
+	if out != exp {
+		t.Errorf("WrapWithOptions(%#v) %#v != %#v", in, out, exp)
+	}
+}
+
+func TestWrapPieces(t *testing.T) {
+	for _, v := range []struct {
+		in  string
+		exp []string
+	}{
+		{"a/b/c", []string{"a/", "b/", "c"}},
+		{"--flag", []string{"--flag"}},
+		{"a--b", []string{"a--b"}},
+		{"end-", []string{"end-"}},
+		{"x,y_z", []string{"x,", "y_", "z"}},
+		{"hy\u00adphen\u00adated", []string{"hy\u00ad", "phen\u00ad", "ated"}},
+		{"\u00adsoft\u00ad\u00ad", []string{"soft\u00ad"}},
+		{"a/b\u00adc", []string{"a/", "b\u00ad", "c"}},
+		{"ab\u200bcd\u200b", []string{"ab", "cd"}},
+		{"a/\u2060b/c", []string{"a/\u2060b/", "c"}},
+	} {
+		var out []string
+		for _, p := range wrapPieces([]byte(v.in), "/-,_") {
+			out = append(out, string(p))
+		}
+		if strings.Join(out, "|") != strings.Join(v.exp, "|") {
+			t.Errorf("wrapPieces(%#v) %#v != %#v", v.in, out, v.exp)
+		}
+	}
+}
+
+func TestWrapSoftHyphen(t *testing.T) {
+	in := "an extra\u00adordi\u00adnarily long word"
+	for width, exp := range map[int]string{
+		80: "an extraordinarily long word",
+		14: "an extraordi-\nnarily long\nword",
+		13: "an extraordi-\nnarily long\nword",
+		12: "an extra-\nordinarily\nlong word",
+		8:  "an\nextra-\nordi-\nnarily\nlong\nword",
+	} {
+		if out := Wrap(in, width, "", ""); out != exp {
+			t.Errorf("Wrap(%#v, %d) %#v != %#v", in, width, out, exp)
+		}
+	}
+	in = "x\u00adyz"
+	if out, exp := Wrap(in, 3, "", ""), "xyz"; out != exp {
+		t.Errorf("Wrap(%#v, 3) %#v != %#v", in, out, exp)
+	}
+}
+
+func TestWrapZeroWidthSpace(t *testing.T) {
+	in := "id 4f2a\u200b9c31\u200bb7e0 and a-\u2060b"
+	for width, exp := range map[int]string{
+		80: "id 4f2a9c31b7e0 and a-b",
+		12: "id 4f2a9c31\nb7e0 and a-b",
+		8:  "id 4f2a\n9c31b7e0\nand a-b",
+	} {
+		if out := Wrap(in, width, "", ""); out != exp {
+			t.Errorf("Wrap(%#v, %d) %#v != %#v", in, width, out, exp)
+		}
+	}
+	out := WrapWithOptions("long-term a-\u2060b", &WrapOptions{Width: 5, BreakAfter: "-"})
+	if exp := "long-\nterm\na-b"; out != exp {
+		t.Errorf("WrapWithOptions word joiner %#v != %#v", out, exp)
+	}
+}
+
+func TestWrapWithOptionsHardBreaks(t *testing.T) {
+	in := "Jane Doe  \n123 Main Street\\\nAustin, TX\nUSA\n\nA new paragraph that will be wrapped."
+	out := WrapWithOptions(in, &WrapOptions{Width: 20, Indent1: "> ", Indent2: "> ", HardBreaks: true})
+	exp := `> Jane Doe
+> 123 Main Street
+> Austin, TX USA
+
+> A new paragraph
+> that will be
+> wrapped.`
+	if out != exp {
+		t.Errorf("WrapWithOptions(%#v) %#v != %#v", in, out, exp)
+	}
+	out = WrapWithOptions(in, &WrapOptions{Width: 20})
+	exp = `Jane Doe 123 Main
+Street\ Austin, TX
+USA
+
+A new paragraph that
+will be wrapped.`
+	if out != exp {
+		t.Errorf("WrapWithOptions(%#v) %#v != %#v", in, out, exp)
+	}
+}
+
+func TestHumanSizeWithOptions(t *testing.T) {
+	for _, v := range []struct {
+		in   float64
+		opts *HumanSizeOptions
+		exp  string
+	}{
+		{-1500, nil, "-1.5k"},
+		{-1500000, nil, "-1.5m"},
+		{-12, nil, "-12"},
+		{1234567, nil, "1.23m"},
+		{1572864, &HumanSizeOptions{Base: 1024}, "1.5M"},
+		{-1610612736, &HumanSizeOptions{Base: 1024}, "-1.5G"},
+		{1234567, &HumanSizeOptions{Precision: 2}, "1.2m"},
+		{123456789, &HumanSizeOptions{Precision: 1}, "123m"},
+		{1234567, &HumanSizeOptions{Precision: 5}, "1.2346m"},
+		{1201, &HumanSizeOptions{Rounding: HumanSizeRoundUp}, "1.21k"},
+		{1209, &HumanSizeOptions{Rounding: HumanSizeRoundDown}, "1.2k"},
+		{1200, &HumanSizeOptions{Rounding: HumanSizeRoundUp}, "1.2k"},
+		{-1201, &HumanSizeOptions{Rounding: HumanSizeRoundUp}, "-1.21k"},
+		{12, &HumanSizeOptions{Suffixes: []string{" B", " kB"}, Full: true}, "12 B"},
+	} {
+		if out := HumanSizeWithOptions(v.in, v.opts); out != v.exp {
+			t.Errorf("HumanSizeWithOptions(%f, %#v) %#v != %#v", v.in, v.opts, out, v.exp)
+		}
+	}
+	if out := HumanSize1024(-1536); out != "-1.5K" {
+		t.Errorf("HumanSize1024(-1536) %#v", out)
+	}
+}
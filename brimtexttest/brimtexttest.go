@@ -0,0 +1,50 @@
+// Package brimtexttest contains helpers for testing the output of brimtext,
+// such as tables from Align, against expected "golden" text.
+package brimtexttest
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gholt/brimtext"
+)
+
+// Normalize returns the output with ANSI escape codes removed, "\r\n" line
+// endings converted to "\n", trailing whitespace removed from each line, and
+// leading and trailing blank lines removed, so only meaningful differences
+// are compared.
+func Normalize(output string) string {
+	output = strings.Replace(brimtext.StripANSIEscapes(output), "\r\n", "\n", -1)
+	lines := strings.Split(output, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " \t\r")
+	}
+	return strings.Trim(strings.Join(lines, "\n"), "\n")
+}
+
+// Diff returns a unified diff from want to got after both are normalized,
+// or an empty string if they match.
+func Diff(got string, want string) string {
+	return brimtext.UnifiedDiff(brimtext.DiffLines(Normalize(want)+"\n", Normalize(got)+"\n"), &brimtext.DiffOptions{
+		Context:  3,
+		FromFile: "want",
+		ToFile:   "got",
+	})
+}
+
+// Equal reports a test error, with a diff, if got doesn't match want after
+// both are normalized, returning true if they matched. For example:
+//
+//	brimtexttest.Equal(t, brimtext.Align(data, opts), `
+//	Name  Count
+//	alpha 1
+//	beta  22
+//	`)
+func Equal(t testing.TB, got string, want string) bool {
+	t.Helper()
+	if diff := Diff(got, want); diff != "" {
+		t.Errorf("output mismatch:\n%s", diff)
+		return false
+	}
+	return true
+}
@@ -0,0 +1,46 @@
+package brimtexttest
+
+import (
+	"testing"
+
+	"github.com/gholt/brimtext"
+)
+
+func TestNormalize(t *testing.T) {
+	for in, exp := range map[string]string{
+		"":                            "",
+		"\n\na  \r\nb\t\n\n":          "a\nb",
+		"\x1b[1mbold\x1b[0m   \nnext": "bold\nnext",
+		"  indent kept":               "  indent kept",
+	} {
+		if out := Normalize(in); out != exp {
+			t.Errorf("Normalize(%#v) %#v != %#v", in, out, exp)
+		}
+	}
+}
+
+func TestDiff(t *testing.T) {
+	if out := Diff("a  \nb\n", "\na\nb"); out != "" {
+		t.Errorf("Diff of matching output %#v", out)
+	}
+	out := Diff("a\nc\n", "a\nb\n")
+	exp := "--- want\n" +
+		"+++ got\n" +
+		"@@ -1,2 +1,2 @@\n" +
+		" a\n" +
+		"-b\n" +
+		"+c\n"
+	if out != exp {
+		t.Errorf("%#v != %#v", out, exp)
+	}
+}
+
+func TestEqual(t *testing.T) {
+	out := brimtext.Align([][]string{{"Name", "Count"}, {"alpha", "1"}}, nil)
+	if !Equal(t, out, `
+Name  Count
+alpha 1
+`) {
+		t.Errorf("Equal returned false")
+	}
+}
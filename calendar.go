@@ -0,0 +1,121 @@
+package brimtext
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CalendarOptions controls the output of CalendarMonth and CalendarYear.
+type CalendarOptions struct {
+	// FirstDay is the day each week starts on; the zero value is Sunday.
+	FirstDay time.Weekday
+	// Highlight lists dates to highlight; only the year, month, and day of
+	// each are considered.
+	Highlight []time.Time
+	// HighlightCode is the ANSI escape code used to highlight dates; if nil,
+	// ANSIEscape.Reverse is used.
+	HighlightCode []byte
+	// AlignOptions are used to lay out each month, such as
+	// NewUnicodeBoxedAlignOptions(); if nil, NewDefaultAlignOptions() is
+	// used.
+	AlignOptions *AlignOptions
+}
+
+// CalendarMonth returns the month as a small table headed by the month and
+// year, such as:
+//
+//	    October 2026
+//	Su Mo Tu We Th Fr Sa
+//	             1  2  3
+//	 4  5  6  7  8  9 10
+//	11 12 13 14 15 16 17
+//	18 19 20 21 22 23 24
+//	25 26 27 28 29 30 31
+//
+// If opts is nil, &CalendarOptions{} is used.
+func CalendarMonth(year int, month time.Month, opts *CalendarOptions) string {
+	return calendarMonth(year, month, opts, month.String()+" "+strconv.Itoa(year))
+}
+
+// CalendarYear returns all twelve months of the year, as from CalendarMonth,
+// in a grid three months across headed by the year. If opts is nil,
+// &CalendarOptions{} is used.
+func CalendarYear(year int, opts *CalendarOptions) string {
+	var lines []string
+	for month := time.January; month <= time.December; month += 3 {
+		var months []string
+		for m := month; m < month+3; m++ {
+			months = append(months, calendarMonth(year, m, opts, m.String()))
+		}
+		row := blockLines(JoinHorizontal("  ", months...))
+		if lines == nil {
+			lines = append(lines, calendarCenter(strconv.Itoa(year), blockWidth(row)), "")
+		} else {
+			lines = append(lines, "")
+		}
+		lines = append(lines, row...)
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+func calendarMonth(year int, month time.Month, opts *CalendarOptions, title string) string {
+	if opts == nil {
+		opts = &CalendarOptions{}
+	}
+	code := opts.HighlightCode
+	if code == nil {
+		code = ANSIEscape.Reverse
+	}
+	highlight := make(map[int]bool)
+	for _, t := range opts.Highlight {
+		if t.Year() == year && t.Month() == month {
+			highlight[t.Day()] = true
+		}
+	}
+	header := make([]string, 7)
+	alignments := make([]Alignment, 7)
+	for i := range header {
+		header[i] = ((opts.FirstDay + time.Weekday(i)) % 7).String()[:2]
+		alignments[i] = Right
+	}
+	alignOpts := NewDefaultAlignOptions()
+	if opts.AlignOptions != nil {
+		o := *opts.AlignOptions
+		alignOpts = &o
+	}
+	alignOpts.Alignments = alignments
+	data := [][]string{header}
+	if !AllEqual("", alignOpts.FirstNilFirstUDR, alignOpts.FirstNilFirstUDLR, alignOpts.FirstNilUDLR, alignOpts.FirstNilLR, alignOpts.FirstNilLastUDL) {
+		data = append(data, nil)
+	}
+	first := time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
+	days := first.AddDate(0, 1, -1).Day()
+	col := (int(first.Weekday()) - int(opts.FirstDay) + 7) % 7
+	week := make([]string, 7)
+	for day := 1; day <= days; day++ {
+		week[col] = strconv.Itoa(day)
+		if highlight[day] {
+			week[col] = string(code) + week[col] + string(ANSIEscape.Reset)
+		}
+		col++
+		if col == 7 || day == days {
+			data = append(data, week)
+			week = make([]string, 7)
+			col = 0
+		}
+	}
+	table := Align(data, alignOpts)
+	width := DisplayWidth(table[:strings.IndexByte(table, '\n')])
+	return calendarCenter(title, width) + "\n" + table
+}
+
+// calendarCenter returns the text with enough leading spaces to center it
+// within width.
+func calendarCenter(text string, width int) string {
+	pad := (width - DisplayWidth(text)) / 2
+	if pad < 0 {
+		pad = 0
+	}
+	return strings.Repeat(" ", pad) + text
+}
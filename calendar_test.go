@@ -0,0 +1,58 @@
+package brimtext
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCalendarMonth(t *testing.T) {
+	out := CalendarMonth(2026, time.October, nil)
+	exp := "    October 2026\n" +
+		"Su Mo Tu We Th Fr Sa\n" +
+		"             1  2  3\n" +
+		" 4  5  6  7  8  9 10\n" +
+		"11 12 13 14 15 16 17\n" +
+		"18 19 20 21 22 23 24\n" +
+		"25 26 27 28 29 30 31\n"
+	if out != exp {
+		t.Errorf("CalendarMonth %#v != %#v", out, exp)
+	}
+	out = CalendarMonth(2026, time.February, &CalendarOptions{
+		FirstDay:     time.Monday,
+		Highlight:    []time.Time{time.Date(2026, 2, 14, 12, 0, 0, 0, time.Local), time.Date(2025, 2, 15, 0, 0, 0, 0, time.UTC)},
+		AlignOptions: NewSimpleAlignOptions(),
+	})
+	exp = "           February 2026\n" +
+		"+----+----+----+----+----+----+----+\n" +
+		"| Mo | Tu | We | Th | Fr | Sa | Su |\n" +
+		"+----+----+----+----+----+----+----+\n" +
+		"|    |    |    |    |    |    |  1 |\n" +
+		"|  2 |  3 |  4 |  5 |  6 |  7 |  8 |\n" +
+		"|  9 | 10 | 11 | 12 | 13 | \x1b[7m14\x1b[0m | 15 |\n" +
+		"| 16 | 17 | 18 | 19 | 20 | 21 | 22 |\n" +
+		"| 23 | 24 | 25 | 26 | 27 | 28 |    |\n" +
+		"+----+----+----+----+----+----+----+\n"
+	if out != exp {
+		t.Errorf("CalendarMonth options %#v != %#v", out, exp)
+	}
+}
+
+func TestCalendarYear(t *testing.T) {
+	out := CalendarYear(2026, nil)
+	lines := strings.Split(out, "\n")
+	if lines[0] != "                              2026" {
+		t.Errorf("CalendarYear title %#v", lines[0])
+	}
+	exp := "      January               February               March"
+	if lines[2] != exp {
+		t.Errorf("CalendarYear months %#v != %#v", lines[2], exp)
+	}
+	exp = "25 26 27 28 29 30 31                        29 30 31"
+	if lines[8] != exp {
+		t.Errorf("CalendarYear weeks %#v != %#v", lines[8], exp)
+	}
+	if n := strings.Count(out, "Su Mo Tu We Th Fr Sa"); n != 12 {
+		t.Errorf("CalendarYear had %d months", n)
+	}
+}
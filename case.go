@@ -0,0 +1,157 @@
+package brimtext
+
+import (
+	"strings"
+	"unicode"
+)
+
+// identifierWords splits an identifier like "HTTPServer", "http_server",
+// "http-server", or "httpServer" into its component words. Runs of capitals
+// are treated as acronyms, so "HTTPServer" gives "HTTP" and "Server".
+func identifierWords(s string) []string {
+	var words []string
+	rs := []rune(s)
+	start := -1
+	for i, r := range rs {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			if start != -1 {
+				words = append(words, string(rs[start:i]))
+				start = -1
+			}
+			continue
+		}
+		if start == -1 {
+			start = i
+			continue
+		}
+		prev := rs[i-1]
+		split := false
+		if unicode.IsUpper(r) {
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) {
+				split = true
+			} else if unicode.IsUpper(prev) && i+1 < len(rs) && unicode.IsLower(rs[i+1]) {
+				split = true
+			}
+		}
+		if split {
+			words = append(words, string(rs[start:i]))
+			start = i
+		}
+	}
+	if start != -1 {
+		words = append(words, string(rs[start:]))
+	}
+	return words
+}
+
+// SnakeCase converts an identifier to snake_case, such as SnakeCase("HTTPServer")
+// giving "http_server".
+func SnakeCase(s string) string {
+	return strings.ToLower(strings.Join(identifierWords(s), "_"))
+}
+
+// ScreamingSnakeCase converts an identifier to SCREAMING_SNAKE_CASE, such as
+// ScreamingSnakeCase("maxRetryCount") giving "MAX_RETRY_COUNT".
+func ScreamingSnakeCase(s string) string {
+	return strings.ToUpper(strings.Join(identifierWords(s), "_"))
+}
+
+// KebabCase converts an identifier to kebab-case, such as
+// KebabCase("HTTPServer") giving "http-server".
+func KebabCase(s string) string {
+	return strings.ToLower(strings.Join(identifierWords(s), "-"))
+}
+
+// CamelCase converts an identifier to camelCase, such as
+// CamelCase("http_server") giving "httpServer".
+func CamelCase(s string) string {
+	words := identifierWords(s)
+	for i, w := range words {
+		if i == 0 {
+			words[i] = strings.ToLower(w)
+		} else {
+			words[i] = capitalizeWord(w)
+		}
+	}
+	return strings.Join(words, "")
+}
+
+// PascalCase converts an identifier to PascalCase, such as
+// PascalCase("http_server") giving "HttpServer".
+func PascalCase(s string) string {
+	words := identifierWords(s)
+	for i, w := range words {
+		words[i] = capitalizeWord(w)
+	}
+	return strings.Join(words, "")
+}
+
+// capitalizeWord returns the word with its first rune uppercased and the rest
+// lowercased.
+func capitalizeWord(w string) string {
+	rs := []rune(strings.ToLower(w))
+	if len(rs) > 0 {
+		rs[0] = unicode.ToUpper(rs[0])
+	}
+	return string(rs)
+}
+
+// titleSmallWords are left lowercase by TitleCase unless they begin or end
+// the title.
+var titleSmallWords = map[string]bool{
+	"a": true, "an": true, "and": true, "as": true, "at": true, "but": true,
+	"by": true, "for": true, "from": true, "in": true, "into": true,
+	"nor": true, "of": true, "on": true, "or": true, "per": true, "so": true,
+	"the": true, "to": true, "up": true, "via": true, "vs": true, "with": true,
+	"yet": true,
+}
+
+// TitleCase returns the text with headline-style capitalization, such as
+// TitleCase("the lord of the rings") giving "The Lord of the Rings". Short
+// articles, conjunctions, and prepositions are lowercased unless they are the
+// first or last word or follow a colon. Words that already contain an
+// uppercase letter past their first, like "iPhone" or "HTTP", are left as is.
+// Unlike strings.Title, existing spacing is preserved exactly.
+func TitleCase(s string) string {
+	fields := strings.Fields(s)
+	if len(fields) == 0 {
+		return s
+	}
+	var out strings.Builder
+	rest := s
+	forceCap := true
+	for i, field := range fields {
+		j := strings.Index(rest, field)
+		out.WriteString(rest[:j])
+		rest = rest[j+len(field):]
+		out.WriteString(titleWord(field, forceCap || i == len(fields)-1))
+		forceCap = strings.HasSuffix(field, ":")
+	}
+	out.WriteString(rest)
+	return out.String()
+}
+
+func titleWord(word string, forceCap bool) string {
+	rs := []rune(word)
+	start := 0
+	for start < len(rs) && !unicode.IsLetter(rs[start]) && !unicode.IsDigit(rs[start]) {
+		start++
+	}
+	if start == len(rs) {
+		return word
+	}
+	for _, r := range rs[start+1:] {
+		if unicode.IsUpper(r) {
+			return word
+		}
+	}
+	end := len(rs)
+	for end > start && !unicode.IsLetter(rs[end-1]) && !unicode.IsDigit(rs[end-1]) {
+		end--
+	}
+	if !forceCap && titleSmallWords[strings.ToLower(string(rs[start:end]))] {
+		return strings.ToLower(word)
+	}
+	rs[start] = unicode.ToUpper(rs[start])
+	return string(rs)
+}
@@ -0,0 +1,48 @@
+package brimtext
+
+import (
+	"testing"
+)
+
+func TestIdentifierCases(t *testing.T) {
+	for in, exp := range map[string][5]string{
+		"":               {"", "", "", "", ""},
+		"HTTPServer":     {"http_server", "HTTP_SERVER", "http-server", "httpServer", "HttpServer"},
+		"http_server":    {"http_server", "HTTP_SERVER", "http-server", "httpServer", "HttpServer"},
+		"http-server":    {"http_server", "HTTP_SERVER", "http-server", "httpServer", "HttpServer"},
+		"httpServer":     {"http_server", "HTTP_SERVER", "http-server", "httpServer", "HttpServer"},
+		"MAX_RETRY":      {"max_retry", "MAX_RETRY", "max-retry", "maxRetry", "MaxRetry"},
+		"UserID":         {"user_id", "USER_ID", "user-id", "userId", "UserId"},
+		"Base64Encode":   {"base64_encode", "BASE64_ENCODE", "base64-encode", "base64Encode", "Base64Encode"},
+		"  spaced  out ": {"spaced_out", "SPACED_OUT", "spaced-out", "spacedOut", "SpacedOut"},
+		"ÉtéChaud":       {"été_chaud", "ÉTÉ_CHAUD", "été-chaud", "étéChaud", "ÉtéChaud"},
+	} {
+		for i, f := range []func(string) string{SnakeCase, ScreamingSnakeCase, KebabCase, CamelCase, PascalCase} {
+			out := f(in)
+			if out != exp[i] {
+				t.Errorf("case %d of %#v %#v != %#v", i, in, out, exp[i])
+			}
+		}
+	}
+}
+
+func TestTitleCase(t *testing.T) {
+	for in, exp := range map[string]string{
+		"":                             "",
+		"   ":                          "   ",
+		"the lord of the rings":        "The Lord of the Rings",
+		"THE END of an era":            "THE END of an Era",
+		"what it's for":                "What It's For",
+		"a tale of two cities":         "A Tale of Two Cities",
+		"report: the state of things":  "Report: The State of Things",
+		"using an iPhone with HTTP":    "Using an iPhone with HTTP",
+		"(and) the \"quoted\" words":   "(And) the \"Quoted\" Words",
+		"keep  the   spacing":          "Keep  the   Spacing",
+		"über die brücke in the house": "Über Die Brücke in the House",
+	} {
+		out := TitleCase(in)
+		if out != exp {
+			t.Errorf("TitleCase(%#v) %#v != %#v", in, out, exp)
+		}
+	}
+}
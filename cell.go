@@ -0,0 +1,150 @@
+package brimtext
+
+import (
+	"context"
+	"strings"
+)
+
+// cellAlignUnset marks the cells of AlignCells without an Alignment of their
+// own.
+const cellAlignUnset Alignment = -1
+
+// Cell is a table cell with settings of its own that override those of its
+// column; see AlignCells.
+type Cell struct {
+	Value string
+	// Alignment is used for the cell instead of the column's alignment if
+	// AlignmentSet is true, such as to right align a total in a column of
+	// left aligned text.
+	Alignment    Alignment
+	AlignmentSet bool
+	// Style, if not nil, is the ANSI escape code to style each line of the
+	// value with, such as ANSIEscape.FRed; it doesn't affect the widths.
+	Style []byte
+	// ColSpan is the number of columns the cell covers, for section
+	// headings within a table or titles over groups of columns; values < 2
+	// cover just the one. A spanning cell is aligned within the columns it
+	// covers by the alignment of the first, isn't rewrapped, and widens the
+	// last of the columns if they are too narrow for it.
+	ColSpan int
+}
+
+// TextCell returns a Cell of the value with no settings of its own.
+func TextCell(value string) Cell {
+	return Cell{Value: value}
+}
+
+// AlignedCell returns a Cell of the value with the alignment given.
+func AlignedCell(value string, alignment Alignment) Cell {
+	return Cell{Value: value, Alignment: alignment, AlignmentSet: true}
+}
+
+// StyledCell returns a Cell of the value with the style given.
+func StyledCell(value string, style []byte) Cell {
+	return Cell{Value: value, Style: style}
+}
+
+// SpanCell returns a Cell of the value covering span columns.
+func SpanCell(value string, span int) Cell {
+	return Cell{Value: value, ColSpan: span}
+}
+
+// AlignCells is like Align but for rows of Cells, each of which may override
+// the alignment of its column, carry a style, and span columns. For example:
+//
+//	data := [][]brimtext.Cell{
+//		{brimtext.SpanCell("Fruit", 2)},
+//		{brimtext.TextCell("apples"), brimtext.TextCell("3")},
+//		{brimtext.TextCell("pears"), brimtext.TextCell("12")},
+//		nil,
+//		{brimtext.AlignedCell("Total", brimtext.Right), brimtext.StyledCell("15", brimtext.ANSIEscape.Bold)},
+//	}
+//
+// If opts is nil, NewDefaultAlignOptions is used.
+func AlignCells(data [][]Cell, opts *AlignOptions) string {
+	if len(data) == 0 {
+		return ""
+	}
+	if opts == nil {
+		opts = NewDefaultAlignOptions()
+	}
+	values := make([][]string, len(data))
+	var aligns [][]Alignment
+	var spans [][]int
+	styled := false
+	for r, row := range data {
+		if row == nil {
+			continue
+		}
+		columns := 0
+		for _, cell := range row {
+			columns += cellSpan(cell)
+		}
+		values[r] = make([]string, 0, columns)
+		for _, cell := range row {
+			c := len(values[r])
+			value := cell.Value
+			if cell.Style != nil && value != "" {
+				value = cellStyle(value, cell.Style)
+				styled = true
+			}
+			values[r] = append(values[r], value)
+			if n := cellSpan(cell); n > 1 {
+				for len(spans) <= r {
+					spans = append(spans, nil)
+				}
+				if spans[r] == nil {
+					spans[r] = make([]int, columns)
+					for i := range spans[r] {
+						spans[r][i] = 1
+					}
+				}
+				spans[r][c] = n
+				for i := 1; i < n; i++ {
+					spans[r][c+i] = 0
+					values[r] = append(values[r], "")
+				}
+			}
+			if !cell.AlignmentSet {
+				continue
+			}
+			for len(aligns) <= r {
+				aligns = append(aligns, nil)
+			}
+			if aligns[r] == nil {
+				aligns[r] = make([]Alignment, columns)
+				for i := range aligns[r] {
+					aligns[r][i] = cellAlignUnset
+				}
+			}
+			aligns[r][c] = cell.Alignment
+		}
+	}
+	if styled && !opts.ResetCells {
+		opts = opts.Clone()
+		opts.ResetCells = true
+	}
+	layout, _ := newAlignLayout(context.Background(), values, aligns, spans, -1, opts)
+	out, _ := alignRender(context.Background(), layout, opts)
+	return out
+}
+
+// cellSpan returns the number of columns the cell covers.
+func cellSpan(cell Cell) int {
+	if cell.ColSpan > 1 {
+		return cell.ColSpan
+	}
+	return 1
+}
+
+// cellStyle wraps each line of the value with the style and
+// ANSIEscape.Reset.
+func cellStyle(value string, style []byte) string {
+	lines := strings.Split(value, "\n")
+	for i, line := range lines {
+		if line != "" {
+			lines[i] = string(style) + line + string(ANSIEscape.Reset)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,82 @@
+package brimtext
+
+import (
+	"testing"
+)
+
+func TestAlignCells(t *testing.T) {
+	b, z := string(ANSIEscape.Bold), string(ANSIEscape.Reset)
+	data := [][]Cell{
+		{TextCell("Item"), TextCell("Count")},
+		nil,
+		{TextCell("apples"), TextCell("3")},
+		{TextCell("pears"), TextCell("12")},
+		nil,
+		{AlignedCell("Total", Right), StyledCell("15", ANSIEscape.Bold)},
+	}
+	opts := NewAlignOptions(WithAlignments(Left, Right))
+	out := AlignCells(data, opts)
+	exp := "Item   Count\n" +
+		"\n" +
+		"apples     3\n" +
+		"pears     12\n" +
+		"\n" +
+		" Total    " + b + "15" + z + "\n"
+	if out != exp {
+		t.Errorf("%#v != %#v", out, exp)
+	}
+	out = AlignCells([][]Cell{{TextCell("a"), AlignedCell("b\nc", Center)}, {TextCell("d"), TextCell("efg")}}, nil)
+	exp = "a  b\n" +
+		"   c\n" +
+		"d efg\n"
+	if out != exp {
+		t.Errorf("%#v != %#v", out, exp)
+	}
+	out = AlignCells([][]Cell{{StyledCell("one two", ANSIEscape.Bold), TextCell("x")}}, NewAlignOptions(WithWidths(3)))
+	exp = b + "one" + z + " x\n" +
+		"two" + z + " \n"
+	if out != exp {
+		t.Errorf("%#v != %#v", out, exp)
+	}
+	if out = AlignCells(nil, nil); out != "" {
+		t.Errorf("%#v != \"\"", out)
+	}
+}
+
+func TestAlignCellsColSpan(t *testing.T) {
+	data := [][]Cell{
+		{TextCell("Name"), TextCell("Count"), TextCell("Size")},
+		nil,
+		{SpanCell("Fruit", 3)},
+		{TextCell("apples"), TextCell("3"), TextCell("1k")},
+		{SpanCell("Vegetables, leafy", 2), TextCell("x")},
+		{TextCell("kale"), TextCell("12"), TextCell("4k")},
+	}
+	out := AlignCells(data, NewSimpleAlignOptions())
+	exp := "+--------+----------+------+\n" +
+		"| Name   | Count    | Size |\n" +
+		"+--------+----------+------+\n" +
+		"| Fruit                    |\n" +
+		"| apples | 3        | 1k   |\n" +
+		"| Vegetables, leafy | x    |\n" +
+		"| kale   | 12       | 4k   |\n" +
+		"+--------+----------+------+\n"
+	if out != exp {
+		t.Errorf("%#v != %#v", out, exp)
+	}
+	out = AlignCells(data, NewUnicodeBoxedAlignOptions())
+	exp = "╔════════╦══════════╤══════╗\n" +
+		"║ Name   ║ Count    │ Size ║\n" +
+		"╠════════╩══════════╧══════╣\n" +
+		"║ Fruit                    ║\n" +
+		"╟────────╥──────────┬──────╢\n" +
+		"║ apples ║ 3        │ 1k   ║\n" +
+		"╟────────╨──────────┼──────╢\n" +
+		"║ Vegetables, leafy │ x    ║\n" +
+		"╟────────╥──────────┼──────╢\n" +
+		"║ kale   ║ 12       │ 4k   ║\n" +
+		"╚════════╩══════════╧══════╝\n"
+	if out != exp {
+		t.Errorf("%#v != %#v", out, exp)
+	}
+}
@@ -0,0 +1,620 @@
+package brimtext
+
+import (
+	"bytes"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// SparklineOptions controls the output of SparklineWithOptions.
+type SparklineOptions struct {
+	// Min and Max pin the bottom and top of the scale; if they are equal the
+	// smallest and largest values given are used instead. Values outside the
+	// scale are clamped to it.
+	Min float64
+	Max float64
+	// ASCII uses "_.,-=+*#" rather than the Unicode block elements.
+	ASCII bool
+	// Color, if set, is called for each value to give the ANSI escape code to
+	// output it with, such as ANSIEscape.FRed for values over a threshold.
+	Color func(value float64) []byte
+}
+
+var sparklineUnicode = []rune("▁▂▃▄▅▆▇█")
+var sparklineASCII = []rune("_.,-=+*#")
+
+// Sparkline returns the values as a compact, single line chart, such as
+// "▁▂▄█▆▃", one rune per value. NaN and infinite values are output as
+// spaces.
+func Sparkline(values []float64) string {
+	return SparklineWithOptions(values, nil)
+}
+
+// SparklineWithOptions is like Sparkline but with the output controlled by
+// opts; see SparklineOptions. If opts is nil, the defaults are the same as
+// Sparkline.
+func SparklineWithOptions(values []float64, opts *SparklineOptions) string {
+	if opts == nil {
+		opts = &SparklineOptions{}
+	}
+	levels := sparklineUnicode
+	if opts.ASCII {
+		levels = sparklineASCII
+	}
+	min, max := opts.Min, opts.Max
+	if min == max {
+		min, max = math.Inf(1), math.Inf(-1)
+		for _, v := range values {
+			if math.IsNaN(v) || math.IsInf(v, 0) {
+				continue
+			}
+			min = math.Min(min, v)
+			max = math.Max(max, v)
+		}
+	}
+	var buf bytes.Buffer
+	for _, v := range values {
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			buf.WriteByte(' ')
+			continue
+		}
+		level := 0
+		if max > min {
+			level = int((math.Max(min, math.Min(max, v)) - min) / (max - min) * float64(len(levels)-1))
+		}
+		if level < 0 {
+			level = 0
+		} else if level > len(levels)-1 {
+			level = len(levels) - 1
+		}
+		if opts.Color != nil {
+			buf.Write(opts.Color(v))
+			buf.WriteRune(levels[level])
+			buf.Write(ANSIEscape.Reset)
+		} else {
+			buf.WriteRune(levels[level])
+		}
+	}
+	return buf.String()
+}
+
+// BarChartOptions controls the output of BarChart.
+type BarChartOptions struct {
+	// Width is the total width to fit the chart to, with the same meaning as
+	// the width given to Wrap.
+	Width int
+	// Max pins the value that gives a full width bar; if 0 the largest value
+	// is used.
+	Max float64
+	// Format, if set, formats each value, such as HumanSize1024; otherwise
+	// the shortest representation of the value is used.
+	Format func(value float64) string
+	// ASCII uses "#" for the bars rather than the Unicode block elements.
+	ASCII bool
+}
+
+var barEighths = []rune("▏▎▍▌▋▊▉")
+
+// BarChart returns a horizontal bar chart with a line for each label and
+// value, the labels padded to line up, the bars scaled to fit the width, and
+// the formatted values right aligned after them:
+//
+//	/var  ████████████████████████████████  1.18G
+//	/usr  ██████████▌                        391M
+//	/tmp  ▏                                    12K
+//
+// Negative, NaN, and infinite values are shown with empty bars. If opts is
+// nil, &BarChartOptions{} is used.
+func BarChart(labels []string, values []float64, opts *BarChartOptions) string {
+	if opts == nil {
+		opts = &BarChartOptions{}
+	}
+	if len(labels) == 0 && len(values) == 0 {
+		return ""
+	}
+	width := opts.Width
+	if width < 1 {
+		width = GetTTYWidth() - 1 + width
+	}
+	rows := len(labels)
+	if len(values) > rows {
+		rows = len(values)
+	}
+	formatted := make([]string, rows)
+	labelWidth, valueWidth := 0, 0
+	max := opts.Max
+	for i := 0; i < rows; i++ {
+		if i < len(values) {
+			if opts.Format != nil {
+				formatted[i] = opts.Format(values[i])
+			} else {
+				formatted[i] = strconv.FormatFloat(values[i], 'g', -1, 64)
+			}
+			if opts.Max == 0 && values[i] > max && !math.IsInf(values[i], 0) {
+				max = values[i]
+			}
+		}
+		if i < len(labels) {
+			if w := DisplayWidth(labels[i]); w > labelWidth {
+				labelWidth = w
+			}
+		}
+		if w := DisplayWidth(formatted[i]); w > valueWidth {
+			valueWidth = w
+		}
+	}
+	barWidth := width - labelWidth - valueWidth - 4
+	if barWidth < 1 {
+		barWidth = 1
+	}
+	var buf bytes.Buffer
+	for i := 0; i < rows; i++ {
+		label := ""
+		if i < len(labels) {
+			label = labels[i]
+		}
+		bar := ""
+		if i < len(values) && max > 0 && values[i] > 0 && !math.IsInf(values[i], 0) {
+			bar = chartBar(math.Min(values[i], max)/max*float64(barWidth), opts.ASCII)
+		}
+		buf.WriteString(label)
+		buf.WriteString(strings.Repeat(" ", labelWidth-DisplayWidth(label)+2))
+		buf.WriteString(bar)
+		buf.WriteString(strings.Repeat(" ", barWidth-DisplayWidth(bar)+2+valueWidth-DisplayWidth(formatted[i])))
+		buf.WriteString(formatted[i])
+		buf.WriteByte('\n')
+	}
+	return buf.String()
+}
+
+// chartNiceStep returns the smallest 1, 2, or 5 times a power of ten that is
+// at least step, giving tick values that read well.
+func chartNiceStep(step float64) float64 {
+	if step <= 0 {
+		return 1
+	}
+	magnitude := math.Pow(10, math.Floor(math.Log10(step)))
+	switch n := step / magnitude; {
+	case n <= 1:
+		return magnitude
+	case n <= 2:
+		return 2 * magnitude
+	case n <= 5:
+		return 5 * magnitude
+	}
+	return 10 * magnitude
+}
+
+// chartBar returns a bar columns wide, using partial block elements for the
+// fractional part unless ascii is true. Any positive value gives at least a
+// sliver of a bar.
+func chartBar(columns float64, ascii bool) string {
+	if ascii {
+		n := int(columns + 0.5)
+		if n < 1 {
+			n = 1
+		}
+		return strings.Repeat("#", n)
+	}
+	full := int(columns)
+	eighths := int((columns - float64(full)) * 8)
+	bar := strings.Repeat("█", full)
+	if eighths > 0 {
+		bar += string(barEighths[eighths-1])
+	} else if full == 0 {
+		bar = string(barEighths[0])
+	}
+	return bar
+}
+
+// HistogramBucket is a range of values and how many values fell within it,
+// Min inclusive and Max exclusive, except for the last bucket from
+// HistogramBuckets which includes its Max.
+type HistogramBucket struct {
+	Min   float64
+	Max   float64
+	Count int
+}
+
+// HistogramBuckets splits the range of the values into count equal width
+// buckets and counts the values within each; NaN and infinite values are
+// ignored. If
+// count < 1, 10 buckets are used. If all the values are the same, a single
+// bucket is returned.
+func HistogramBuckets(values []float64, count int) []HistogramBucket {
+	if count < 1 {
+		count = 10
+	}
+	min, max := math.NaN(), math.NaN()
+	for _, v := range values {
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			continue
+		}
+		if math.IsNaN(min) || v < min {
+			min = v
+		}
+		if math.IsNaN(max) || v > max {
+			max = v
+		}
+	}
+	if math.IsNaN(min) {
+		return nil
+	}
+	if min == max {
+		n := 0
+		for _, v := range values {
+			if !math.IsNaN(v) && !math.IsInf(v, 0) {
+				n++
+			}
+		}
+		return []HistogramBucket{{Min: min, Max: max, Count: n}}
+	}
+	buckets := make([]HistogramBucket, count)
+	step := (max - min) / float64(count)
+	for i := range buckets {
+		buckets[i].Min = min + step*float64(i)
+		buckets[i].Max = min + step*float64(i+1)
+	}
+	buckets[count-1].Max = max
+	for _, v := range values {
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			continue
+		}
+		i := int((v - min) / step)
+		if i < 0 {
+			i = 0
+		} else if i >= count {
+			i = count - 1
+		}
+		buckets[i].Count++
+	}
+	return buckets
+}
+
+// HistogramOptions controls the output of Histogram.
+type HistogramOptions struct {
+	// Width is the total width to fit the histogram to, with the same
+	// meaning as the width given to Wrap.
+	Width int
+	// Format formats the bucket boundaries; if nil, HumanSize1000 is used.
+	Format func(value float64) string
+	// ASCII uses "#" for the bars rather than the Unicode block elements.
+	ASCII bool
+}
+
+// Histogram returns a line for each bucket giving its range, count,
+// percentage of the total count, and a bar scaled to the largest count:
+//
+//	 0 - 10  12  30.0%  ████████████████▌
+//	10 - 20  21  52.5%  █████████████████████████████
+//	20 - 30   7  17.5%  █████▊
+//
+// Use HistogramBuckets to build buckets from raw values. If opts is nil,
+// &HistogramOptions{} is used.
+func Histogram(buckets []HistogramBucket, opts *HistogramOptions) string {
+	if opts == nil {
+		opts = &HistogramOptions{}
+	}
+	if len(buckets) == 0 {
+		return ""
+	}
+	format := opts.Format
+	if format == nil {
+		format = HumanSize1000
+	}
+	width := opts.Width
+	if width < 1 {
+		width = GetTTYWidth() - 1 + width
+	}
+	total, most := 0, 0
+	for _, b := range buckets {
+		total += b.Count
+		if b.Count > most {
+			most = b.Count
+		}
+	}
+	cols := make([][4]string, len(buckets))
+	var widths [4]int
+	for i, b := range buckets {
+		pct := 0.0
+		if total > 0 {
+			pct = float64(b.Count) * 100 / float64(total)
+		}
+		cols[i] = [4]string{format(b.Min), format(b.Max), strconv.Itoa(b.Count), strconv.FormatFloat(pct, 'f', 1, 64) + "%"}
+		for j, c := range cols[i] {
+			if w := DisplayWidth(c); w > widths[j] {
+				widths[j] = w
+			}
+		}
+	}
+	barWidth := width - widths[0] - widths[1] - widths[2] - widths[3] - 9
+	if barWidth < 1 {
+		barWidth = 1
+	}
+	var buf bytes.Buffer
+	for i, b := range buckets {
+		c := cols[i]
+		buf.WriteString(strings.Repeat(" ", widths[0]-DisplayWidth(c[0])))
+		buf.WriteString(c[0])
+		buf.WriteString(" - ")
+		buf.WriteString(c[1])
+		buf.WriteString(strings.Repeat(" ", widths[1]-DisplayWidth(c[1])+2+widths[2]-DisplayWidth(c[2])))
+		buf.WriteString(c[2])
+		buf.WriteString(strings.Repeat(" ", widths[3]-DisplayWidth(c[3])+2))
+		buf.WriteString(c[3])
+		if b.Count > 0 {
+			buf.WriteString("  ")
+			buf.WriteString(chartBar(float64(b.Count)/float64(most)*float64(barWidth), opts.ASCII))
+		}
+		buf.WriteByte('\n')
+	}
+	return buf.String()
+}
+
+// TimelineSpan is a labeled interval for Timeline; Start and End are in
+// whatever units the caller likes, such as seconds since the first step.
+type TimelineSpan struct {
+	Label string
+	Start float64
+	End   float64
+}
+
+// TimelineOptions controls the output of Timeline.
+type TimelineOptions struct {
+	// Width is the total width to fit the timeline to, with the same meaning
+	// as the width given to Wrap.
+	Width int
+	// Ticks is the number of tick labels on the axis; if < 2, one tick about
+	// every ten columns is used.
+	Ticks int
+	// Format, if set, formats the tick labels; otherwise the shortest
+	// representation of the value is used.
+	Format func(value float64) string
+	// ASCII uses "=", "-", and "+" rather than Unicode block and box drawing
+	// characters.
+	ASCII bool
+}
+
+// Timeline returns a horizontal timeline, or Gantt chart, with a line for
+// each span, its bar placed and scaled along a shared axis, followed by the
+// axis and its tick labels:
+//
+//	fetch    ███
+//	compile     █████████████████
+//	test                     ███████████████████████
+//	         ┬───────────────┬────────────────┬──────
+//	         0               5                10
+//
+// Infinite starts and ends are drawn to the edge of the axis, which spans
+// only the finite ones; spans with a NaN start or end get no bar. If opts is
+// nil, &TimelineOptions{} is used.
+func Timeline(spans []TimelineSpan, opts *TimelineOptions) string {
+	if opts == nil {
+		opts = &TimelineOptions{}
+	}
+	if len(spans) == 0 {
+		return ""
+	}
+	width := opts.Width
+	if width < 1 {
+		width = GetTTYWidth() - 1 + width
+	}
+	format := opts.Format
+	if format == nil {
+		format = func(value float64) string { return strconv.FormatFloat(value, 'g', -1, 64) }
+	}
+	bar, rule, tick := "█", "─", "┬"
+	if opts.ASCII {
+		bar, rule, tick = "=", "-", "+"
+	}
+	min, max := math.Inf(1), math.Inf(-1)
+	labelWidth := 0
+	for _, s := range spans {
+		for _, v := range []float64{s.Start, s.End} {
+			if !math.IsNaN(v) && !math.IsInf(v, 0) {
+				min = math.Min(min, v)
+				max = math.Max(max, v)
+			}
+		}
+		if w := DisplayWidth(s.Label); w > labelWidth {
+			labelWidth = w
+		}
+	}
+	if min > max {
+		min, max = 0, 0
+	}
+	lastLabel := format(max)
+	axisWidth := width - labelWidth - 2 - DisplayWidth(lastLabel) + 1
+	if axisWidth < 2 {
+		axisWidth = 2
+	}
+	column := func(v float64) float64 {
+		if max <= min {
+			return 0
+		}
+		return (math.Max(min, math.Min(max, v)) - min) / (max - min) * float64(axisWidth-1)
+	}
+	pad := strings.Repeat(" ", labelWidth+2)
+	var buf bytes.Buffer
+	for _, s := range spans {
+		buf.WriteString(s.Label)
+		if math.IsNaN(s.Start) || math.IsNaN(s.End) {
+			buf.WriteByte('\n')
+			continue
+		}
+		start := int(column(s.Start) + 0.5)
+		end := int(column(s.End) + 0.5)
+		if end <= start {
+			end = start + 1
+		}
+		buf.WriteString(strings.Repeat(" ", labelWidth-DisplayWidth(s.Label)+2+start))
+		buf.WriteString(strings.Repeat(bar, end-start))
+		buf.WriteByte('\n')
+	}
+	ticks := opts.Ticks
+	if ticks < 2 {
+		ticks = axisWidth/10 + 1
+	}
+	axis := make([]string, axisWidth)
+	for i := range axis {
+		axis[i] = rule
+	}
+	var labels bytes.Buffer
+	if max > min {
+		step := chartNiceStep((max - min) / float64(ticks-1))
+		first := math.Ceil(min/step) * step
+		at := 0
+		for i := 0; ; i++ {
+			v := first + step*float64(i)
+			if v > max+step/1e6 {
+				break
+			}
+			col := int(column(v) + 0.5)
+			axis[col] = tick
+			if col < at {
+				continue
+			}
+			labels.WriteString(strings.Repeat(" ", col-at))
+			label := format(v)
+			labels.WriteString(label)
+			at = col + DisplayWidth(label) + 1
+			labels.WriteByte(' ')
+		}
+	} else {
+		axis[0] = tick
+		labels.WriteString(format(min))
+	}
+	buf.WriteString(pad)
+	buf.WriteString(strings.Join(axis, ""))
+	buf.WriteByte('\n')
+	buf.WriteString(pad)
+	buf.WriteString(strings.TrimRight(labels.String(), " "))
+	buf.WriteByte('\n')
+	return buf.String()
+}
+
+// BrailleOptions controls the output of BraillePlot.
+type BrailleOptions struct {
+	// Width is the width of the plot in characters, each holding two values
+	// across; if < 1, enough width for all the values is used. If there are
+	// more values than fit, neighboring values are averaged together.
+	Width int
+	// Height is the height of the plot in lines, each holding four dots
+	// down; if < 1, 4 is used.
+	Height int
+	// Min and Max pin the bottom and top of the plot; if they are equal the
+	// smallest and largest values given are used instead. Values outside the
+	// range are clamped to it.
+	Min float64
+	Max float64
+}
+
+// brailleDots gives the bit for each dot of a braille pattern character,
+// indexed by [row][column].
+var brailleDots = [4][2]rune{{0x01, 0x08}, {0x02, 0x10}, {0x04, 0x20}, {0x40, 0x80}}
+
+// BraillePlot returns the values as a line chart drawn with braille pattern
+// characters, giving a 2x4 grid of dots per character for much finer detail
+// than Sparkline. Consecutive values are joined by vertical runs of dots and
+// NaN and infinite values leave gaps. The result is a block of Height lines, each padded
+// to the full Width and ending with a newline, suitable for JoinHorizontal.
+// If opts is nil, &BrailleOptions{} is used.
+func BraillePlot(values []float64, opts *BrailleOptions) string {
+	if opts == nil {
+		opts = &BrailleOptions{}
+	}
+	if len(values) == 0 {
+		return ""
+	}
+	width := opts.Width
+	if width < 1 {
+		width = (len(values) + 1) / 2
+	}
+	height := opts.Height
+	if height < 1 {
+		height = 4
+	}
+	if len(values) > width*2 {
+		values = brailleResample(values, width*2)
+	}
+	min, max := opts.Min, opts.Max
+	if min == max {
+		min, max = math.Inf(1), math.Inf(-1)
+		for _, v := range values {
+			if !math.IsNaN(v) && !math.IsInf(v, 0) {
+				min = math.Min(min, v)
+				max = math.Max(max, v)
+			}
+		}
+	}
+	rows := height * 4
+	grid := make([][]rune, height)
+	for i := range grid {
+		grid[i] = make([]rune, width)
+	}
+	set := func(x, y int) {
+		y = rows - 1 - y
+		grid[y/4][x/2] |= brailleDots[y%4][x%2]
+	}
+	prev := -1
+	for x, v := range values {
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			prev = -1
+			continue
+		}
+		y := 0
+		if max > min {
+			y = int((math.Min(math.Max(v, min), max)-min)/(max-min)*float64(rows-1) + 0.5)
+		}
+		if y < 0 || y >= rows {
+			y = 0
+		}
+		from, to := y, y
+		if prev >= 0 {
+			if prev < y {
+				from = prev + 1
+			} else if prev > y {
+				to = prev - 1
+			}
+		}
+		for i := from; i <= to; i++ {
+			set(x, i)
+		}
+		prev = y
+	}
+	var buf bytes.Buffer
+	for _, line := range grid {
+		for _, r := range line {
+			if r == 0 {
+				buf.WriteByte(' ')
+			} else {
+				buf.WriteRune(0x2800 | r)
+			}
+		}
+		buf.WriteByte('\n')
+	}
+	return buf.String()
+}
+
+// brailleResample averages the values down to n values; NaN and infinite
+// values are ignored unless a group has nothing else.
+func brailleResample(values []float64, n int) []float64 {
+	resampled := make([]float64, n)
+	for i := range resampled {
+		start, end := i*len(values)/n, (i+1)*len(values)/n
+		sum, count := 0.0, 0
+		for _, v := range values[start:end] {
+			if !math.IsNaN(v) && !math.IsInf(v, 0) {
+				sum += v
+				count++
+			}
+		}
+		if count == 0 {
+			resampled[i] = math.NaN()
+		} else {
+			resampled[i] = sum / float64(count)
+		}
+	}
+	return resampled
+}
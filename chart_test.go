@@ -0,0 +1,218 @@
+package brimtext
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestSparkline(t *testing.T) {
+	for _, v := range []struct {
+		in  []float64
+		exp string
+	}{
+		{nil, ""},
+		{[]float64{5}, "▁"},
+		{[]float64{1, 1, 1}, "▁▁▁"},
+		{[]float64{0, 1, 2, 3, 4, 5, 6, 7}, "▁▂▃▄▅▆▇█"},
+		{[]float64{10, math.NaN(), 0}, "█ ▁"},
+		{[]float64{-1, 0, 1}, "▁▄█"},
+	} {
+		out := Sparkline(v.in)
+		if out != v.exp {
+			t.Errorf("Sparkline(%v) %#v != %#v", v.in, out, v.exp)
+		}
+	}
+}
+
+func TestSparklineWithOptions(t *testing.T) {
+	out := SparklineWithOptions([]float64{0, 50, 100, 200}, &SparklineOptions{Min: 0, Max: 100, ASCII: true})
+	exp := "_-##"
+	if out != exp {
+		t.Errorf("SparklineWithOptions pinned %#v != %#v", out, exp)
+	}
+	opts := &SparklineOptions{Color: func(v float64) []byte {
+		if v > 1 {
+			return ANSIEscape.FRed
+		}
+		return ANSIEscape.FGreen
+	}}
+	out = SparklineWithOptions([]float64{0, 2}, opts)
+	exp = "\x1b[32m▁\x1b[0m\x1b[31m█\x1b[0m"
+	if out != exp {
+		t.Errorf("SparklineWithOptions color %#v != %#v", out, exp)
+	}
+}
+
+func TestBarChart(t *testing.T) {
+	out := BarChart([]string{"/var", "/usr", "/tmp"}, []float64{1000, 250, 1}, &BarChartOptions{Width: 24, Format: HumanSize1000})
+	exp := "/var  █████████████   1k\n" +
+		"/usr  ███▎           250\n" +
+		"/tmp  ▏                1\n"
+	if out != exp {
+		t.Errorf("BarChart %#v != %#v", out, exp)
+	}
+	out = BarChart([]string{"a", "bb"}, []float64{3, -1}, &BarChartOptions{Width: 20, Max: 4, ASCII: true})
+	exp = "a   #########      3\n" +
+		"bb                -1\n"
+	if out != exp {
+		t.Errorf("BarChart ASCII %#v != %#v", out, exp)
+	}
+	out = BarChart([]string{"a", "b", "c", "d"}, []float64{2, math.Inf(1), math.Inf(-1), math.NaN()}, &BarChartOptions{Width: 16, ASCII: true})
+	exp = "a  #######     2\n" +
+		"b           +Inf\n" +
+		"c           -Inf\n" +
+		"d            NaN\n"
+	if out != exp {
+		t.Errorf("BarChart Inf %#v != %#v", out, exp)
+	}
+	if out = BarChart(nil, nil, nil); out != "" {
+		t.Errorf("BarChart(nil) %#v", out)
+	}
+}
+
+func TestChartBar(t *testing.T) {
+	for _, v := range []struct {
+		columns float64
+		ascii   bool
+		exp     string
+	}{
+		{0.01, false, "▏"},
+		{1, false, "█"},
+		{1.5, false, "█▌"},
+		{2.99, false, "██▉"},
+		{0.01, true, "#"},
+		{2.5, true, "###"},
+	} {
+		out := chartBar(v.columns, v.ascii)
+		if out != v.exp {
+			t.Errorf("chartBar(%v, %v) %#v != %#v", v.columns, v.ascii, out, v.exp)
+		}
+	}
+}
+
+func TestHistogramBuckets(t *testing.T) {
+	out := HistogramBuckets([]float64{0, 1, 2, 3, 4, 5, 6, 7, 8, 10, math.NaN()}, 2)
+	exp := []HistogramBucket{{0, 5, 5}, {5, 10, 5}}
+	if !reflect.DeepEqual(out, exp) {
+		t.Errorf("HistogramBuckets %#v != %#v", out, exp)
+	}
+	out = HistogramBuckets([]float64{3, 3, 3}, 0)
+	exp = []HistogramBucket{{3, 3, 3}}
+	if !reflect.DeepEqual(out, exp) {
+		t.Errorf("HistogramBuckets same %#v != %#v", out, exp)
+	}
+	if out = HistogramBuckets([]float64{math.NaN()}, 5); out != nil {
+		t.Errorf("HistogramBuckets NaN %#v", out)
+	}
+	out = HistogramBuckets([]float64{1, math.Inf(1), 3, math.Inf(-1)}, 2)
+	exp = []HistogramBucket{{1, 2, 1}, {2, 3, 1}}
+	if !reflect.DeepEqual(out, exp) {
+		t.Errorf("HistogramBuckets Inf %#v != %#v", out, exp)
+	}
+}
+
+func TestHistogram(t *testing.T) {
+	out := Histogram([]HistogramBucket{{0, 10, 2}, {10, 20, 4}, {20, 30, 0}}, &HistogramOptions{Width: 30, ASCII: true})
+	exp := " 0 - 10  2  33.3%  ######\n" +
+		"10 - 20  4  66.7%  ###########\n" +
+		"20 - 30  0   0.0%\n"
+	if out != exp {
+		t.Errorf("Histogram %#v != %#v", out, exp)
+	}
+	if out = Histogram(nil, nil); out != "" {
+		t.Errorf("Histogram(nil) %#v", out)
+	}
+}
+
+func TestTimeline(t *testing.T) {
+	out := Timeline([]TimelineSpan{{"fetch", 0, 1}, {"compile", 1, 6}, {"test", 5, 12}}, &TimelineOptions{Width: 50})
+	exp := "fetch    ███\n" +
+		"compile     █████████████████\n" +
+		"test                     ███████████████████████\n" +
+		"         ┬───────────────┬────────────────┬──────\n" +
+		"         0               5                10\n"
+	if out != exp {
+		t.Errorf("Timeline %#v != %#v", out, exp)
+	}
+	out = Timeline([]TimelineSpan{{"a", 0, 0.5}, {"bb", 0.25, 1}}, &TimelineOptions{Width: 30, ASCII: true, Ticks: 3})
+	exp = "a   =============\n" +
+		"bb        ===================\n" +
+		"    +------------+-----------+\n" +
+		"    0            0.5         1\n"
+	if out != exp {
+		t.Errorf("Timeline ASCII %#v != %#v", out, exp)
+	}
+	out = Timeline([]TimelineSpan{{"a", math.Inf(-1), 5}, {"b", 5, math.Inf(1)}, {"c", 0, 10}, {"d", math.NaN(), 1}}, &TimelineOptions{Width: 20, ASCII: true})
+	exp = "a  ========\n" +
+		"b          =======\n" +
+		"c  ===============\n" +
+		"d\n" +
+		"   +--------------+\n" +
+		"   0              10\n"
+	if out != exp {
+		t.Errorf("Timeline Inf %#v != %#v", out, exp)
+	}
+	if out = Timeline(nil, nil); out != "" {
+		t.Errorf("Timeline(nil) %#v", out)
+	}
+}
+
+func TestChartNiceStep(t *testing.T) {
+	for _, v := range [][2]float64{{0, 1}, {0.3, 0.5}, {1, 1}, {1.5, 2}, {4, 5}, {7, 10}, {120, 200}} {
+		if out := chartNiceStep(v[0]); out != v[1] {
+			t.Errorf("chartNiceStep(%v) %v != %v", v[0], out, v[1])
+		}
+	}
+}
+
+func TestBraillePlot(t *testing.T) {
+	out := BraillePlot([]float64{0, 1, 2, 3, math.NaN(), 7}, &BrailleOptions{Height: 1})
+	exp := "⣀⠤⠈\n"
+	if out != exp {
+		t.Errorf("BraillePlot %#v != %#v", out, exp)
+	}
+	out = BraillePlot([]float64{0, 7, 0}, &BrailleOptions{Width: 3, Height: 2})
+	exp = "⢸⡆ \n⡸⡇ \n"
+	if out != exp {
+		t.Errorf("BraillePlot joined %#v != %#v", out, exp)
+	}
+	out = BraillePlot([]float64{5, 5, 5, 5}, &BrailleOptions{Width: 1, Height: 1, Min: 0, Max: 10})
+	exp = "⠒\n"
+	if out != exp {
+		t.Errorf("BraillePlot resampled %#v != %#v", out, exp)
+	}
+	out = BraillePlot([]float64{0, math.Inf(1), 7, math.Inf(-1)}, &BrailleOptions{Height: 1})
+	exp = "⡀⠁\n"
+	if out != exp {
+		t.Errorf("BraillePlot Inf %#v != %#v", out, exp)
+	}
+	out = BraillePlot([]float64{0, 7}, &BrailleOptions{Height: 1, Min: math.Inf(-1), Max: 7})
+	exp = "⣀\n"
+	if out != exp {
+		t.Errorf("BraillePlot Inf Min %#v != %#v", out, exp)
+	}
+	if out = BraillePlot(nil, nil); out != "" {
+		t.Errorf("BraillePlot(nil) %#v", out)
+	}
+}
+
+func TestBrailleResample(t *testing.T) {
+	out := brailleResample([]float64{1, 3, math.NaN(), 4, math.NaN(), math.NaN()}, 3)
+	if out[0] != 2 || out[1] != 4 || !math.IsNaN(out[2]) {
+		t.Errorf("brailleResample %#v", out)
+	}
+}
+
+func TestSparklineInf(t *testing.T) {
+	out := Sparkline([]float64{1, math.Inf(1), 3, math.Inf(-1)})
+	exp := "▁ █ "
+	if out != exp {
+		t.Errorf("%#v != %#v", out, exp)
+	}
+	out = SparklineWithOptions([]float64{1, 3}, &SparklineOptions{Min: 0, Max: math.Inf(1)})
+	exp = "▁▁"
+	if out != exp {
+		t.Errorf("%#v != %#v", out, exp)
+	}
+}
@@ -0,0 +1,131 @@
+package brimtext
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+
+	"golang.org/x/crypto/ssh/terminal"
+)
+
+// ColorRule selects lines for a ColorizingWriter to style; see
+// ColorizingWriter.
+type ColorRule struct {
+	// Match, if not nil, selects lines whose text matches it.
+	Match *regexp.Regexp
+	// Prefix, if not empty, selects lines that start with it.
+	Prefix string
+	// Code is the ANSI escape code to style selected lines with, such as
+	// ANSIEscape.FRed.
+	Code []byte
+	// MatchOnly styles just the text Match matches, as with Highlight,
+	// rather than the whole line.
+	MatchOnly bool
+}
+
+// matches returns true if the rule selects the line, given without ANSI
+// escape codes.
+func (rule *ColorRule) matches(plain string) bool {
+	if rule.Match == nil && rule.Prefix == "" {
+		return false
+	}
+	if rule.Prefix != "" && !strings.HasPrefix(plain, rule.Prefix) {
+		return false
+	}
+	return rule.Match == nil || rule.Match.MatchString(plain)
+}
+
+// ColorizingWriter is an io.Writer that styles the lines of the text written
+// through it by the first of its Rules that selects each line, such as to
+// show lines containing "ERROR" in red and "WARN" in yellow, giving
+// forwarded logs readable color without changing how they are printed. For
+// example:
+//
+//	w := brimtext.NewColorizingWriter(os.Stdout,
+//		&brimtext.ColorRule{Match: regexp.MustCompile(`\bERROR\b`), Code: brimtext.ANSIEscape.FRed},
+//		&brimtext.ColorRule{Match: regexp.MustCompile(`\bWARN\b`), Code: brimtext.ANSIEscape.FYellow},
+//	)
+//
+// Rules are checked against the text of each line ignoring any ANSI escape
+// codes already in it, and a line's own styling is kept, with the rule's code
+// given again after each of its resets. Text is held until the end of each
+// line; use Flush to output a final line that has no newline.
+type ColorizingWriter struct {
+	Rules []*ColorRule
+	// Plain writes the text straight through without styling. It is set by
+	// NewColorizingWriter when the writer isn't a terminal or the NO_COLOR
+	// environment variable is set.
+	Plain bool
+	w     io.Writer
+	line  []byte
+}
+
+// NewColorizingWriter returns a ColorizingWriter writing to w with the rules
+// given.
+func NewColorizingWriter(w io.Writer, rules ...*ColorRule) *ColorizingWriter {
+	plain := os.Getenv("NO_COLOR") != ""
+	if f, ok := w.(*os.File); !ok || !terminal.IsTerminal(int(f.Fd())) {
+		plain = true
+	}
+	return &ColorizingWriter{Rules: rules, Plain: plain, w: w}
+}
+
+// Write styles and writes each complete line of p, holding any partial line
+// until the rest of it is written or Flush is called.
+func (cw *ColorizingWriter) Write(p []byte) (int, error) {
+	if cw.Plain {
+		if err := cw.Flush(); err != nil {
+			return 0, err
+		}
+		return cw.w.Write(p)
+	}
+	n := 0
+	for {
+		i := bytes.IndexByte(p[n:], '\n')
+		if i < 0 {
+			break
+		}
+		cw.line = append(cw.line, p[n:n+i+1]...)
+		if err := cw.writeLine(); err != nil {
+			return n, err
+		}
+		n += i + 1
+	}
+	cw.line = append(cw.line, p[n:]...)
+	return len(p), nil
+}
+
+// Flush styles and writes any partial line held.
+func (cw *ColorizingWriter) Flush() error {
+	if len(cw.line) == 0 {
+		return nil
+	}
+	return cw.writeLine()
+}
+
+func (cw *ColorizingWriter) writeLine() error {
+	line := string(cw.line)
+	cw.line = cw.line[:0]
+	text := strings.TrimRight(line, "\r\n")
+	end := line[len(text):]
+	if !cw.Plain {
+		plain := StripANSIEscapes(text)
+		for _, rule := range cw.Rules {
+			if !rule.matches(plain) {
+				continue
+			}
+			if rule.MatchOnly && rule.Match != nil {
+				text = Highlight(text, rule.Match, rule.Code)
+			} else if text != "" {
+				reset := string(ANSIEscape.Reset)
+				text = strings.Replace(text, reset, reset+string(rule.Code), -1)
+				text = string(rule.Code) + text + reset
+			}
+			break
+		}
+	}
+	_, err := io.WriteString(cw.w, text+end)
+	return err
+}
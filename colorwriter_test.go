@@ -0,0 +1,53 @@
+package brimtext
+
+import (
+	"bytes"
+	"regexp"
+	"testing"
+)
+
+func TestColorizingWriter(t *testing.T) {
+	r, y, b, z := string(ANSIEscape.FRed), string(ANSIEscape.FYellow), string(ANSIEscape.Bold), string(ANSIEscape.Reset)
+	rules := []*ColorRule{
+		{Match: regexp.MustCompile(`\bERROR\b`), Code: ANSIEscape.FRed},
+		{Prefix: "WARN", Code: ANSIEscape.FYellow},
+		{Match: regexp.MustCompile(`\d+ms`), Code: ANSIEscape.Bold, MatchOnly: true},
+	}
+	for _, v := range []struct {
+		in  []string
+		exp string
+	}{
+		{[]string{"ERROR: bad\n"}, r + "ERROR: bad" + z + "\n"},
+		{[]string{"an ERR", "OR here\r\nok\n"}, r + "an ERROR here" + z + "\r\nok\n"},
+		{[]string{"WARN: slow\nnot WARN\n"}, y + "WARN: slow" + z + "\nnot WARN\n"},
+		{[]string{"took 12ms\n"}, "took " + b + "12ms" + z + "\n"},
+		{[]string{"\x1b[1mERROR" + z + " after\n"}, r + "\x1b[1mERROR" + z + r + " after" + z + "\n"},
+		{[]string{"WARN final"}, y + "WARN final" + z},
+		{[]string{"\n"}, "\n"},
+	} {
+		var buf bytes.Buffer
+		cw := NewColorizingWriter(&buf, rules...)
+		if !cw.Plain {
+			t.Error("expected Plain for a non-terminal writer")
+		}
+		cw.Plain = false
+		for _, s := range v.in {
+			n, err := cw.Write([]byte(s))
+			if n != len(s) || err != nil {
+				t.Errorf("Write(%#v) %d, %v", s, n, err)
+			}
+		}
+		if err := cw.Flush(); err != nil {
+			t.Error(err)
+		}
+		if out := buf.String(); out != v.exp {
+			t.Errorf("%#v: %#v != %#v", v.in, out, v.exp)
+		}
+	}
+	var buf bytes.Buffer
+	cw := NewColorizingWriter(&buf, rules...)
+	cw.Write([]byte("ERROR: plain\n"))
+	if out := buf.String(); out != "ERROR: plain\n" {
+		t.Errorf("%#v != %#v", out, "ERROR: plain\n")
+	}
+}
@@ -0,0 +1,195 @@
+package brimtext
+
+import (
+	"strings"
+)
+
+// alignColumns returns the columns selected by opts.Columns and
+// opts.ColumnNames, or nil if none are.
+func alignColumns(data [][]string, opts *AlignOptions, headerRows int) []int {
+	if len(opts.Columns) == 0 && len(opts.ColumnNames) == 0 {
+		return nil
+	}
+	columns := append([]int(nil), opts.Columns...)
+	if len(opts.ColumnNames) > 0 && headerRows > 0 {
+		for _, name := range opts.ColumnNames {
+			for col, cell := range data[0] {
+				if strings.EqualFold(strings.TrimSpace(StripANSIEscapes(cell)), name) {
+					columns = append(columns, col)
+					break
+				}
+			}
+		}
+	}
+	if columns == nil {
+		columns = []int{}
+	}
+	return columns
+}
+
+// selectColumns returns the data with just the columns given, in their
+// order, and a copy of opts with its per column options moved along with
+// their columns.
+func selectColumns(data [][]string, cellAligns [][]Alignment, columns []int, opts *AlignOptions) ([][]string, [][]Alignment, *AlignOptions) {
+	newData := make([][]string, len(data))
+	for r, row := range data {
+		if row == nil {
+			continue
+		}
+		newRow := make([]string, len(columns))
+		for i, col := range columns {
+			if col >= 0 && col < len(row) {
+				newRow[i] = row[col]
+			}
+		}
+		newData[r] = newRow
+	}
+	var newAligns [][]Alignment
+	if cellAligns != nil {
+		newAligns = make([][]Alignment, len(cellAligns))
+		for r, row := range cellAligns {
+			if row == nil {
+				continue
+			}
+			newAligns[r] = make([]Alignment, len(columns))
+			for i, col := range columns {
+				newAligns[r][i] = cellAlignUnset
+				if col >= 0 && col < len(row) {
+					newAligns[r][i] = row[col]
+				}
+			}
+		}
+	}
+	has := func(n int, col int) bool {
+		return n > 0 && col >= 0 && col < n
+	}
+	newOpts := opts.Clone()
+	newOpts.Widths, newOpts.MaxWidths = nil, nil
+	newOpts.Alignments, newOpts.HeaderAlignments, newOpts.ContinuationAlignments = nil, nil, nil
+	newOpts.HeatScales, newOpts.Formatters, newOpts.WrapModes = nil, nil, nil
+	newOpts.VerticalAlignments = nil
+	if opts.HeaderAlignments != nil {
+		newOpts.HeaderAlignments = []Alignment{}
+	}
+	for i, col := range columns {
+		if has(len(opts.Widths), col) {
+			for len(newOpts.Widths) <= i {
+				newOpts.Widths = append(newOpts.Widths, 0)
+			}
+			newOpts.Widths[i] = opts.Widths[col]
+		}
+		if has(len(opts.MaxWidths), col) {
+			for len(newOpts.MaxWidths) <= i {
+				newOpts.MaxWidths = append(newOpts.MaxWidths, 0)
+			}
+			newOpts.MaxWidths[i] = opts.MaxWidths[col]
+		}
+		if has(len(opts.Alignments), col) {
+			for len(newOpts.Alignments) <= i {
+				newOpts.Alignments = append(newOpts.Alignments, Left)
+			}
+			newOpts.Alignments[i] = opts.Alignments[col]
+		}
+		if has(len(opts.HeaderAlignments), col) {
+			for len(newOpts.HeaderAlignments) <= i {
+				newOpts.HeaderAlignments = append(newOpts.HeaderAlignments, Center)
+			}
+			newOpts.HeaderAlignments[i] = opts.HeaderAlignments[col]
+		}
+		if has(len(opts.ContinuationAlignments), col) {
+			for len(newOpts.ContinuationAlignments) <= i {
+				align := Left
+				if j := len(newOpts.ContinuationAlignments); has(len(opts.Alignments), columns[j]) {
+					align = opts.Alignments[columns[j]]
+				}
+				newOpts.ContinuationAlignments = append(newOpts.ContinuationAlignments, align)
+			}
+			newOpts.ContinuationAlignments[i] = opts.ContinuationAlignments[col]
+		}
+		if has(len(opts.HeatScales), col) {
+			for len(newOpts.HeatScales) <= i {
+				newOpts.HeatScales = append(newOpts.HeatScales, nil)
+			}
+			newOpts.HeatScales[i] = opts.HeatScales[col]
+		}
+		if has(len(opts.Formatters), col) {
+			for len(newOpts.Formatters) <= i {
+				newOpts.Formatters = append(newOpts.Formatters, nil)
+			}
+			newOpts.Formatters[i] = opts.Formatters[col]
+		}
+		if has(len(opts.WrapModes), col) {
+			for len(newOpts.WrapModes) <= i {
+				newOpts.WrapModes = append(newOpts.WrapModes, WrapWords)
+			}
+			newOpts.WrapModes[i] = opts.WrapModes[col]
+		}
+		if has(len(opts.VerticalAlignments), col) {
+			for len(newOpts.VerticalAlignments) <= i {
+				newOpts.VerticalAlignments = append(newOpts.VerticalAlignments, Top)
+			}
+			newOpts.VerticalAlignments[i] = opts.VerticalAlignments[col]
+		}
+	}
+	return newData, newAligns, newOpts
+}
+
+// Transpose returns the data with its rows and columns swapped, such as to
+// show the rows of a wide table as records, each field name beside its
+// value:
+//
+//	brimtext.Transpose([][]string{{"Name", "Size"}, nil, {"a.txt", "1024"}})
+//
+// gives:
+//
+//	[][]string{{"Name", "a.txt"}, {"Size", "1024"}}
+//
+// Nil rows are left out and short rows are filled out with empty cells.
+func Transpose(data [][]string) [][]string {
+	columns := 0
+	for _, row := range data {
+		if len(row) > columns {
+			columns = len(row)
+		}
+	}
+	out := make([][]string, columns)
+	for c := range out {
+		out[c] = make([]string, 0, len(data))
+		for _, row := range data {
+			if row == nil {
+				continue
+			}
+			cell := ""
+			if c < len(row) {
+				cell = row[c]
+			}
+			out[c] = append(out[c], cell)
+		}
+	}
+	return out
+}
+
+// transposeAligns swaps the rows and columns of cell alignments as
+// Transpose does those of the data.
+func transposeAligns(data [][]string, cellAligns [][]Alignment) [][]Alignment {
+	columns := 0
+	for _, row := range data {
+		if len(row) > columns {
+			columns = len(row)
+		}
+	}
+	out := make([][]Alignment, columns)
+	for c := range out {
+		for r, row := range data {
+			if row == nil {
+				continue
+			}
+			align := cellAlignUnset
+			if r < len(cellAligns) && c < len(cellAligns[r]) {
+				align = cellAligns[r][c]
+			}
+			out[c] = append(out[c], align)
+		}
+	}
+	return out
+}
@@ -0,0 +1,79 @@
+package brimtext
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAlignColumns(t *testing.T) {
+	data := [][]string{
+		{"Name", "Size", "Owner"},
+		nil,
+		{"a.txt", "1024", "root"},
+		{"b.txt", "2048", "gholt"},
+	}
+	opts := NewAlignOptions(
+		WithAlignments(Left, Right, Left),
+		WithFormatters(nil, BytesFormatter(1024)),
+		WithColumns(2, 1),
+	)
+	out := Align(data, opts)
+	exp := "Owner Size\n" +
+		"\n" +
+		"root  1KiB\n" +
+		"gholt 2KiB\n"
+	if out != exp {
+		t.Errorf("%#v != %#v", out, exp)
+	}
+	opts = NewAlignOptions(WithAlignments(Left, Right, Left))
+	opts.ColumnNames = []string{"size", "missing", "NAME"}
+	out = Align(data, opts)
+	exp = "Size Name\n" +
+		"\n" +
+		"1024 a.txt\n" +
+		"2048 b.txt\n"
+	if out != exp {
+		t.Errorf("%#v != %#v", out, exp)
+	}
+}
+
+func TestTranspose(t *testing.T) {
+	out := Transpose([][]string{{"Name", "Size", "Owner"}, nil, {"a.txt", "1024"}, {"b.txt", "2048", "gholt"}})
+	exp := [][]string{{"Name", "a.txt", "b.txt"}, {"Size", "1024", "2048"}, {"Owner", "", "gholt"}}
+	if !reflect.DeepEqual(out, exp) {
+		t.Errorf("%#v != %#v", out, exp)
+	}
+	if out = Transpose(nil); len(out) != 0 {
+		t.Errorf("%#v != nil", out)
+	}
+	opts := NewDefaultAlignOptions()
+	opts.Transpose = true
+	s := Align([][]string{{"Name", "Size"}, nil, {"a.txt", "1024"}}, opts)
+	e := "Name a.txt\n" +
+		"Size 1024\n"
+	if s != e {
+		t.Errorf("%#v != %#v", s, e)
+	}
+}
+
+func TestAlignColumnsVerticalAlignments(t *testing.T) {
+	data := [][]string{{"a\nb\nc", "x", "y"}}
+	opts := NewAlignOptions(WithColumns(2, 1, 0))
+	opts.VerticalAlignments = []VerticalAlignment{Top, Top, Bottom}
+	out := Align(data, opts)
+	exp := "  x a\n" +
+		"    b\n" +
+		"y   c\n"
+	if out != exp {
+		t.Errorf("%#v != %#v", out, exp)
+	}
+}
+
+func TestWithColumnsCopies(t *testing.T) {
+	columns := []int{1, 0}
+	opts := NewAlignOptions(WithColumns(columns...))
+	columns[0] = 2
+	if opts.Columns[0] != 1 {
+		t.Errorf("%#v", opts.Columns)
+	}
+}
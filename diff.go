@@ -0,0 +1,487 @@
+package brimtext
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// DiffKind identifies what a DiffOp does.
+type DiffKind int
+
+const (
+	// DiffEqual indicates a line common to both texts.
+	DiffEqual DiffKind = iota
+	// DiffDelete indicates a line only in the first text.
+	DiffDelete
+	// DiffInsert indicates a line only in the second text.
+	DiffInsert
+)
+
+// DiffOp is a single line of the result of DiffLines.
+type DiffOp struct {
+	Kind DiffKind
+	// Text is the line itself, without its line ending.
+	Text string
+	// ALine and BLine are the 1-based line numbers of the line within the
+	// first and second texts; 0 if the line isn't in that text.
+	ALine int
+	BLine int
+}
+
+// DiffLines compares the two texts line by line and returns the operations
+// that turn a into b, with deletions ordered before insertions wherever lines
+// were changed. The result is a longest common subsequence diff, so it may not
+// be as compact as one from a dedicated diff tool for very large inputs, but
+// is more than reasonable for showing changes to config files and the like.
+func DiffLines(a string, b string) []DiffOp {
+	return diffLines(splitDiffLines(a), splitDiffLines(b))
+}
+
+// DiffWords is like DiffLines but compares the texts word by word, useful
+// for showing exactly what changed within a line. Each DiffOp holds a single
+// token (a run of letters and digits, a run of whitespace, or a single other
+// character) and the ALine and BLine fields give the 1-based token positions
+// rather than line numbers. Concatenating the Text of the DiffEqual and
+// DiffDelete operations gives a back, and of DiffEqual and DiffInsert gives b.
+func DiffWords(a string, b string) []DiffOp {
+	return diffLines(splitDiffWords(a), splitDiffWords(b))
+}
+
+// DiffRunes is like DiffWords but with each token being a single rune.
+func DiffRunes(a string, b string) []DiffOp {
+	return diffLines(splitDiffRunes(a), splitDiffRunes(b))
+}
+
+func splitDiffWords(s string) []string {
+	var tokens []string
+	start := -1
+	startKind := 0
+	kind := func(r rune) int {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' {
+			return 1
+		} else if unicode.IsSpace(r) {
+			return 2
+		}
+		return 3
+	}
+	for i, r := range s {
+		k := kind(r)
+		if start != -1 && (k != startKind || k == 3) {
+			tokens = append(tokens, s[start:i])
+			start = -1
+		}
+		if start == -1 {
+			start = i
+			startKind = k
+		}
+	}
+	if start != -1 {
+		tokens = append(tokens, s[start:])
+	}
+	return tokens
+}
+
+func splitDiffRunes(s string) []string {
+	tokens := make([]string, 0, len(s))
+	for _, r := range s {
+		tokens = append(tokens, string(r))
+	}
+	return tokens
+}
+
+func splitDiffLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	s = strings.Replace(s, "\r\n", "\n", -1)
+	s = strings.TrimSuffix(s, "\n")
+	return strings.Split(s, "\n")
+}
+
+func diffLines(a []string, b []string) []DiffOp {
+	var ops []DiffOp
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		ops = append(ops, DiffOp{Kind: DiffEqual, Text: a[prefix], ALine: prefix + 1, BLine: prefix + 1})
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+	ma := a[prefix : len(a)-suffix]
+	mb := b[prefix : len(b)-suffix]
+	// lcs[i][j] is the length of the longest common subsequence of ma[i:] and
+	// mb[j:].
+	lcs := make([][]int, len(ma)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(mb)+1)
+	}
+	for i := len(ma) - 1; i >= 0; i-- {
+		for j := len(mb) - 1; j >= 0; j-- {
+			if ma[i] == mb[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+	i, j := 0, 0
+	var inserts []DiffOp
+	flush := func() {
+		ops = append(ops, inserts...)
+		inserts = inserts[:0]
+	}
+	for i < len(ma) || j < len(mb) {
+		switch {
+		case i < len(ma) && j < len(mb) && ma[i] == mb[j]:
+			flush()
+			ops = append(ops, DiffOp{Kind: DiffEqual, Text: ma[i], ALine: prefix + i + 1, BLine: prefix + j + 1})
+			i++
+			j++
+		case j >= len(mb) || (i < len(ma) && lcs[i+1][j] >= lcs[i][j+1]):
+			ops = append(ops, DiffOp{Kind: DiffDelete, Text: ma[i], ALine: prefix + i + 1})
+			i++
+		default:
+			inserts = append(inserts, DiffOp{Kind: DiffInsert, Text: mb[j], BLine: prefix + j + 1})
+			j++
+		}
+	}
+	flush()
+	for k := 0; k < suffix; k++ {
+		ai := len(a) - suffix + k
+		bi := len(b) - suffix + k
+		ops = append(ops, DiffOp{Kind: DiffEqual, Text: a[ai], ALine: ai + 1, BLine: bi + 1})
+	}
+	return ops
+}
+
+// DiffOptions controls the output of UnifiedDiff.
+type DiffOptions struct {
+	// Context is the number of unchanged lines to show around each change; a
+	// negative value shows all lines.
+	Context int
+	// FromFile and ToFile, if either is set, will cause "--- FromFile" and
+	// "+++ ToFile" header lines to be output.
+	FromFile string
+	ToFile   string
+	// Color will use ANSI escape codes to show deletions in red, insertions
+	// in green, and hunk headers in cyan. When lines are modified, the changed
+	// words within them are shown in inverse video; see DiffWords.
+	Color bool
+}
+
+// NewDiffOptions gives:
+//
+//	&DiffOptions{Context: 3}
+func NewDiffOptions() *DiffOptions {
+	return &DiffOptions{Context: 3}
+}
+
+// UnifiedDiff renders the operations from DiffLines in the familiar unified
+// diff format, such as:
+//
+//	@@ -1,3 +1,3 @@
+//	 first
+//	-second
+//	+2nd
+//	 third
+//
+// If there are no changes, an empty string is returned. If opts is nil,
+// NewDiffOptions is used.
+func UnifiedDiff(ops []DiffOp, opts *DiffOptions) string {
+	if opts == nil {
+		opts = NewDiffOptions()
+	}
+	var buf bytes.Buffer
+	for _, hunk := range diffHunks(ops, opts.Context) {
+		if buf.Len() == 0 && (opts.FromFile != "" || opts.ToFile != "") {
+			diffLine(&buf, opts.Color, ANSIEscape.Bold, "--- "+opts.FromFile)
+			diffLine(&buf, opts.Color, ANSIEscape.Bold, "+++ "+opts.ToFile)
+		}
+		aStart, aCount, bStart, bCount := diffHunkRange(ops, hunk[0], hunk[1])
+		diffLine(&buf, opts.Color, ANSIEscape.FCyan, fmt.Sprintf("@@ -%s +%s @@", diffRange(aStart, aCount), diffRange(bStart, bCount)))
+		hops := ops[hunk[0]:hunk[1]]
+		for i := 0; i < len(hops); {
+			if hops[i].Kind == DiffEqual {
+				diffLine(&buf, false, nil, " "+hops[i].Text)
+				i++
+				continue
+			}
+			var dels, ins []string
+			for i < len(hops) && hops[i].Kind == DiffDelete {
+				dels = append(dels, hops[i].Text)
+				i++
+			}
+			for i < len(hops) && hops[i].Kind == DiffInsert {
+				ins = append(ins, hops[i].Text)
+				i++
+			}
+			if opts.Color && len(dels) == len(ins) {
+				// Modified lines get their changed words highlighted.
+				rights := make([]string, len(ins))
+				for j := range dels {
+					var left string
+					left, rights[j] = diffHighlightPair(dels[j], ins[j], true, "-", "+")
+					buf.WriteString(left)
+					buf.WriteByte('\n')
+				}
+				for _, right := range rights {
+					buf.WriteString(right)
+					buf.WriteByte('\n')
+				}
+				continue
+			}
+			for _, line := range dels {
+				diffLine(&buf, opts.Color, ANSIEscape.FRed, "-"+line)
+			}
+			for _, line := range ins {
+				diffLine(&buf, opts.Color, ANSIEscape.FGreen, "+"+line)
+			}
+		}
+	}
+	return buf.String()
+}
+
+func diffLine(buf *bytes.Buffer, color bool, code []byte, line string) {
+	if color {
+		buf.Write(code)
+		buf.WriteString(line)
+		buf.Write(ANSIEscape.Reset)
+	} else {
+		buf.WriteString(line)
+	}
+	buf.WriteByte('\n')
+}
+
+// diffHunks groups the operations into runs of changes with up to context
+// equal lines around them, returned as [start, end) index pairs into ops; a
+// negative context gives everything as one hunk.
+func diffHunks(ops []DiffOp, context int) [][2]int {
+	var hunks [][2]int
+	start, end := -1, -1
+	for i, op := range ops {
+		if op.Kind == DiffEqual {
+			continue
+		}
+		if context < 0 {
+			return [][2]int{{0, len(ops)}}
+		}
+		lo := i - context
+		if lo < 0 {
+			lo = 0
+		}
+		hi := i + context + 1
+		if hi > len(ops) {
+			hi = len(ops)
+		}
+		if start == -1 {
+			start, end = lo, hi
+		} else if lo <= end {
+			end = hi
+		} else {
+			hunks = append(hunks, [2]int{start, end})
+			start, end = lo, hi
+		}
+	}
+	if start == -1 {
+		return nil
+	}
+	return append(hunks, [2]int{start, end})
+}
+
+// diffHunkRange returns the starting line numbers and line counts in the
+// first and second texts for the hunk ops[start:end]. An empty range starts
+// at the line before it, as diff does.
+func diffHunkRange(ops []DiffOp, start int, end int) (int, int, int, int) {
+	aStart, aCount, bStart, bCount := 0, 0, 0, 0
+	for _, op := range ops[start:end] {
+		if op.ALine != 0 {
+			if aStart == 0 {
+				aStart = op.ALine
+			}
+			aCount++
+		}
+		if op.BLine != 0 {
+			if bStart == 0 {
+				bStart = op.BLine
+			}
+			bCount++
+		}
+	}
+	for i := start - 1; i >= 0 && (aStart == 0 || bStart == 0); i-- {
+		if aStart == 0 && ops[i].ALine != 0 {
+			aStart = ops[i].ALine
+		}
+		if bStart == 0 && ops[i].BLine != 0 {
+			bStart = ops[i].BLine
+		}
+	}
+	return aStart, aCount, bStart, bCount
+}
+
+func diffRange(start int, count int) string {
+	if count == 1 {
+		return fmt.Sprintf("%d", start)
+	}
+	return fmt.Sprintf("%d,%d", start, count)
+}
+
+// SideBySideOptions controls the output of SideBySideDiff.
+type SideBySideOptions struct {
+	// Width is the total width to fit the output to, with the same meaning as
+	// the width given to Wrap.
+	Width int
+	// Context is the number of unchanged lines to show around each change; a
+	// negative value shows all lines.
+	Context int
+	// Color will use ANSI escape codes to show deleted text in red, inserted
+	// text in green, and the changed words of modified lines in inverse
+	// video; see DiffWords.
+	Color bool
+	// AlignOptions give the table style used; if nil,
+	// NewDefaultAlignOptions is used. The Widths and Alignments are
+	// overridden.
+	AlignOptions *AlignOptions
+}
+
+// NewSideBySideOptions gives:
+//
+//	&SideBySideOptions{Context: 3}
+func NewSideBySideOptions() *SideBySideOptions {
+	return &SideBySideOptions{Context: 3}
+}
+
+// SideBySideDiff renders the operations from DiffLines as a table with the
+// first text on the left, the second on the right, and a gutter column
+// between them marking each row: " " for unchanged, "|" for modified, "<" for
+// deleted, and ">" for inserted lines. Long lines are wrapped so the table
+// fits the width. Hunks of changes are separated by nil rows, so the
+// AlignOptions choose how those look. If there are no changes, an empty
+// string is returned. If opts is nil, NewSideBySideOptions is used.
+func SideBySideDiff(ops []DiffOp, opts *SideBySideOptions) string {
+	if opts == nil {
+		opts = NewSideBySideOptions()
+	}
+	width := opts.Width
+	if width < 1 {
+		width = GetTTYWidth() - 1 + width
+	}
+	alignOpts := NewDefaultAlignOptions()
+	if opts.AlignOptions != nil {
+		o := *opts.AlignOptions
+		alignOpts = &o
+	}
+	alignOpts.Widths = nil
+	alignOpts.Alignments = nil
+	overhead := DisplayWidth(alignOpts.RowFirstUD) + DisplayWidth(alignOpts.RowSecondUD) + DisplayWidth(alignOpts.RowUD) + DisplayWidth(alignOpts.RowLastUD) + 1
+	colWidth := (width - overhead) / 2
+	if colWidth < 1 {
+		colWidth = 1
+	}
+	var data [][]string
+	row := func(left string, mark string, right string) {
+		data = append(data, []string{
+			strings.Join(wrapRunes(left, colWidth), "\n"),
+			mark,
+			strings.Join(wrapRunes(right, colWidth), "\n"),
+		})
+	}
+	colored := func(code []byte, s string) string {
+		if !opts.Color || s == "" {
+			return s
+		}
+		return string(code) + s + string(ANSIEscape.Reset)
+	}
+	for h, hunk := range diffHunks(ops, opts.Context) {
+		if h > 0 {
+			data = append(data, nil)
+		}
+		hops := ops[hunk[0]:hunk[1]]
+		for i := 0; i < len(hops); {
+			if hops[i].Kind == DiffEqual {
+				row(hops[i].Text, " ", hops[i].Text)
+				i++
+				continue
+			}
+			var dels, ins []string
+			for i < len(hops) && hops[i].Kind == DiffDelete {
+				dels = append(dels, hops[i].Text)
+				i++
+			}
+			for i < len(hops) && hops[i].Kind == DiffInsert {
+				ins = append(ins, hops[i].Text)
+				i++
+			}
+			for j := 0; j < len(dels) || j < len(ins); j++ {
+				switch {
+				case j < len(dels) && j < len(ins):
+					left, right := diffHighlightPair(dels[j], ins[j], opts.Color, "", "")
+					row(left, "|", right)
+				case j < len(dels):
+					row(colored(ANSIEscape.FRed, dels[j]), "<", "")
+				default:
+					row("", ">", colored(ANSIEscape.FGreen, ins[j]))
+				}
+			}
+		}
+	}
+	if len(data) == 0 {
+		return ""
+	}
+	return Align(data, alignOpts)
+}
+
+// diffHighlightPair returns the two versions of a modified line, each after
+// its lead, colored red and green with the changed words shown in inverse
+// video if color is true. Lines with no words in common are just colored.
+func diffHighlightPair(a string, b string, color bool, leadA string, leadB string) (string, string) {
+	if !color {
+		return leadA + a, leadB + b
+	}
+	words := DiffWords(a, b)
+	related := false
+	for _, w := range words {
+		if w.Kind == DiffEqual && strings.TrimSpace(w.Text) != "" {
+			related = true
+			break
+		}
+	}
+	if !related {
+		return string(ANSIEscape.FRed) + leadA + a + string(ANSIEscape.Reset), string(ANSIEscape.FGreen) + leadB + b + string(ANSIEscape.Reset)
+	}
+	return diffHighlight(words, DiffDelete, ANSIEscape.FRed, leadA), diffHighlight(words, DiffInsert, ANSIEscape.FGreen, leadB)
+}
+
+// diffHighlight builds one side of a word diff: the lead and the DiffEqual
+// tokens in the color given and the tokens of the kind given additionally in
+// inverse video, skipping the tokens for the other side.
+func diffHighlight(words []DiffOp, kind DiffKind, code []byte, lead string) string {
+	var buf bytes.Buffer
+	buf.Write(code)
+	buf.WriteString(lead)
+	for i := 0; i < len(words); {
+		switch words[i].Kind {
+		case DiffEqual:
+			buf.WriteString(words[i].Text)
+			i++
+		case kind:
+			buf.Write(ANSIEscape.Reverse)
+			for ; i < len(words) && words[i].Kind != DiffEqual; i++ {
+				if words[i].Kind == kind {
+					buf.WriteString(words[i].Text)
+				}
+			}
+			buf.Write(ANSIEscape.Reset)
+			buf.Write(code)
+		default:
+			i++
+		}
+	}
+	buf.Write(ANSIEscape.Reset)
+	return buf.String()
+}
@@ -0,0 +1,184 @@
+package brimtext
+
+import (
+	"testing"
+)
+
+func TestDiffLines(t *testing.T) {
+	ops := DiffLines("a\nb\nc\n", "a\nB\nc\nd\n")
+	exp := []DiffOp{
+		{Kind: DiffEqual, Text: "a", ALine: 1, BLine: 1},
+		{Kind: DiffDelete, Text: "b", ALine: 2},
+		{Kind: DiffInsert, Text: "B", BLine: 2},
+		{Kind: DiffEqual, Text: "c", ALine: 3, BLine: 3},
+		{Kind: DiffInsert, Text: "d", BLine: 4},
+	}
+	if len(ops) != len(exp) {
+		t.Fatalf("DiffLines %#v != %#v", ops, exp)
+	}
+	for i := range ops {
+		if ops[i] != exp[i] {
+			t.Errorf("DiffLines op %d %#v != %#v", i, ops[i], exp[i])
+		}
+	}
+	if ops = DiffLines("", ""); len(ops) != 0 {
+		t.Errorf("DiffLines of empty texts %#v", ops)
+	}
+	ops = DiffLines("", "x")
+	if len(ops) != 1 || ops[0] != (DiffOp{Kind: DiffInsert, Text: "x", BLine: 1}) {
+		t.Errorf("DiffLines insert only %#v", ops)
+	}
+}
+
+func TestUnifiedDiff(t *testing.T) {
+	a := "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12\n"
+	b := "1\n2\nthree\n4\n5\n6\n7\n8\n9\n10\n11\n12\n13\n"
+	out := UnifiedDiff(DiffLines(a, b), nil)
+	exp := `@@ -1,6 +1,6 @@
+ 1
+ 2
+-3
++three
+ 4
+ 5
+ 6
+@@ -10,3 +10,4 @@
+ 10
+ 11
+ 12
++13
+`
+	if out != exp {
+		t.Errorf("UnifiedDiff %#v != %#v", out, exp)
+	}
+	opts := NewDiffOptions()
+	opts.Context = 0
+	opts.FromFile = "a.conf"
+	opts.ToFile = "b.conf"
+	out = UnifiedDiff(DiffLines("x\ny\n", "x\nnew\ny\n"), opts)
+	exp = `--- a.conf
++++ b.conf
+@@ -1,0 +2 @@
++new
+`
+	if out != exp {
+		t.Errorf("UnifiedDiff insert %#v != %#v", out, exp)
+	}
+	opts = NewDiffOptions()
+	opts.Color = true
+	out = UnifiedDiff(DiffLines("a\n", "b\n"), opts)
+	exp = "\x1b[36m@@ -1 +1 @@\x1b[0m\n\x1b[31m-a\x1b[0m\n\x1b[32m+b\x1b[0m\n"
+	if out != exp {
+		t.Errorf("UnifiedDiff color %#v != %#v", out, exp)
+	}
+	if out = UnifiedDiff(DiffLines("same\n", "same\n"), nil); out != "" {
+		t.Errorf("UnifiedDiff no changes %#v", out)
+	}
+	out = UnifiedDiff(DiffLines("a\nb\nc\nd\ne\n", "a\nb\nc\nd\nE\n"), &DiffOptions{Context: -1})
+	exp = "@@ -1,5 +1,5 @@\n a\n b\n c\n d\n-e\n+E\n"
+	if out != exp {
+		t.Errorf("UnifiedDiff all context %#v != %#v", out, exp)
+	}
+}
+
+func TestSideBySideDiff(t *testing.T) {
+	opts := NewSideBySideOptions()
+	opts.Width = 30
+	out := SideBySideDiff(DiffLines("same\nold line\ngone\n", "same\nnew line\n"), opts)
+	exp := "same       same\nold line | new line\ngone     < \n"
+	if out != exp {
+		t.Errorf("SideBySideDiff %#v != %#v", out, exp)
+	}
+	opts.Width = 16
+	out = SideBySideDiff(DiffLines("a\n", "a\nlong inserted line\n"), opts)
+	exp = `a   a
+  > long i
+    nserte
+    d line
+`
+	if out != exp {
+		t.Errorf("SideBySideDiff wrapped %#v != %#v", out, exp)
+	}
+	out = SideBySideDiff(DiffLines("a\n", "a\n日本語の長い行\n"), opts)
+	exp = "a   a\n  > 日本語\n    の長い\n    行\n"
+	if out != exp {
+		t.Errorf("SideBySideDiff wide %#v != %#v", out, exp)
+	}
+	opts = NewSideBySideOptions()
+	opts.Width = 40
+	opts.Context = 0
+	opts.AlignOptions = NewSimpleAlignOptions()
+	out = SideBySideDiff(DiffLines("1\n2\n3\n4\n", "one\n2\n3\nfour\n"), opts)
+	exp = `+---+---+------+
+| 1 | | | one  |
++---+---+------+
+| 4 | | | four |
++---+---+------+
+`
+	if out != exp {
+		t.Errorf("SideBySideDiff boxed %#v != %#v", out, exp)
+	}
+	opts = NewSideBySideOptions()
+	opts.Width = 40
+	opts.Color = true
+	out = SideBySideDiff(DiffLines("the cat sat\n", "the dog sat\n"), opts)
+	exp = "\x1b[31mthe \x1b[7mcat\x1b[0m\x1b[31m sat\x1b[0m | \x1b[32mthe \x1b[7mdog\x1b[0m\x1b[32m sat\x1b[0m\n"
+	if out != exp {
+		t.Errorf("SideBySideDiff color %#v != %#v", out, exp)
+	}
+	if out = SideBySideDiff(DiffLines("x\n", "x\n"), nil); out != "" {
+		t.Errorf("SideBySideDiff no changes %#v", out)
+	}
+}
+
+func TestDiffWords(t *testing.T) {
+	ops := DiffWords("the quick fox", "the slow fox!")
+	var kinds []DiffKind
+	var texts []string
+	for _, op := range ops {
+		kinds = append(kinds, op.Kind)
+		texts = append(texts, op.Text)
+	}
+	expKinds := []DiffKind{DiffEqual, DiffEqual, DiffDelete, DiffInsert, DiffEqual, DiffEqual, DiffInsert}
+	expTexts := []string{"the", " ", "quick", "slow", " ", "fox", "!"}
+	if len(ops) != len(expKinds) {
+		t.Fatalf("DiffWords %#v", ops)
+	}
+	for i := range ops {
+		if kinds[i] != expKinds[i] || texts[i] != expTexts[i] {
+			t.Errorf("DiffWords op %d %#v != %v %#v", i, ops[i], expKinds[i], expTexts[i])
+		}
+	}
+}
+
+func TestDiffRunes(t *testing.T) {
+	ops := DiffRunes("cat", "cut")
+	a, b := "", ""
+	changed := 0
+	for _, op := range ops {
+		if op.Kind != DiffInsert {
+			a += op.Text
+		}
+		if op.Kind != DiffDelete {
+			b += op.Text
+		}
+		if op.Kind != DiffEqual {
+			changed++
+		}
+	}
+	if a != "cat" || b != "cut" || changed != 2 {
+		t.Errorf("DiffRunes %#v", ops)
+	}
+}
+
+func TestUnifiedDiffWordHighlight(t *testing.T) {
+	opts := NewDiffOptions()
+	opts.Color = true
+	out := UnifiedDiff(DiffLines("port = 80\n", "port = 8080\n"), opts)
+	exp := "\x1b[36m@@ -1 +1 @@\x1b[0m\n" +
+		"\x1b[31m-port = \x1b[7m80\x1b[0m\x1b[31m\x1b[0m\n" +
+		"\x1b[32m+port = \x1b[7m8080\x1b[0m\x1b[32m\x1b[0m\n"
+	if out != exp {
+		t.Errorf("UnifiedDiff word highlight %#v != %#v", out, exp)
+	}
+}
@@ -0,0 +1,117 @@
+package brimtext
+
+import (
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// Levenshtein returns the edit distance between a and b: the number of single
+// rune insertions, deletions, or substitutions needed to turn one into the
+// other.
+func Levenshtein(a string, b string) int {
+	return LevenshteinMax(a, b, -1)
+}
+
+// LevenshteinMax is like Levenshtein but will stop early once the distance is
+// known to be greater than max, returning max+1 in that case. This is much
+// faster when only close matches are of interest. A negative max means no
+// limit.
+func LevenshteinMax(a string, b string, max int) int {
+	ra := []rune(a)
+	rb := []rune(b)
+	if len(ra) < len(rb) {
+		ra, rb = rb, ra
+	}
+	if max >= 0 && len(ra)-len(rb) > max {
+		return max + 1
+	}
+	if len(rb) == 0 {
+		return len(ra)
+	}
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		rowMin := curr[0]
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			v := prev[j-1] + cost
+			if prev[j]+1 < v {
+				v = prev[j] + 1
+			}
+			if curr[j-1]+1 < v {
+				v = curr[j-1] + 1
+			}
+			curr[j] = v
+			if v < rowMin {
+				rowMin = v
+			}
+		}
+		if max >= 0 && rowMin > max {
+			return max + 1
+		}
+		prev, curr = curr, prev
+	}
+	if max >= 0 && prev[len(rb)] > max {
+		return max + 1
+	}
+	return prev[len(rb)]
+}
+
+// Suggest returns up to max of the candidates that are most similar to the
+// input, closest first; a max of 0 or less returns all similar candidates.
+// Candidates that start with the input rank highest, followed by those within
+// a small edit distance (scaled by the length of the input), all compared
+// case insensitively. This is intended for "did you mean" style help when a
+// user mistypes a command or flag name.
+func Suggest(input string, candidates []string, max int) []string {
+	in := strings.ToLower(input)
+	threshold := (utf8.RuneCountInString(in) + 2) / 3
+	if threshold < 1 {
+		threshold = 1
+	}
+	type suggestion struct {
+		value string
+		score int
+	}
+	var suggestions []suggestion
+	for _, c := range candidates {
+		lc := strings.ToLower(c)
+		if in != "" && strings.HasPrefix(lc, in) {
+			suggestions = append(suggestions, suggestion{c, 0})
+			continue
+		}
+		if d := LevenshteinMax(in, lc, threshold); d <= threshold {
+			suggestions = append(suggestions, suggestion{c, d + 1})
+		}
+	}
+	sort.SliceStable(suggestions, func(x int, y int) bool {
+		return suggestions[x].score < suggestions[y].score
+	})
+	if max > 0 && len(suggestions) > max {
+		suggestions = suggestions[:max]
+	}
+	out := make([]string, 0, len(suggestions))
+	for _, s := range suggestions {
+		out = append(out, s.value)
+	}
+	return out
+}
+
+// DidYouMean returns a sentence like "Did you mean: status, stash?" built from
+// Suggest(input, candidates, max), or an empty string if there are no
+// suggestions.
+func DidYouMean(input string, candidates []string, max int) string {
+	suggestions := Suggest(input, candidates, max)
+	if len(suggestions) == 0 {
+		return ""
+	}
+	return "Did you mean: " + strings.Join(suggestions, ", ") + "?"
+}
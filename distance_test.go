@@ -0,0 +1,90 @@
+package brimtext
+
+import (
+	"testing"
+)
+
+func TestLevenshtein(t *testing.T) {
+	for in, exp := range map[[2]string]int{
+		{"", ""}:                   0,
+		{"", "abc"}:                3,
+		{"abc", ""}:                3,
+		{"abc", "abc"}:             0,
+		{"kitten", "sitting"}:      3,
+		{"sitting", "kitten"}:      3,
+		{"flaw", "lawn"}:           2,
+		{"héllo", "hello"}:         1,
+		{"日本語", "日本"}:              1,
+		{"--verbose", "--vrebose"}: 2,
+	} {
+		out := Levenshtein(in[0], in[1])
+		if out != exp {
+			t.Errorf("Levenshtein(%#v, %#v) %d != %d", in[0], in[1], out, exp)
+		}
+	}
+}
+
+func TestLevenshteinMax(t *testing.T) {
+	for _, v := range []struct {
+		a   string
+		b   string
+		max int
+		exp int
+	}{
+		{"kitten", "sitting", 3, 3},
+		{"kitten", "sitting", 2, 3},
+		{"kitten", "sitting", 0, 1},
+		{"kitten", "sitting", -1, 3},
+		{"a", "abcdefgh", 2, 3},
+		{"abc", "abc", 0, 0},
+	} {
+		out := LevenshteinMax(v.a, v.b, v.max)
+		if out != v.exp {
+			t.Errorf("LevenshteinMax(%#v, %#v, %d) %d != %d", v.a, v.b, v.max, out, v.exp)
+		}
+	}
+}
+
+func TestSuggest(t *testing.T) {
+	candidates := []string{"status", "stash", "start", "commit", "checkout", "cherry-pick"}
+	for _, v := range []struct {
+		in  string
+		max int
+		exp []string
+	}{
+		{"stauts", 0, []string{"status", "start"}},
+		{"stauts", 1, []string{"status"}},
+		{"sta", 0, []string{"status", "stash", "start"}},
+		{"sta", 2, []string{"status", "stash"}},
+		{"comit", 0, []string{"commit"}},
+		{"CHECKOUT", 0, []string{"checkout"}},
+		{"xyzzy", 0, []string{}},
+		{"", 0, []string{}},
+	} {
+		out := Suggest(v.in, candidates, v.max)
+		if len(out) != len(v.exp) {
+			t.Errorf("Suggest(%#v, %d) %#v != %#v", v.in, v.max, out, v.exp)
+			continue
+		}
+		for i := range out {
+			if out[i] != v.exp[i] {
+				t.Errorf("Suggest(%#v, %d) %#v != %#v", v.in, v.max, out, v.exp)
+				break
+			}
+		}
+	}
+}
+
+func TestDidYouMean(t *testing.T) {
+	candidates := []string{"status", "stash", "commit"}
+	for in, exp := range map[string]string{
+		"stat":  "Did you mean: status, stash?",
+		"comit": "Did you mean: commit?",
+		"xyzzy": "",
+	} {
+		out := DidYouMean(in, candidates, 0)
+		if out != exp {
+			t.Errorf("DidYouMean(%#v) %#v != %#v", in, out, exp)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package brimtext
+
+import (
+	"strings"
+)
+
+// ErrorListOptions controls the output of ErrorList.
+type ErrorListOptions struct {
+	// Width is the width to wrap to, with the same meaning as it has for
+	// Wrap.
+	Width int
+	// Bullet prefixes each item; continuation lines of an item are indented
+	// by the width of the bullet.
+	Bullet string
+	// Indent is prefixed once for each level of nesting.
+	Indent string
+	// Nested will render errors that wrap several errors, like
+	// fmt.Errorf("config: %w", errors.Join(errA, errB)), as a "Config:" item
+	// with errA and errB nested beneath it. Otherwise, such errors are
+	// flattened into "Config: errA." and "Config: errB." items.
+	Nested bool
+	// SentenceOptions are used to pass each item through
+	// SentenceWithOptions; if nil, Sentence is used.
+	SentenceOptions *SentenceOptions
+}
+
+// NewErrorListOptions gives:
+//
+//	&ErrorListOptions{Bullet: "* ", Indent: "  ", Nested: true}
+func NewErrorListOptions() *ErrorListOptions {
+	return &ErrorListOptions{Bullet: "* ", Indent: "  ", Nested: true}
+}
+
+// ErrorList formats the errors as a wrapped, bulleted list with each item
+// passed through Sentence, useful for reporting many validation errors at
+// once. Errors that wrap several errors, such as those from errors.Join, are
+// expanded into their individual errors. If opts is nil,
+// NewErrorListOptions is used.
+//
+// For example:
+//
+//	fmt.Println("Errors:")
+//	fmt.Println(brimtext.ErrorList(errs, nil))
+//
+//	Errors:
+//	* Missing name.
+//	* Config:
+//	  * Port must be a number.
+//	  * Host is required.
+func ErrorList(errs []error, opts *ErrorListOptions) string {
+	if opts == nil {
+		opts = NewErrorListOptions()
+	}
+	var lines []string
+	var add func(err error, level int, prefix string)
+	add = func(err error, level int, prefix string) {
+		if err == nil {
+			return
+		}
+		if m, ok := err.(interface{ Unwrap() []error }); ok {
+			for _, e := range m.Unwrap() {
+				add(e, level, prefix)
+			}
+			return
+		}
+		msg := err.Error()
+		if u, ok := err.(interface{ Unwrap() error }); ok {
+			inner := u.Unwrap()
+			if _, ok := inner.(interface{ Unwrap() []error }); ok && strings.HasSuffix(msg, inner.Error()) {
+				heading := strings.TrimSpace(strings.TrimSuffix(msg, inner.Error()))
+				if heading != "" {
+					if opts.Nested {
+						lines = append(lines, errorListItem(upperFirst(prefix+heading), level, opts))
+						add(inner, level+1, "")
+					} else {
+						add(inner, level, prefix+heading+" ")
+					}
+					return
+				}
+			}
+		}
+		lines = append(lines, errorListItem(SentenceWithOptions(prefix+msg, opts.SentenceOptions), level, opts))
+	}
+	for _, err := range errs {
+		add(err, 0, "")
+	}
+	return strings.Join(lines, "\n")
+}
+
+func errorListItem(text string, level int, opts *ErrorListOptions) string {
+	indent := strings.Repeat(opts.Indent, level)
+	return Wrap(text, opts.Width, indent+opts.Bullet, indent+strings.Repeat(" ", DisplayWidth(opts.Bullet)))
+}
@@ -0,0 +1,64 @@
+package brimtext
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+type testMultiError []error
+
+func (m testMultiError) Error() string {
+	var s []string
+	for _, err := range m {
+		s = append(s, err.Error())
+	}
+	return strings.Join(s, "\n")
+}
+
+func (m testMultiError) Unwrap() []error {
+	return m
+}
+
+func TestErrorList(t *testing.T) {
+	errs := []error{
+		errors.New("missing name"),
+		fmt.Errorf("config: %w", testMultiError{errors.New("port must be a number"), errors.New("host is required")}),
+		testMultiError{errors.New("first"), nil, errors.New("second")},
+	}
+	out := ErrorList(errs, nil)
+	exp := `* Missing name.
+* Config:
+  * Port must be a number.
+  * Host is required.
+* First.
+* Second.`
+	if out != exp {
+		t.Errorf("ErrorList nested %#v != %#v", out, exp)
+	}
+	opts := NewErrorListOptions()
+	opts.Nested = false
+	opts.Bullet = "- "
+	out = ErrorList(errs, opts)
+	exp = `- Missing name.
+- Config: port must be a number.
+- Config: host is required.
+- First.
+- Second.`
+	if out != exp {
+		t.Errorf("ErrorList flat %#v != %#v", out, exp)
+	}
+	opts = NewErrorListOptions()
+	opts.Width = 20
+	out = ErrorList([]error{errors.New("this is a long error that must wrap")}, opts)
+	exp = `* This is a long
+  error that must
+  wrap.`
+	if out != exp {
+		t.Errorf("ErrorList wrapped %#v != %#v", out, exp)
+	}
+	if out = ErrorList(nil, nil); out != "" {
+		t.Errorf("ErrorList(nil) %#v != %#v", out, "")
+	}
+}
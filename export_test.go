@@ -0,0 +1,9 @@
+package brimtext
+
+// SetAlignTTYWidth replaces the terminal width used for AlignOptions.FitWidth
+// with f, returning a func that restores it.
+func SetAlignTTYWidth(f func() int) func() {
+	prev := alignTTYWidth
+	alignTTYWidth = f
+	return func() { alignTTYWidth = prev }
+}
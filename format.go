@@ -0,0 +1,162 @@
+package brimtext
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ColumnFormatter converts a data cell into its display form, such as
+// "1234567" into "1.18MiB"; see AlignOptions.Formatters. The formatters given
+// here return cells they can't parse unchanged, so blank and placeholder cells
+// pass through as is.
+type ColumnFormatter func(cell string) string
+
+// applyFormatters returns the data with each cell after the header rows
+// passed through its column's formatter, if any.
+func applyFormatters(data [][]string, formatters []ColumnFormatter, headerRows int) [][]string {
+	newData := make([][]string, len(data))
+	for r, row := range data {
+		if r < headerRows || row == nil {
+			newData[r] = row
+			continue
+		}
+		newRow := make([]string, len(row))
+		for c, cell := range row {
+			if c < len(formatters) && formatters[c] != nil {
+				cell = formatters[c](cell)
+			}
+			newRow[c] = cell
+		}
+		newData[r] = newRow
+	}
+	return newData
+}
+
+// formatFloat parses the cell as a number, allowing surrounding whitespace.
+func formatFloat(cell string) (float64, bool) {
+	v, err := strconv.ParseFloat(strings.TrimSpace(cell), 64)
+	if err != nil || math.IsNaN(v) || math.IsInf(v, 0) {
+		return 0, false
+	}
+	return v, true
+}
+
+// BytesFormatter formats byte counts with HumanSizeBytes1024 if unit is 1024,
+// or HumanSizeBytes1000 otherwise; "1234567" gives "1.18MiB" or "1.23MB".
+func BytesFormatter(unit float64) ColumnFormatter {
+	human := HumanSizeBytes1000
+	if unit == 1024 {
+		human = HumanSizeBytes1024
+	}
+	return func(cell string) string {
+		v, ok := formatFloat(cell)
+		if !ok {
+			return cell
+		}
+		return human(v)
+	}
+}
+
+// ThousandsFormatter separates the thousands of integers with sep, keeping
+// any sign and fractional part; "-1234567.89" with "," gives
+// "-1,234,567.89".
+func ThousandsFormatter(sep string) ColumnFormatter {
+	return func(cell string) string {
+		s := strings.TrimSpace(cell)
+		if _, ok := formatFloat(s); !ok {
+			return cell
+		}
+		sign := ""
+		if s != "" && (s[0] == '-' || s[0] == '+') {
+			sign, s = s[:1], s[1:]
+		}
+		frac := ""
+		if i := strings.IndexByte(s, '.'); i >= 0 {
+			s, frac = s[:i], s[i:]
+		}
+		if !allDigits(s) {
+			return cell
+		}
+		for i := len(s) - 3; i > 0; i -= 3 {
+			s = s[:i] + sep + s[i:]
+		}
+		return sign + s + frac
+	}
+}
+
+// PercentFormatter formats ratios as percentages with the given number of
+// decimal places; "0.256" with 1 gives "25.6%".
+func PercentFormatter(decimals int) ColumnFormatter {
+	return func(cell string) string {
+		v, ok := formatFloat(cell)
+		if !ok {
+			return cell
+		}
+		return strconv.FormatFloat(v*100, 'f', decimals, 64) + "%"
+	}
+}
+
+// DurationFormatter formats durations, given as a number of seconds or
+// anything time.ParseDuration accepts, rounded to round if it is > 0;
+// "3723.4" with time.Second gives "1h2m3s".
+func DurationFormatter(round time.Duration) ColumnFormatter {
+	return func(cell string) string {
+		var d time.Duration
+		if v, ok := formatFloat(cell); ok {
+			d = time.Duration(v * float64(time.Second))
+		} else if p, err := time.ParseDuration(strings.TrimSpace(cell)); err == nil {
+			d = p
+		} else {
+			return cell
+		}
+		if round > 0 {
+			d = d.Round(round)
+		}
+		return d.String()
+	}
+}
+
+// TimestampFormatter formats timestamps, given as Unix seconds or in RFC 3339
+// form, with the time.Format layout, in loc if it isn't nil. With a nil loc,
+// RFC 3339 timestamps keep their own zones and Unix seconds are shown in UTC.
+func TimestampFormatter(layout string, loc *time.Location) ColumnFormatter {
+	return func(cell string) string {
+		var t time.Time
+		s := strings.TrimSpace(cell)
+		if v, ok := formatFloat(s); ok {
+			sec, frac := math.Modf(v)
+			t = time.Unix(int64(sec), int64(frac*1e9)).UTC()
+		} else if p, err := time.Parse(time.RFC3339Nano, s); err == nil {
+			t = p
+		} else {
+			return cell
+		}
+		if loc != nil {
+			t = t.In(loc)
+		}
+		return t.Format(layout)
+	}
+}
+
+// BooleanFormatter formats cells TrueString recognizes as trueText and those
+// FalseString recognizes as falseText; empty texts default to "✓" and "✗".
+func BooleanFormatter(trueText string, falseText string) ColumnFormatter {
+	if trueText == "" {
+		trueText = "✓"
+	}
+	if falseText == "" {
+		falseText = "✗"
+	}
+	return func(cell string) string {
+		s := strings.TrimSpace(cell)
+		if TrueString(s) {
+			return trueText
+		}
+		if FalseString(s) {
+			return falseText
+		}
+		return cell
+	}
+}
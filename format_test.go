@@ -0,0 +1,76 @@
+package brimtext
+
+import (
+	"testing"
+	"time"
+)
+
+func TestColumnFormatters(t *testing.T) {
+	est := time.FixedZone("EST", -5*60*60)
+	for _, v := range []struct {
+		name string
+		f    ColumnFormatter
+		in   string
+		exp  string
+	}{
+		{"bytes1024", BytesFormatter(1024), "1234567", "1.18MiB"},
+		{"bytes1000", BytesFormatter(1000), "1234567", "1.23MB"},
+		{"bytes small", BytesFormatter(1024), " 123 ", "123B"},
+		{"bytes blank", BytesFormatter(1024), "-", "-"},
+		{"thousands", ThousandsFormatter(","), "1234567", "1,234,567"},
+		{"thousands sep", ThousandsFormatter("."), "1234567", "1.234.567"},
+		{"thousands neg frac", ThousandsFormatter(","), "-1234567.89", "-1,234,567.89"},
+		{"thousands short", ThousandsFormatter(","), "123", "123"},
+		{"thousands exp", ThousandsFormatter(","), "1e6", "1e6"},
+		{"thousands text", ThousandsFormatter(","), "n/a", "n/a"},
+		{"percent", PercentFormatter(1), "0.256", "25.6%"},
+		{"percent whole", PercentFormatter(0), "1", "100%"},
+		{"percent text", PercentFormatter(0), "", ""},
+		{"duration seconds", DurationFormatter(time.Second), "3723.4", "1h2m3s"},
+		{"duration parsed", DurationFormatter(time.Millisecond), "1.23456s", "1.235s"},
+		{"duration unrounded", DurationFormatter(0), "90", "1m30s"},
+		{"duration negative", DurationFormatter(time.Second), "-1.5", "-2s"},
+		{"duration text", DurationFormatter(time.Second), "soon", "soon"},
+		{"timestamp unix", TimestampFormatter("2006-01-02 15:04", nil), "1500000000", "2017-07-14 02:40"},
+		{"timestamp rfc3339", TimestampFormatter(time.Kitchen, nil), "2020-01-02T15:04:05-07:00", "3:04PM"},
+		{"timestamp loc", TimestampFormatter("15:04 MST", est), "1500000000", "21:40 EST"},
+		{"timestamp text", TimestampFormatter(time.Kitchen, nil), "never", "never"},
+		{"boolean true", BooleanFormatter("", ""), "yes", "✓"},
+		{"boolean false", BooleanFormatter("", ""), "False", "✗"},
+		{"boolean custom", BooleanFormatter("on", "off"), "1", "on"},
+		{"boolean text", BooleanFormatter("", ""), "maybe", "maybe"},
+	} {
+		if out := v.f(v.in); out != v.exp {
+			t.Errorf("%s: %#v != %#v", v.name, out, v.exp)
+		}
+	}
+}
+
+func TestAlignFormatters(t *testing.T) {
+	data := [][]string{
+		{"Name", "Size", "OK"},
+		nil,
+		{"a", "1234567", "true"},
+		{"b", "512", "false"},
+	}
+	opts := NewAlignOptions(WithFormatters(nil, BytesFormatter(1024), BooleanFormatter("", "")))
+	out := Align(data, opts)
+	exp := "Name Size    OK\n\n" +
+		"a    1.18MiB ✓\n" +
+		"b    512B    ✗\n"
+	if out != exp {
+		t.Errorf("%#v != %#v", out, exp)
+	}
+	if data[2][1] != "1234567" {
+		t.Errorf("Align changed the data: %#v", data[2])
+	}
+	g, r, z := string(ANSIEscape.FGreen), string(ANSIEscape.FRed), string(ANSIEscape.Reset)
+	opts.HeatScales = []*HeatScale{nil, NewHeatScale()}
+	out = Align(data, opts)
+	exp = "Name Size    OK\n\n" +
+		"a    " + r + "1.18MiB" + z + " ✓\n" +
+		"b    " + g + "512B" + z + "    ✗\n"
+	if out != exp {
+		t.Errorf("%#v != %#v", out, exp)
+	}
+}
@@ -2,4 +2,7 @@ module github.com/gholt/brimtext
 
 go 1.12
 
-require golang.org/x/crypto v0.0.0-20190701094942-4def268fd1a4
+require (
+	golang.org/x/crypto v0.0.0-20190701094942-4def268fd1a4
+	golang.org/x/text v0.3.3
+)
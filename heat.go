@@ -0,0 +1,99 @@
+package brimtext
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+// HeatScale colors the numeric cells of a column by where each value falls
+// between the smallest and largest values in that column; see
+// AlignOptions.HeatScales.
+type HeatScale struct {
+	// Colors are the ANSI escape codes making up the gradient, from the
+	// lowest values to the highest; each value is given the nearest step.
+	Colors [][]byte
+	// Min and Max pin the ends of the scale; if they are equal, the smallest
+	// and largest values in the column are used instead. Values outside the
+	// scale are clamped to it.
+	Min float64
+	Max float64
+}
+
+// NewHeatScale gives a green, yellow, red foreground HeatScale.
+func NewHeatScale() *HeatScale {
+	return &HeatScale{Colors: [][]byte{ANSIEscape.FGreen, ANSIEscape.FYellow, ANSIEscape.FRed}}
+}
+
+// NewBackgroundHeatScale gives a green, yellow, red background HeatScale.
+func NewBackgroundHeatScale() *HeatScale {
+	return &HeatScale{Colors: [][]byte{ANSIEscape.BGreen, ANSIEscape.BYellow, ANSIEscape.BRed}}
+}
+
+// heatValue parses the cell as a number, allowing surrounding whitespace,
+// thousands separators, and a trailing percent sign.
+func heatValue(cell string) (float64, bool) {
+	cell = strings.TrimSpace(StripANSIEscapes(cell))
+	cell = strings.TrimSuffix(cell, "%")
+	cell = strings.Replace(cell, ",", "", -1)
+	if cell == "" {
+		return 0, false
+	}
+	v, err := strconv.ParseFloat(cell, 64)
+	if err != nil || math.IsNaN(v) || math.IsInf(v, 0) {
+		return 0, false
+	}
+	return v, true
+}
+
+// applyHeatScales returns the data with the cells of each column that has a
+// HeatScale wrapped in the scale's colors, according to the numeric values
+// of the matching cells of values, normally the data before any Formatters.
+// Cells whose values aren't numbers, such as headers, are left as is.
+func applyHeatScales(data [][]string, values [][]string, scales []*HeatScale) [][]string {
+	newData := make([][]string, len(data))
+	copy(newData, data)
+	copied := make([]bool, len(data))
+	for col, scale := range scales {
+		if scale == nil || len(scale.Colors) == 0 {
+			continue
+		}
+		min, max := scale.Min, scale.Max
+		if min == max {
+			first := true
+			for _, row := range values {
+				if col >= len(row) {
+					continue
+				}
+				if v, ok := heatValue(row[col]); ok {
+					if first || v < min {
+						min = v
+					}
+					if first || v > max {
+						max = v
+					}
+					first = false
+				}
+			}
+		}
+		for r, row := range newData {
+			if col >= len(row) || col >= len(values[r]) {
+				continue
+			}
+			v, ok := heatValue(values[r][col])
+			if !ok {
+				continue
+			}
+			step := 0
+			if max > min {
+				step = int(math.Floor((math.Min(math.Max(v, min), max)-min)/(max-min)*float64(len(scale.Colors)-1) + 0.5))
+			}
+			if !copied[r] {
+				newData[r] = append([]string(nil), row...)
+				copied[r] = true
+			}
+			newData[r][col] = string(scale.Colors[step]) + row[col] + string(ANSIEscape.Reset)
+		}
+	}
+	return newData
+}
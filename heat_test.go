@@ -0,0 +1,72 @@
+package brimtext
+
+import (
+	"testing"
+)
+
+func TestHeatValue(t *testing.T) {
+	for _, v := range []struct {
+		in  string
+		exp float64
+		ok  bool
+	}{
+		{"12", 12, true},
+		{" 1,234.5 ", 1234.5, true},
+		{"45%", 45, true},
+		{"\x1b[1m7\x1b[0m", 7, true},
+		{"", 0, false},
+		{"Name", 0, false},
+		{"NaN", 0, false},
+		{"Inf", 0, false},
+		{"+Inf", 0, false},
+		{"-Inf", 0, false},
+	} {
+		out, ok := heatValue(v.in)
+		if out != v.exp || ok != v.ok {
+			t.Errorf("heatValue(%#v) %v, %v != %v, %v", v.in, out, ok, v.exp, v.ok)
+		}
+	}
+}
+
+func TestAlignHeatScales(t *testing.T) {
+	g, y, r, z := string(ANSIEscape.FGreen), string(ANSIEscape.FYellow), string(ANSIEscape.FRed), string(ANSIEscape.Reset)
+	data := [][]string{
+		{"Host", "Load"},
+		nil,
+		{"a", "1"},
+		{"b", "5"},
+		{"c", "9"},
+		{"d", "-"},
+	}
+	opts := NewDefaultAlignOptions()
+	opts.HeatScales = []*HeatScale{nil, NewHeatScale()}
+	out := Align(data, opts)
+	exp := "Host Load\n" +
+		"\n" +
+		"a    " + g + "1" + z + "\n" +
+		"b    " + y + "5" + z + "\n" +
+		"c    " + r + "9" + z + "\n" +
+		"d    -\n"
+	if out != exp {
+		t.Errorf("Align HeatScales %#v != %#v", out, exp)
+	}
+	if data[2][1] != "1" {
+		t.Errorf("Align HeatScales modified the data: %#v", data[2])
+	}
+	opts.HeatScales = []*HeatScale{nil, &HeatScale{Colors: [][]byte{ANSIEscape.FGreen, ANSIEscape.FRed}, Min: 0, Max: 100}}
+	out = Align([][]string{{"a", "20"}, {"b", "80"}, {"c", "200"}}, opts)
+	exp = "a " + g + "20" + z + "\n" +
+		"b " + r + "80" + z + "\n" +
+		"c " + r + "200" + z + "\n"
+	if out != exp {
+		t.Errorf("Align HeatScales pinned %#v != %#v", out, exp)
+	}
+	opts.HeatScales = []*HeatScale{NewHeatScale()}
+	out = Align([][]string{{"1"}, {"Inf"}, {"3"}}, opts)
+	exp = g + "1" + z + "\n" +
+		"Inf\n" +
+		r + "3" + z + "\n"
+	if out != exp {
+		t.Errorf("Align HeatScales Inf %#v != %#v", out, exp)
+	}
+}
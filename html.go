@@ -0,0 +1,127 @@
+package brimtext
+
+import (
+	"bytes"
+	"html"
+	"strings"
+)
+
+// HTMLAlignOptions controls the output of AlignHTML.
+type HTMLAlignOptions struct {
+	// Alignments are the alignments of each column, given as text-align
+	// styles on its cells; Left columns, and those beyond the alignments
+	// given, have no style.
+	Alignments []Alignment
+	// Formatters, indexed by column, convert each cell in that column other
+	// than those of the header rows, as with AlignOptions.Formatters.
+	Formatters []ColumnFormatter
+	// Class, if not empty, is the class attribute of the table element.
+	Class string
+	// Indent, if not empty, puts each element other than the cells on its
+	// own line, indented by Indent for each level of nesting. If empty, the
+	// table is output as a single line.
+	Indent string
+}
+
+// NewHTMLAlignOptions gives:
+//
+//	&HTMLAlignOptions{Indent: "  "}
+func NewHTMLAlignOptions() *HTMLAlignOptions {
+	return &HTMLAlignOptions{Indent: "  "}
+}
+
+// AlignHTML returns the same data Align takes as an HTML table, so one set of
+// rows can feed both terminal and web reports:
+//
+//	<table>
+//	  <thead>
+//	    <tr><th>Name</th><th style="text-align: right">Count</th></tr>
+//	  </thead>
+//	  <tbody>
+//	    <tr><td>alpha</td><td style="text-align: right">1</td></tr>
+//	  </tbody>
+//	</table>
+//
+// The rows before the first nil row are the header, in a thead with th
+// cells; without a nil row there is no thead. Each later nil row starts a new
+// tbody. Cells are HTML escaped with their ANSI escape codes removed, and
+// newlines within them become br elements. If opts is nil,
+// NewHTMLAlignOptions is used.
+func AlignHTML(data [][]string, opts *HTMLAlignOptions) string {
+	if len(data) == 0 {
+		return ""
+	}
+	if opts == nil {
+		opts = NewHTMLAlignOptions()
+	}
+	headerRows := alignHeaderRows(data, &AlignOptions{})
+	if len(opts.Formatters) > 0 {
+		data = applyFormatters(data, opts.Formatters, headerRows)
+	}
+	var buf bytes.Buffer
+	open := func(depth int, tag string) {
+		if opts.Indent != "" {
+			buf.WriteString(strings.Repeat(opts.Indent, depth))
+		}
+		buf.WriteString(tag)
+		if opts.Indent != "" {
+			buf.WriteByte('\n')
+		}
+	}
+	if opts.Class != "" {
+		open(0, `<table class="`+html.EscapeString(opts.Class)+`">`)
+	} else {
+		open(0, "<table>")
+	}
+	section := ""
+	for r, row := range data {
+		if row == nil {
+			if section != "" {
+				open(1, "</"+section+">")
+				section = ""
+			}
+			continue
+		}
+		if section == "" {
+			section = "tbody"
+			if r < headerRows {
+				section = "thead"
+			}
+			open(1, "<"+section+">")
+		}
+		cell := "td"
+		if section == "thead" {
+			cell = "th"
+		}
+		if opts.Indent != "" {
+			buf.WriteString(strings.Repeat(opts.Indent, 2))
+		}
+		buf.WriteString("<tr>")
+		for c, v := range row {
+			buf.WriteString("<" + cell)
+			if c < len(opts.Alignments) {
+				switch opts.Alignments[c] {
+				case Right, Decimal:
+					buf.WriteString(` style="text-align: right"`)
+				case Center:
+					buf.WriteString(` style="text-align: center"`)
+				case Justify:
+					buf.WriteString(` style="text-align: justify"`)
+				}
+			}
+			buf.WriteByte('>')
+			v = strings.Replace(StripANSIEscapes(v), "\r\n", "\n", -1)
+			buf.WriteString(strings.Replace(html.EscapeString(v), "\n", "<br>", -1))
+			buf.WriteString("</" + cell + ">")
+		}
+		buf.WriteString("</tr>")
+		if opts.Indent != "" {
+			buf.WriteByte('\n')
+		}
+	}
+	if section != "" {
+		open(1, "</"+section+">")
+	}
+	open(0, "</table>")
+	return buf.String()
+}
@@ -0,0 +1,43 @@
+package brimtext
+
+import (
+	"testing"
+)
+
+func TestAlignHTML(t *testing.T) {
+	data := [][]string{
+		{"Name", "Count"},
+		nil,
+		{"a<b>", "1234"},
+		{"two\nlines", "\x1b[31m5\x1b[0m"},
+		nil,
+		{"Total", "1239"},
+	}
+	opts := NewHTMLAlignOptions()
+	opts.Alignments = []Alignment{Left, Right}
+	opts.Formatters = []ColumnFormatter{nil, ThousandsFormatter(",")}
+	out := AlignHTML(data, opts)
+	exp := "<table>\n" +
+		"  <thead>\n" +
+		"    <tr><th>Name</th><th style=\"text-align: right\">Count</th></tr>\n" +
+		"  </thead>\n" +
+		"  <tbody>\n" +
+		"    <tr><td>a&lt;b&gt;</td><td style=\"text-align: right\">1,234</td></tr>\n" +
+		"    <tr><td>two<br>lines</td><td style=\"text-align: right\">5</td></tr>\n" +
+		"  </tbody>\n" +
+		"  <tbody>\n" +
+		"    <tr><td>Total</td><td style=\"text-align: right\">1,239</td></tr>\n" +
+		"  </tbody>\n" +
+		"</table>\n"
+	if out != exp {
+		t.Errorf("%#v != %#v", out, exp)
+	}
+	out = AlignHTML([][]string{{"a", "b&c"}}, &HTMLAlignOptions{Class: "report", Alignments: []Alignment{Center}})
+	exp = `<table class="report"><tbody><tr><td style="text-align: center">a</td><td>b&amp;c</td></tr></tbody></table>`
+	if out != exp {
+		t.Errorf("%#v != %#v", out, exp)
+	}
+	if out = AlignHTML(nil, nil); out != "" {
+		t.Errorf("%#v != \"\"", out)
+	}
+}
@@ -0,0 +1,57 @@
+package brimtext
+
+import (
+	"bytes"
+	"io"
+)
+
+// IndentWriter is an io.Writer that starts every line of the text written
+// through it with a prefix, such as to nest the output of a subprocess under
+// a heading. Writes that don't end on a line boundary are fine; the prefix of
+// a line is written along with the line's first byte, so a final newline
+// never leaves a dangling prefix.
+type IndentWriter struct {
+	// FirstPrefix starts the first line written.
+	FirstPrefix string
+	// Prefix starts each line after the first.
+	Prefix  string
+	w       io.Writer
+	started bool
+	midLine bool
+}
+
+// NewIndentWriter returns an IndentWriter writing to w with every line
+// starting with prefix; set FirstPrefix for a different first line, such as
+// a list bullet.
+func NewIndentWriter(w io.Writer, prefix string) *IndentWriter {
+	return &IndentWriter{FirstPrefix: prefix, Prefix: prefix, w: w}
+}
+
+// Write writes p to the underlying writer with the prefixes added.
+func (iw *IndentWriter) Write(p []byte) (int, error) {
+	n := len(p)
+	var out bytes.Buffer
+	for len(p) > 0 {
+		if !iw.midLine {
+			if iw.started {
+				out.WriteString(iw.Prefix)
+			} else {
+				out.WriteString(iw.FirstPrefix)
+				iw.started = true
+			}
+			iw.midLine = true
+		}
+		i := bytes.IndexByte(p, '\n')
+		if i < 0 {
+			out.Write(p)
+			break
+		}
+		out.Write(p[:i+1])
+		iw.midLine = false
+		p = p[i+1:]
+	}
+	if _, err := iw.w.Write(out.Bytes()); err != nil {
+		return 0, err
+	}
+	return n, nil
+}
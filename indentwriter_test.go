@@ -0,0 +1,36 @@
+package brimtext
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestIndentWriter(t *testing.T) {
+	for _, v := range []struct {
+		in    []string
+		first string
+		exp   string
+	}{
+		{[]string{"one\ntwo\n"}, "", "    one\n    two\n"},
+		{[]string{"o", "ne\nt", "wo\n", "three"}, "", "    one\n    two\n    three"},
+		{[]string{"one\n", "\n", "two\n"}, "", "    one\n    \n    two\n"},
+		{[]string{"one\ntwo\n"}, "  * ", "  * one\n    two\n"},
+		{[]string{"one\n", "two\n"}, "  * ", "  * one\n    two\n"},
+		{[]string{""}, "", ""},
+	} {
+		var buf bytes.Buffer
+		iw := NewIndentWriter(&buf, "    ")
+		if v.first != "" {
+			iw.FirstPrefix = v.first
+		}
+		for _, s := range v.in {
+			n, err := iw.Write([]byte(s))
+			if n != len(s) || err != nil {
+				t.Errorf("Write(%#v) %d, %v", s, n, err)
+			}
+		}
+		if out := buf.String(); out != v.exp {
+			t.Errorf("%#v: %#v != %#v", v.in, out, v.exp)
+		}
+	}
+}
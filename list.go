@@ -0,0 +1,433 @@
+package brimtext
+
+import (
+	"bytes"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DefinitionListOptions controls the output of DefinitionList.
+type DefinitionListOptions struct {
+	// Width is the width to wrap to, with the same meaning as the width given
+	// to Wrap.
+	Width int
+	// Indent prefixes each term.
+	Indent string
+	// Separator goes between the term column and the definitions.
+	Separator string
+	// MaxTermWidth limits the width of the term column; longer terms will
+	// have their definitions start on the following line instead. A
+	// MaxTermWidth of 0 means no limit.
+	MaxTermWidth int
+}
+
+// NewDefinitionListOptions gives:
+//
+//	&DefinitionListOptions{Indent: "  ", Separator: "  ", MaxTermWidth: 24}
+func NewDefinitionListOptions() *DefinitionListOptions {
+	return &DefinitionListOptions{Indent: "  ", Separator: "  ", MaxTermWidth: 24}
+}
+
+// DefinitionList formats term and definition pairs with the terms in a left
+// aligned column and the definitions wrapped with a hanging indent, the usual
+// layout for command line flag help:
+//
+//	-h, --help     Show this help and exit.
+//	-v, --verbose  Output more information about what is being done, which
+//	               can be quite a lot.
+//	--a-very-long-flag-name
+//	               Definitions for terms wider than MaxTermWidth start on
+//	               the next line.
+//
+// Definitions may have multiple paragraphs, separated by blank lines. If opts
+// is nil, NewDefinitionListOptions is used.
+func DefinitionList(pairs [][2]string, opts *DefinitionListOptions) string {
+	if opts == nil {
+		opts = NewDefinitionListOptions()
+	}
+	width := opts.Width
+	if width < 1 {
+		width = GetTTYWidth() - 1 + width
+	}
+	termWidth := 0
+	for _, pair := range pairs {
+		w := DisplayWidth(pair[0])
+		if (opts.MaxTermWidth == 0 || w <= opts.MaxTermWidth) && w > termWidth {
+			termWidth = w
+		}
+	}
+	hanging := strings.Repeat(" ", DisplayWidth(opts.Indent)+termWidth+DisplayWidth(opts.Separator))
+	var buf bytes.Buffer
+	for _, pair := range pairs {
+		term, def := pair[0], pair[1]
+		w := DisplayWidth(term)
+		first := hanging
+		if w > termWidth {
+			buf.WriteString(opts.Indent + term)
+			buf.WriteByte('\n')
+		} else {
+			first = opts.Indent + term + strings.Repeat(" ", termWidth-w) + opts.Separator
+		}
+		if strings.TrimSpace(def) == "" {
+			if w <= termWidth {
+				buf.WriteString(strings.TrimRight(first, " "))
+				buf.WriteByte('\n')
+			}
+			continue
+		}
+		buf.WriteString(wrapParagraphs(def, width, first, hanging))
+		buf.WriteByte('\n')
+	}
+	return buf.String()
+}
+
+// wrapParagraphs is like Wrap but only the first paragraph that isn't blank
+// gets indent1; the following paragraphs get indent2 for all their lines.
+func wrapParagraphs(text string, width int, indent1 string, indent2 string) string {
+	text = strings.Replace(text, "\r\n", "\n", -1)
+	var pars []string
+	for _, par := range strings.Split(text, "\n\n") {
+		if strings.TrimSpace(par) == "" {
+			continue
+		}
+		if len(pars) == 0 {
+			pars = append(pars, Wrap(par, width, indent1, indent2))
+		} else {
+			pars = append(pars, Wrap(par, width, indent2, indent2))
+		}
+	}
+	return strings.Join(pars, "\n\n")
+}
+
+// KeyValueOptions controls the output of KeyValue.
+type KeyValueOptions struct {
+	// Width is the width to wrap to, with the same meaning as the width given
+	// to Wrap.
+	Width int
+	// Indent is the unit of nesting; keys starting with one or more Indent
+	// are nested beneath the previous key with less indentation.
+	Indent string
+	// Colon is appended to each key.
+	Colon string
+	// Gap goes between the padded keys and their values.
+	Gap string
+}
+
+// NewKeyValueOptions gives:
+//
+//	&KeyValueOptions{Indent: "  ", Colon: ":", Gap: "  "}
+func NewKeyValueOptions() *KeyValueOptions {
+	return &KeyValueOptions{Indent: "  ", Colon: ":", Gap: "  "}
+}
+
+// KeyValue formats key and value pairs in the style of "kubectl describe",
+// with keys padded to the longest key of their section and values wrapped to
+// the remaining width. Keys beginning with opts.Indent are nested within the
+// previous less indented key, which usually has an empty value to act as a
+// section heading. For example:
+//
+//	brimtext.KeyValue([][2]string{
+//	    {"Name", "web-1"},
+//	    {"Namespace", "default"},
+//	    {"Containers", ""},
+//	    {"  nginx", ""},
+//	    {"    Image", "nginx:1.19"},
+//	    {"    Port", "80/TCP"},
+//	}, nil)
+//
+// Gives:
+//
+//	Name:       web-1
+//	Namespace:  default
+//	Containers:
+//	  nginx:
+//	    Image:  nginx:1.19
+//	    Port:   80/TCP
+//
+// If opts is nil, NewKeyValueOptions is used.
+func KeyValue(pairs [][2]string, opts *KeyValueOptions) string {
+	if opts == nil {
+		opts = NewKeyValueOptions()
+	}
+	width := opts.Width
+	if width < 1 {
+		width = GetTTYWidth() - 1 + width
+	}
+	levels := make([]int, len(pairs))
+	keys := make([]string, len(pairs))
+	for i, pair := range pairs {
+		key := pair[0]
+		if opts.Indent != "" {
+			for strings.HasPrefix(key, opts.Indent) {
+				key = key[len(opts.Indent):]
+				levels[i]++
+			}
+		}
+		keys[i] = key + opts.Colon
+	}
+	// A section is the run of pairs at one level beneath the same heading;
+	// each gets padded to its own widest key.
+	keyWidths := make([]int, len(pairs))
+	for i := range pairs {
+		if keyWidths[i] != 0 {
+			continue
+		}
+		var members []int
+		max := 0
+		for j := i; j < len(pairs) && levels[j] >= levels[i]; j++ {
+			if levels[j] != levels[i] {
+				continue
+			}
+			members = append(members, j)
+			if w := DisplayWidth(keys[j]); strings.TrimSpace(pairs[j][1]) != "" && w > max {
+				max = w
+			}
+		}
+		if max == 0 {
+			max = -1
+		}
+		for _, j := range members {
+			keyWidths[j] = max
+		}
+	}
+	var buf bytes.Buffer
+	for i, pair := range pairs {
+		indent := strings.Repeat(opts.Indent, levels[i])
+		if strings.TrimSpace(pair[1]) == "" {
+			buf.WriteString(indent + keys[i])
+			buf.WriteByte('\n')
+			continue
+		}
+		first := indent + keys[i] + strings.Repeat(" ", keyWidths[i]-DisplayWidth(keys[i])) + opts.Gap
+		hanging := strings.Repeat(" ", DisplayWidth(first))
+		buf.WriteString(wrapParagraphs(pair[1], width, first, hanging))
+		buf.WriteByte('\n')
+	}
+	return buf.String()
+}
+
+// SeparatorOptions controls the output of AlignOnSeparator.
+type SeparatorOptions struct {
+	// Separator splits each line into its key and value at its first
+	// occurrence; if empty, "=" is used.
+	Separator string
+	// Before and After are put on either side of the separator, in place of
+	// any spaces around it, such as " " and " " for "key = value".
+	Before string
+	After  string
+	// PadAfter pads after the separator rather than before it, aligning
+	// "key: value" style lines on their values.
+	PadAfter bool
+	// Sort orders the lines by their keys, with NaturalLess.
+	Sort bool
+}
+
+// NewSeparatorOptions gives:
+//
+//	&SeparatorOptions{Separator: "=", Before: " ", After: " "}
+func NewSeparatorOptions() *SeparatorOptions {
+	return &SeparatorOptions{Separator: "=", Before: " ", After: " "}
+}
+
+// AlignOnSeparator aligns lines like "timeout=30s" on their separators,
+// padding the keys to a common width, such as for pretty printing config
+// dumps without building a full table. Lines without the separator are kept
+// as they are and don't affect the width. For example:
+//
+//	brimtext.AlignOnSeparator("timeout=30s\nmax_connections=100\n", nil)
+//
+// Gives:
+//
+//	timeout         = 30s
+//	max_connections = 100
+//
+// If opts is nil, NewSeparatorOptions is used.
+func AlignOnSeparator(text string, opts *SeparatorOptions) string {
+	if opts == nil {
+		opts = NewSeparatorOptions()
+	}
+	sep := opts.Separator
+	if sep == "" {
+		sep = "="
+	}
+	lines := blockLines(text)
+	keys := make([]string, len(lines))
+	values := make([]string, len(lines))
+	found := make([]bool, len(lines))
+	width := 0
+	for i, line := range lines {
+		j := strings.Index(line, sep)
+		if j < 0 {
+			keys[i] = line
+			continue
+		}
+		found[i] = true
+		keys[i] = strings.TrimRight(line[:j], " \t")
+		values[i] = strings.TrimLeft(line[j+len(sep):], " \t")
+		if w := DisplayWidth(keys[i]); w > width {
+			width = w
+		}
+	}
+	order := make([]int, len(lines))
+	for i := range order {
+		order[i] = i
+	}
+	if opts.Sort {
+		sort.SliceStable(order, func(a int, b int) bool {
+			return NaturalLess(strings.TrimSpace(keys[order[a]]), strings.TrimSpace(keys[order[b]]))
+		})
+	}
+	var buf bytes.Buffer
+	for _, i := range order {
+		if !found[i] {
+			buf.WriteString(lines[i])
+			buf.WriteByte('\n')
+			continue
+		}
+		pad := strings.Repeat(" ", width-DisplayWidth(keys[i]))
+		line := keys[i] + pad + opts.Before + sep + opts.After + values[i]
+		if opts.PadAfter {
+			line = keys[i] + opts.Before + sep + opts.After + pad + values[i]
+		}
+		if values[i] == "" {
+			line = strings.TrimRight(line, " ")
+		}
+		buf.WriteString(line)
+		buf.WriteByte('\n')
+	}
+	out := buf.String()
+	if !strings.HasSuffix(text, "\n") {
+		out = strings.TrimSuffix(out, "\n")
+	}
+	return out
+}
+
+// ListItem is a single item for List to render.
+type ListItem struct {
+	Text     string
+	Children []*ListItem
+}
+
+// ListStyle selects how List marks the items at a level.
+type ListStyle int
+
+const (
+	// ListBullet marks items with the level's entry from
+	// ListOptions.Bullets.
+	ListBullet ListStyle = iota
+	// ListNumber marks items with "1.", "2.", etc.
+	ListNumber
+	// ListLowerLetter marks items with "a.", "b.", etc.
+	ListLowerLetter
+	// ListUpperLetter marks items with "A.", "B.", etc.
+	ListUpperLetter
+)
+
+// ListOptions controls the output of List.
+type ListOptions struct {
+	// Width is the width to wrap to, with the same meaning as the width given
+	// to Wrap.
+	Width int
+	// Styles give the ListStyle for each level of nesting; levels beyond
+	// the end use the last style given. If nil, ListBullet is used.
+	Styles []ListStyle
+	// Bullets give the bullet for each level of nesting when the ListBullet
+	// style is in use; levels beyond the end cycle through them again.
+	Bullets []string
+	// Indent prefixes the top level items.
+	Indent string
+}
+
+// NewListOptions gives:
+//
+//	&ListOptions{Bullets: []string{"*", "-", "+"}}
+func NewListOptions() *ListOptions {
+	return &ListOptions{Bullets: []string{"*", "-", "+"}}
+}
+
+// List formats the items as a list, wrapped to the width with hanging
+// indents, and with nested items indented to line up with the text of their
+// parent. For example:
+//
+//	opts := brimtext.NewListOptions()
+//	opts.Styles = []brimtext.ListStyle{brimtext.ListNumber, brimtext.ListBullet}
+//	fmt.Print(brimtext.List(items, opts))
+//
+//	 1. First item.
+//	 2. Second item, which is long enough that
+//	    it wraps onto a second line.
+//	    * A nested item.
+//	    * Another nested item.
+//	...
+//	10. Numbers are padded to line up.
+//
+// If opts is nil, NewListOptions is used.
+func List(items []*ListItem, opts *ListOptions) string {
+	if opts == nil {
+		opts = NewListOptions()
+	}
+	width := opts.Width
+	if width < 1 {
+		width = GetTTYWidth() - 1 + width
+	}
+	var buf bytes.Buffer
+	listItems(&buf, items, 0, opts.Indent, width, opts)
+	return buf.String()
+}
+
+func listItems(buf *bytes.Buffer, items []*ListItem, level int, indent string, width int, opts *ListOptions) {
+	style := ListBullet
+	if len(opts.Styles) > 0 {
+		if level < len(opts.Styles) {
+			style = opts.Styles[level]
+		} else {
+			style = opts.Styles[len(opts.Styles)-1]
+		}
+	}
+	markers := make([]string, len(items))
+	markerWidth := 0
+	for i := range items {
+		switch style {
+		case ListNumber:
+			markers[i] = strconv.Itoa(i+1) + "."
+		case ListLowerLetter:
+			markers[i] = listLetters(i) + "."
+		case ListUpperLetter:
+			markers[i] = strings.ToUpper(listLetters(i)) + "."
+		default:
+			markers[i] = "*"
+			if len(opts.Bullets) > 0 {
+				markers[i] = opts.Bullets[level%len(opts.Bullets)]
+			}
+		}
+		if w := DisplayWidth(markers[i]); w > markerWidth {
+			markerWidth = w
+		}
+	}
+	for i, item := range items {
+		if item == nil {
+			continue
+		}
+		first := indent + strings.Repeat(" ", markerWidth-DisplayWidth(markers[i])) + markers[i] + " "
+		hanging := strings.Repeat(" ", DisplayWidth(first))
+		if strings.TrimSpace(item.Text) == "" {
+			buf.WriteString(strings.TrimRight(first, " "))
+		} else {
+			buf.WriteString(wrapParagraphs(item.Text, width, first, hanging))
+		}
+		buf.WriteByte('\n')
+		listItems(buf, item.Children, level+1, hanging, width, opts)
+	}
+}
+
+// listLetters returns "a" for 0, "b" for 1, ..., "z" for 25, "aa" for 26,
+// etc.
+func listLetters(i int) string {
+	s := ""
+	for i >= 0 {
+		s = string(rune('a'+i%26)) + s
+		i = i/26 - 1
+	}
+	return s
+}
@@ -0,0 +1,154 @@
+package brimtext
+
+import (
+	"testing"
+)
+
+func TestDefinitionList(t *testing.T) {
+	opts := NewDefinitionListOptions()
+	opts.Width = 50
+	out := DefinitionList([][2]string{
+		{"-h, --help", "Show this help and exit."},
+		{"-v, --verbose", "Output more information about what is being done, which can be quite a lot."},
+		{"--a-very-long-flag-name-here", "Starts on the next line."},
+		{"--quiet", ""},
+		{"--paragraphs", "First.\n\nSecond."},
+	}, opts)
+	exp := `  -h, --help     Show this help and exit.
+  -v, --verbose  Output more information about
+                 what is being done, which can be
+                 quite a lot.
+  --a-very-long-flag-name-here
+                 Starts on the next line.
+  --quiet
+  --paragraphs   First.
+
+                 Second.
+`
+	if out != exp {
+		t.Errorf("DefinitionList %#v != %#v", out, exp)
+	}
+	out = DefinitionList([][2]string{{"-x", "\n\nAfter a blank paragraph."}}, opts)
+	exp = "  -x  After a blank paragraph.\n"
+	if out != exp {
+		t.Errorf("DefinitionList blank %#v != %#v", out, exp)
+	}
+	if out = DefinitionList(nil, nil); out != "" {
+		t.Errorf("DefinitionList(nil) %#v", out)
+	}
+}
+
+func TestKeyValue(t *testing.T) {
+	opts := NewKeyValueOptions()
+	opts.Width = 40
+	out := KeyValue([][2]string{
+		{"Name", "web-1"},
+		{"Namespace", "default"},
+		{"Containers", ""},
+		{"  nginx", ""},
+		{"    Image", "nginx:1.19"},
+		{"    Port", "80/TCP"},
+		{"  sidecar", ""},
+		{"    Image", "envoy"},
+		{"Description", "A fairly long value that will need to be wrapped."},
+	}, opts)
+	exp := `Name:         web-1
+Namespace:    default
+Containers:
+  nginx:
+    Image:  nginx:1.19
+    Port:   80/TCP
+  sidecar:
+    Image:  envoy
+Description:  A fairly long value that
+              will need to be wrapped.
+`
+	if out != exp {
+		t.Errorf("KeyValue %#v != %#v", out, exp)
+	}
+	if out = KeyValue(nil, nil); out != "" {
+		t.Errorf("KeyValue(nil) %#v", out)
+	}
+}
+
+func TestAlignOnSeparator(t *testing.T) {
+	in := "timeout=30s\nmax_connections = 100\n# comment\nname=\n"
+	out := AlignOnSeparator(in, nil)
+	exp := "timeout         = 30s\n" +
+		"max_connections = 100\n" +
+		"# comment\n" +
+		"name            =\n"
+	if out != exp {
+		t.Errorf("AlignOnSeparator %#v != %#v", out, exp)
+	}
+	out = AlignOnSeparator("b: 2\nalpha: 1\nb10: x: y", &SeparatorOptions{Separator: ":", After: " ", PadAfter: true, Sort: true})
+	exp = "alpha: 1\n" +
+		"b:     2\n" +
+		"b10:   x: y"
+	if out != exp {
+		t.Errorf("AlignOnSeparator sorted %#v != %#v", out, exp)
+	}
+	out = AlignOnSeparator("a=1\nbb=2", &SeparatorOptions{})
+	if exp = "a =1\nbb=2"; out != exp {
+		t.Errorf("AlignOnSeparator compact %#v != %#v", out, exp)
+	}
+	if out = AlignOnSeparator("", nil); out != "" {
+		t.Errorf("AlignOnSeparator empty %#v", out)
+	}
+}
+
+func TestList(t *testing.T) {
+	items := []*ListItem{
+		{Text: "First item."},
+		{Text: "Second item, which is long enough that it wraps.", Children: []*ListItem{
+			{Text: "A nested item."},
+			{Text: "Another nested item.", Children: []*ListItem{{Text: "Deeper."}}},
+		}},
+	}
+	opts := NewListOptions()
+	opts.Width = 30
+	out := List(items, opts)
+	exp := `* First item.
+* Second item, which is long
+  enough that it wraps.
+  - A nested item.
+  - Another nested item.
+    + Deeper.
+`
+	if out != exp {
+		t.Errorf("List bullets %#v != %#v", out, exp)
+	}
+	opts.Styles = []ListStyle{ListNumber, ListLowerLetter, ListUpperLetter}
+	opts.Indent = "  "
+	out = List(items, opts)
+	exp = `  1. First item.
+  2. Second item, which is
+     long enough that it
+     wraps.
+     a. A nested item.
+     b. Another nested item.
+        A. Deeper.
+`
+	if out != exp {
+		t.Errorf("List numbered %#v != %#v", out, exp)
+	}
+	var many []*ListItem
+	for i := 0; i < 10; i++ {
+		many = append(many, &ListItem{Text: "x"})
+	}
+	opts = NewListOptions()
+	opts.Styles = []ListStyle{ListNumber}
+	out = List(many, opts)
+	exp = " 1. x\n 2. x\n 3. x\n 4. x\n 5. x\n 6. x\n 7. x\n 8. x\n 9. x\n10. x\n"
+	if out != exp {
+		t.Errorf("List padded numbers %#v != %#v", out, exp)
+	}
+}
+
+func TestListLetters(t *testing.T) {
+	for in, exp := range map[int]string{0: "a", 1: "b", 25: "z", 26: "aa", 27: "ab", 51: "az", 52: "ba", 701: "zz", 702: "aaa"} {
+		if out := listLetters(in); out != exp {
+			t.Errorf("listLetters(%d) %#v != %#v", in, out, exp)
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package brimtext
+
+import (
+	"strings"
+)
+
+// Mask returns s with all but the last visibleSuffix runes replaced by
+// maskRune, such as Mask("4111111111111111", 4, '*') giving
+// "************1111", for safely printing tokens and card numbers. The
+// result has the same display width as s; if visibleSuffix would reveal half
+// or more of s, less is shown. If maskRune is 0, '*' is used.
+func Mask(s string, visibleSuffix int, maskRune rune) string {
+	return MaskPrefixSuffix(s, 0, visibleSuffix, maskRune)
+}
+
+// MaskPrefixSuffix is like Mask but also keeps the first visiblePrefix runes,
+// such as MaskPrefixSuffix("sk_live_abcdef123456", 3, 4, '*') giving
+// "sk_*************3456".
+func MaskPrefixSuffix(s string, visiblePrefix int, visibleSuffix int, maskRune rune) string {
+	if maskRune == 0 {
+		maskRune = '*'
+	}
+	rs := []rune(s)
+	prefix, suffix := maskVisible(len(rs), visiblePrefix, visibleSuffix)
+	maskWidth := RuneWidth(maskRune)
+	if maskWidth < 1 {
+		maskWidth = 1
+	}
+	mask := strings.Repeat(string(maskRune), (DisplayWidth(string(rs[prefix:len(rs)-suffix]))+maskWidth-1)/maskWidth)
+	return string(rs[:prefix]) + mask + string(rs[len(rs)-suffix:])
+}
+
+// MaskFixed is like MaskPrefixSuffix but always uses width mask runes,
+// hiding the length of s as well.
+func MaskFixed(s string, visiblePrefix int, visibleSuffix int, width int, maskRune rune) string {
+	if maskRune == 0 {
+		maskRune = '*'
+	}
+	if width < 0 {
+		width = 0
+	}
+	rs := []rune(s)
+	prefix, suffix := maskVisible(len(rs), visiblePrefix, visibleSuffix)
+	return string(rs[:prefix]) + strings.Repeat(string(maskRune), width) + string(rs[len(rs)-suffix:])
+}
+
+// maskVisible returns how many leading and trailing runes of n may be shown,
+// keeping more than half of them masked.
+func maskVisible(n int, prefix int, suffix int) (int, int) {
+	if prefix < 0 {
+		prefix = 0
+	}
+	if suffix < 0 {
+		suffix = 0
+	}
+	limit := (n - 1) / 2
+	for prefix+suffix > limit {
+		if suffix >= prefix && suffix > 0 {
+			suffix--
+		} else {
+			prefix--
+		}
+	}
+	return prefix, suffix
+}
+
+// applyRedaction returns the data with the cells after the header rows of
+// the columns selected by opts.Redact and opts.RedactHeaders masked.
+func applyRedaction(data [][]string, opts *AlignOptions, headerRows int) [][]string {
+	redact := map[int]bool{}
+	for _, col := range opts.Redact {
+		redact[col] = true
+	}
+	if len(opts.RedactHeaders) > 0 && headerRows > 0 {
+		for col, cell := range data[0] {
+			for _, header := range opts.RedactHeaders {
+				if strings.EqualFold(strings.TrimSpace(StripANSIEscapes(cell)), header) {
+					redact[col] = true
+				}
+			}
+		}
+	}
+	newData := make([][]string, len(data))
+	for r, row := range data {
+		if r < headerRows || row == nil {
+			newData[r] = row
+			continue
+		}
+		newRow := make([]string, len(row))
+		for c, cell := range row {
+			if redact[c] && cell != "" {
+				cell = redactCell(cell, opts.RedactText)
+			}
+			newRow[c] = cell
+		}
+		newData[r] = newRow
+	}
+	return newData
+}
+
+// redactCell returns text, or if text is empty the cell with each rune of
+// each of its lines masked.
+func redactCell(cell string, text string) string {
+	if text != "" {
+		return text
+	}
+	lines := strings.Split(ExpandTabs(StripANSIEscapes(cell), 8), "\n")
+	for i, line := range lines {
+		lines[i] = Mask(line, 0, '*')
+	}
+	return strings.Join(lines, "\n")
+}
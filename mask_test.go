@@ -0,0 +1,93 @@
+package brimtext
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMask(t *testing.T) {
+	for _, v := range []struct {
+		in      string
+		visible int
+		mask    rune
+		exp     string
+	}{
+		{"4111111111111111", 4, '*', "************1111"},
+		{"4111111111111111", 4, 0, "************1111"},
+		{"secret", 4, '#', "####et"},
+		{"abc", 4, '*', "**c"},
+		{"a", 4, '*', "*"},
+		{"", 4, '*', ""},
+		{"token", -1, '*', "*****"},
+		{"日本語のトークン", 2, '*', "************クン"},
+		{"abcdefgh", 2, '●', "●●●●●●gh"},
+	} {
+		out := Mask(v.in, v.visible, v.mask)
+		if out != v.exp {
+			t.Errorf("Mask(%#v, %d, %q) %#v != %#v", v.in, v.visible, v.mask, out, v.exp)
+		}
+	}
+}
+
+func TestMaskPrefixSuffix(t *testing.T) {
+	out := MaskPrefixSuffix("sk_live_abcdef123456", 3, 4, '*')
+	exp := "sk_*************3456"
+	if out != exp {
+		t.Errorf("MaskPrefixSuffix %#v != %#v", out, exp)
+	}
+	out = MaskPrefixSuffix("abcdef", 3, 3, '*')
+	exp = "a****f"
+	if out != exp {
+		t.Errorf("MaskPrefixSuffix limited %#v != %#v", out, exp)
+	}
+	out = MaskPrefixSuffix("secretvalue", 2, 2, '\u200b')
+	exp = "se" + strings.Repeat("\u200b", 7) + "ue"
+	if out != exp {
+		t.Errorf("MaskPrefixSuffix zero width %#v != %#v", out, exp)
+	}
+}
+
+func TestMaskFixed(t *testing.T) {
+	out := MaskFixed("sk_live_abcdef123456", 3, 4, 6, '*')
+	exp := "sk_******3456"
+	if out != exp {
+		t.Errorf("MaskFixed %#v != %#v", out, exp)
+	}
+	out = MaskFixed("ab", 0, 4, 8, '*')
+	exp = "********"
+	if out != exp {
+		t.Errorf("MaskFixed short %#v != %#v", out, exp)
+	}
+}
+
+func TestAlignRedact(t *testing.T) {
+	data := [][]string{
+		{"User", "Token", "Email"},
+		nil,
+		{"bob", "abc123", "bob@example.com"},
+		{"sue", "\x1b[1mxyz\x1b[0m", ""},
+	}
+	opts := NewDefaultAlignOptions()
+	opts.Redact = []int{1}
+	opts.RedactHeaders = []string{"email"}
+	out := Align(data, opts)
+	exp := "User Token  Email\n" +
+		"\n" +
+		"bob  ****** ***************\n" +
+		"sue  ***    \n"
+	if out != exp {
+		t.Errorf("%#v != %#v", out, exp)
+	}
+	opts.RedactText = "∗∗∗"
+	out = Align(data, opts)
+	exp = "User Token Email\n" +
+		"\n" +
+		"bob  ∗∗∗   ∗∗∗\n" +
+		"sue  ∗∗∗   \n"
+	if out != exp {
+		t.Errorf("%#v != %#v", out, exp)
+	}
+	if data[2][1] != "abc123" {
+		t.Errorf("Align changed the data: %#v", data[2])
+	}
+}
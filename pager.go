@@ -0,0 +1,99 @@
+package brimtext
+
+import (
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+
+	"golang.org/x/crypto/ssh/terminal"
+)
+
+// PageOptions controls the finer points of PageWithOptions.
+type PageOptions struct {
+	// Out is where the text is shown; if nil, os.Stdout is used.
+	Out *os.File
+	// Pager is the command line of the pager, such as "less -S"; if empty,
+	// $PAGER is used, or "less" if that is also empty.
+	Pager string
+	// Always pages the text even if it would fit on the terminal.
+	Always bool
+}
+
+// Page writes the text to os.Stdout, through $PAGER if it is a terminal and
+// the text is too long to fit on it, giving the paging behavior of tools like
+// "git log" with one call.
+func Page(text string) error {
+	return PageWithOptions(text, nil)
+}
+
+// PageWithOptions is like Page but with the output and pager controlled by
+// opts; see PageOptions. If opts is nil the behavior is the same as Page.
+//
+// The text is written directly instead when Out isn't a terminal, when the
+// text fits within the terminal's height, or when the pager can't be
+// started. As git does, LESS is set to "FRX" if unset, so less passes ANSI
+// escape codes through and quits at once for text that fits.
+func PageWithOptions(text string, opts *PageOptions) error {
+	if opts == nil {
+		opts = &PageOptions{}
+	}
+	out := opts.Out
+	if out == nil {
+		out = os.Stdout
+	}
+	fd := int(out.Fd())
+	if !terminal.IsTerminal(fd) {
+		return pageDirect(out, text)
+	}
+	if !opts.Always {
+		if width, height, err := terminal.GetSize(fd); err == nil && pageLines(text, width) < height {
+			return pageDirect(out, text)
+		}
+	}
+	pager := opts.Pager
+	if pager == "" {
+		pager = os.Getenv("PAGER")
+	}
+	if pager == "" {
+		pager = "less"
+	}
+	args := strings.Fields(pager)
+	if len(args) == 0 {
+		return pageDirect(out, text)
+	}
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Stdin = strings.NewReader(text)
+	cmd.Stdout = out
+	cmd.Stderr = os.Stderr
+	if os.Getenv("LESS") == "" {
+		cmd.Env = append(os.Environ(), "LESS=FRX")
+	}
+	if err := cmd.Start(); err != nil {
+		return pageDirect(out, text)
+	}
+	return cmd.Wait()
+}
+
+func pageDirect(out io.Writer, text string) error {
+	_, err := io.WriteString(out, text)
+	return err
+}
+
+// pageLines returns how many terminal lines the text takes up when lines
+// wider than width wrap.
+func pageLines(text string, width int) int {
+	if text == "" {
+		return 0
+	}
+	text = strings.TrimSuffix(text, "\n")
+	lines := 0
+	for _, line := range strings.Split(text, "\n") {
+		n := 1
+		if w := DisplayWidth(ExpandTabs(line, 8)); width > 0 && w > width {
+			n = (w + width - 1) / width
+		}
+		lines += n
+	}
+	return lines
+}
@@ -0,0 +1,52 @@
+package brimtext
+
+import (
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestPageLines(t *testing.T) {
+	for _, v := range []struct {
+		in    string
+		width int
+		exp   int
+	}{
+		{"", 80, 0},
+		{"\n", 80, 1},
+		{"one", 80, 1},
+		{"one\ntwo\n", 80, 2},
+		{"one\n\ntwo", 80, 3},
+		{strings.Repeat("x", 10), 4, 3},
+		{strings.Repeat("x", 8), 4, 2},
+		{"\x1b[31m" + strings.Repeat("x", 4) + "\x1b[0m", 4, 1},
+		{"日本語", 4, 2},
+		{"\tx", 4, 3},
+		{"abc", 0, 1},
+	} {
+		if out := pageLines(v.in, v.width); out != v.exp {
+			t.Errorf("pageLines(%#v, %d) %d != %d", v.in, v.width, out, v.exp)
+		}
+	}
+}
+
+func TestPageWithOptionsNotTerminal(t *testing.T) {
+	f, err := ioutil.TempFile("", "brimtext")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+	defer f.Close()
+	text := strings.Repeat("line\n", 1000)
+	if err := PageWithOptions(text, &PageOptions{Out: f, Pager: "false", Always: true}); err != nil {
+		t.Fatal(err)
+	}
+	b, err := ioutil.ReadFile(f.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != text {
+		t.Errorf("wrote %d bytes rather than %d", len(b), len(text))
+	}
+}
@@ -0,0 +1,144 @@
+package brimtext
+
+import (
+	"strings"
+)
+
+// ShortenPath abbreviates the path to fit within width display columns, for
+// path columns in tables. It keeps the first directory and the file name,
+// collapsing directories from the middle into "…" until it fits, such as
+// "/a/…/z/file.go"; if that's not enough, the first directory is shortened
+// to its first letter, then dropped, and finally the file name itself is
+// shortened from the front. Paths that already fit are returned as is.
+func ShortenPath(path string, width int) string {
+	if width < 1 {
+		return ""
+	}
+	if DisplayWidth(path) <= width {
+		return path
+	}
+	parts := strings.Split(path, "/")
+	file := parts[len(parts)-1]
+	dirs := parts[:len(parts)-1]
+	head := 1
+	if len(dirs) > 1 && dirs[0] == "" {
+		head = 2
+	}
+	if head > len(dirs) {
+		head = len(dirs)
+	}
+	fits := func(candidate []string) (string, bool) {
+		s := strings.Join(candidate, "/")
+		return s, DisplayWidth(s) <= width
+	}
+	join := func(a []string, b ...string) []string {
+		return append(append([]string(nil), a...), b...)
+	}
+	for drop := 1; drop <= len(dirs)-head; drop++ {
+		candidate := join(dirs[:head], "…")
+		candidate = join(candidate, dirs[head+drop:]...)
+		if s, ok := fits(join(candidate, file)); ok {
+			return s
+		}
+	}
+	if head > 0 && dirs[head-1] != "" {
+		abbreviated := join(dirs[:head])
+		abbreviated[head-1] = pathAbbreviate(abbreviated[head-1])
+		if s, ok := fits(join(abbreviated, "…", file)); ok {
+			return s
+		}
+	}
+	if len(dirs) > 0 {
+		if s, ok := fits([]string{"…", file}); ok {
+			return s
+		}
+	}
+	return ellipsizeLeft(file, width)
+}
+
+// pathAbbreviate returns the first letter of the directory name, keeping a
+// leading dot for hidden directories.
+func pathAbbreviate(dir string) string {
+	rs := []rune(dir)
+	if len(rs) > 1 && rs[0] == '.' {
+		return string(rs[:2])
+	}
+	if len(rs) > 0 {
+		return string(rs[:1])
+	}
+	return dir
+}
+
+// ellipsizeLeft keeps as much of the end of s as fits within width display
+// columns after a leading "…".
+func ellipsizeLeft(s string, width int) string {
+	if DisplayWidth(s) <= width {
+		return s
+	}
+	rs := []rune(s)
+	used := 1
+	i := len(rs)
+	for i > 0 && used+RuneWidth(rs[i-1]) <= width {
+		i--
+		used += RuneWidth(rs[i])
+	}
+	return "…" + string(rs[i:])
+}
+
+// AbbreviateMiddle shortens s to fit within width display columns by
+// keeping its start and end joined by sep, such as
+// AbbreviateMiddle("4def268fd1a4e0b2c6e5c4ad861ebc1b", 11, "") giving
+// "4def2…ebc1b", for fixed width ID columns of SHAs, UUIDs, ARNs, and the
+// like. Any odd column goes to the start. If sep is "", "…" is used; if
+// width is too narrow for sep, sep is cut to fit.
+func AbbreviateMiddle(s string, width int, sep string) string {
+	if sep == "" {
+		sep = "…"
+	}
+	room := width - DisplayWidth(sep)
+	if room < 0 {
+		if DisplayWidth(s) <= width {
+			return s
+		}
+		rs := []rune(sep)
+		i, used := 0, 0
+		for i < len(rs) && used+RuneWidth(rs[i]) <= width {
+			used += RuneWidth(rs[i])
+			i++
+		}
+		return string(rs[:i])
+	}
+	return AbbreviateHeadTail(s, room-room/2, room/2, sep)
+}
+
+// AbbreviateHeadTail is like AbbreviateMiddle but keeps head display columns
+// from the start of s and tail from the end, such as
+// AbbreviateHeadTail("arn:aws:iam::123456789012:role/deploy", 8, 6, "...")
+// giving "arn:aws:...deploy". The string is returned as is if it is no wider
+// than the abbreviation would be.
+func AbbreviateHeadTail(s string, head int, tail int, sep string) string {
+	if sep == "" {
+		sep = "…"
+	}
+	if head < 0 {
+		head = 0
+	}
+	if tail < 0 {
+		tail = 0
+	}
+	if DisplayWidth(s) <= head+DisplayWidth(sep)+tail {
+		return s
+	}
+	rs := []rune(s)
+	h, used := 0, 0
+	for h < len(rs) && used+RuneWidth(rs[h]) <= head {
+		used += RuneWidth(rs[h])
+		h++
+	}
+	t, used := len(rs), 0
+	for t > h && used+RuneWidth(rs[t-1]) <= tail {
+		t--
+		used += RuneWidth(rs[t])
+	}
+	return string(rs[:h]) + sep + string(rs[t:])
+}
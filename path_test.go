@@ -0,0 +1,79 @@
+package brimtext
+
+import (
+	"testing"
+)
+
+func TestShortenPath(t *testing.T) {
+	for _, v := range []struct {
+		in    string
+		width int
+		exp   string
+	}{
+		{"/a/b/c/file.go", 20, "/a/b/c/file.go"},
+		{"/a/b/c/d/z/file.go", 16, "/a/…/d/z/file.go"},
+		{"/a/b/c/d/z/file.go", 14, "/a/…/z/file.go"},
+		{"/a/b/c/d/z/file.go", 12, "/a/…/file.go"},
+		{"/home/gholt/src/brimtext/align.go", 22, "/home/…/align.go"},
+		{"/home/gholt/src/brimtext/align.go", 14, "/h/…/align.go"},
+		{"/home/gholt/src/brimtext/align.go", 11, "…/align.go"},
+		{"/home/gholt/src/brimtext/align.go", 6, "…gn.go"},
+		{"src/brimtext/align.go", 16, "src/…/align.go"},
+		{".config/brimtext/settings.json", 22, ".c/…/settings.json"},
+		{"averyveryverylongname.go", 10, "…ngname.go"},
+		{"/日本/語/ファイル", 16, "/日本/…/ファイル"},
+		{"/日本/語/ファイル", 13, "…/ファイル"},
+		{"/a/b", 0, ""},
+	} {
+		out := ShortenPath(v.in, v.width)
+		if out != v.exp {
+			t.Errorf("ShortenPath(%#v, %d) %#v != %#v", v.in, v.width, out, v.exp)
+		}
+		if DisplayWidth(out) > v.width {
+			t.Errorf("ShortenPath(%#v, %d) %#v too wide", v.in, v.width, out)
+		}
+	}
+}
+
+func TestAbbreviateMiddle(t *testing.T) {
+	for _, v := range []struct {
+		in    string
+		width int
+		sep   string
+		exp   string
+	}{
+		{"4def268fd1a4e0b2c6e5c4ad861ebc1b", 11, "", "4def2…ebc1b"},
+		{"4def268fd1a4e0b2c6e5c4ad861ebc1b", 12, "", "4def26…ebc1b"},
+		{"4def268fd1a4e0b2c6e5c4ad861ebc1b", 10, "..", "4def..bc1b"},
+		{"123e4567-e89b-12d3-a456-426614174000", 40, "", "123e4567-e89b-12d3-a456-426614174000"},
+		{"short", 5, "", "short"},
+		{"日本語のトークン", 9, "", "日本…クン"},
+		{"abcdef", 1, "", "…"},
+		{"abcdef", 0, "", ""},
+		{"abcdef", 2, "...", ".."},
+		{"ab", 2, "...", "ab"},
+	} {
+		out := AbbreviateMiddle(v.in, v.width, v.sep)
+		if out != v.exp {
+			t.Errorf("AbbreviateMiddle(%#v, %d, %#v) %#v != %#v", v.in, v.width, v.sep, out, v.exp)
+		}
+	}
+}
+
+func TestAbbreviateHeadTail(t *testing.T) {
+	out := AbbreviateHeadTail("arn:aws:iam::123456789012:role/deploy", 8, 6, "...")
+	exp := "arn:aws:...deploy"
+	if out != exp {
+		t.Errorf("AbbreviateHeadTail %#v != %#v", out, exp)
+	}
+	out = AbbreviateHeadTail("abcdefgh", 0, 3, "")
+	exp = "…fgh"
+	if out != exp {
+		t.Errorf("AbbreviateHeadTail no head %#v != %#v", out, exp)
+	}
+	out = AbbreviateHeadTail("abcde", 2, 2, "")
+	exp = "abcde"
+	if out != exp {
+		t.Errorf("AbbreviateHeadTail fits %#v != %#v", out, exp)
+	}
+}
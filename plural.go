@@ -0,0 +1,224 @@
+package brimtext
+
+import (
+	"strings"
+
+	cldr "golang.org/x/text/feature/plural"
+	"golang.org/x/text/language"
+)
+
+// Plural returns singular if count is 1 and plural otherwise; if plural is
+// empty, singular+"s" is used. For example:
+//
+//	fmt.Printf("%d %s", n, brimtext.Plural(n, "file", ""))
+//	fmt.Printf("%d %s", n, brimtext.Plural(n, "index", "indices"))
+func Plural(count int, singular string, plural string) string {
+	if count == 1 || count == -1 {
+		return singular
+	}
+	if plural == "" {
+		return singular + "s"
+	}
+	return plural
+}
+
+// pluralIrregulars are English nouns that don't follow the usual rules.
+var pluralIrregulars = map[string]string{
+	"child":  "children",
+	"foot":   "feet",
+	"goose":  "geese",
+	"index":  "indices",
+	"man":    "men",
+	"matrix": "matrices",
+	"mouse":  "mice",
+	"ox":     "oxen",
+	"person": "people",
+	"tooth":  "teeth",
+	"vertex": "vertices",
+	"woman":  "women",
+	"axis":   "axes",
+	"crisis": "crises",
+	"datum":  "data",
+	"leaf":   "leaves",
+	"life":   "lives",
+	"knife":  "knives",
+	"wife":   "wives",
+	"half":   "halves",
+	"self":   "selves",
+	"shelf":  "shelves",
+	"wolf":   "wolves",
+	"hero":   "heroes",
+	"potato": "potatoes",
+	"quiz":   "quizzes",
+	"tomato": "tomatoes",
+	"echo":   "echoes",
+}
+
+// pluralUncountables are English nouns whose plural is the same as their
+// singular.
+var pluralUncountables = map[string]bool{
+	"aircraft":    true,
+	"data":        true,
+	"deer":        true,
+	"equipment":   true,
+	"fish":        true,
+	"information": true,
+	"metadata":    true,
+	"moose":       true,
+	"news":        true,
+	"series":      true,
+	"sheep":       true,
+	"species":     true,
+	"software":    true,
+}
+
+// Pluralize returns the English plural of the word given, such as "file"
+// giving "files", "box" giving "boxes", "policy" giving "policies", and
+// "person" giving "people". Common irregular and uncountable nouns are
+// recognized and the case of the word is preserved as best as it can be, so
+// "Person" gives "People" and "BOX" gives "BOXES". This is a heuristic, of
+// course; use Plural with an explicit plural for anything unusual.
+func Pluralize(word string) string {
+	if word == "" {
+		return word
+	}
+	lower := strings.ToLower(word)
+	var plural string
+	if pluralUncountables[lower] {
+		return word
+	} else if p, ok := pluralIrregulars[lower]; ok {
+		plural = p
+	} else {
+		plural = lower + "s"
+		n := len(lower)
+		switch {
+		case strings.HasSuffix(lower, "s") || strings.HasSuffix(lower, "x") || strings.HasSuffix(lower, "z") || strings.HasSuffix(lower, "ch") || strings.HasSuffix(lower, "sh"):
+			plural = lower + "es"
+		case n > 1 && lower[n-1] == 'y' && !strings.ContainsRune("aeiou", rune(lower[n-2])):
+			plural = lower[:n-1] + "ies"
+		}
+	}
+	if word == strings.ToUpper(word) && word != lower {
+		return strings.ToUpper(plural)
+	}
+	if strings.HasPrefix(plural, lower) {
+		return word + plural[len(lower):]
+	}
+	if word[:1] != lower[:1] {
+		return strings.ToUpper(plural[:1]) + plural[1:]
+	}
+	return plural
+}
+
+// PluralCategory is one of the CLDR plural categories a language sorts counts
+// into; see PluralCategoryOf.
+type PluralCategory int
+
+const (
+	PluralOther PluralCategory = iota
+	PluralZero
+	PluralOne
+	PluralTwo
+	PluralFew
+	PluralMany
+)
+
+func (c PluralCategory) String() string {
+	switch c {
+	case PluralZero:
+		return "zero"
+	case PluralOne:
+		return "one"
+	case PluralTwo:
+		return "two"
+	case PluralFew:
+		return "few"
+	case PluralMany:
+		return "many"
+	}
+	return "other"
+}
+
+// PluralCategoryOf returns the CLDR plural category of the count for the
+// language tag given, such as "en", "ru", or "ar"; an unrecognized tag falls
+// back to the root rules, where everything is PluralOther. For example, in
+// Russian 1 and 21 are PluralOne, 3 and 22 are PluralFew, and 5 and 11 are
+// PluralMany.
+func PluralCategoryOf(lang string, count int) PluralCategory {
+	if count < 0 {
+		count = -count
+	}
+	return PluralCategory(cldr.Cardinal.MatchPlural(language.Make(lang), count%10000000, 0, 0, 0, 0))
+}
+
+// PluralCategoryOfNumber is like PluralCategoryOf but for a formatted number,
+// such as "1.50" or "1,234", since some languages treat visible fraction
+// digits differently; in English "1" is PluralOne but "1.0" is PluralOther.
+// The fraction must follow a '.', and any other characters, like signs and
+// thousands separators, are ignored. A number without digits is PluralOther.
+func PluralCategoryOfNumber(lang string, number string) PluralCategory {
+	integer, fraction := number, ""
+	if i := strings.LastIndexByte(number, '.'); i >= 0 {
+		integer, fraction = number[:i], number[i+1:]
+	}
+	digits := false
+	operand := func(s string) (value int, count int) {
+		for _, r := range s {
+			if r >= '0' && r <= '9' {
+				value = (value*10 + int(r-'0')) % 10000000
+				count++
+				digits = true
+			}
+		}
+		return value, count
+	}
+	i, _ := operand(integer)
+	f, v := operand(fraction)
+	t, w := operand(strings.TrimRight(fraction, "0"))
+	if !digits {
+		return PluralOther
+	}
+	return PluralCategory(cldr.Cardinal.MatchPlural(language.Make(lang), i, v, w, f, t))
+}
+
+// PluralForms holds the text for each plural category of a message; see
+// PluralFor. Categories a language doesn't use, or that are left empty, fall
+// back to Other.
+type PluralForms struct {
+	Zero  string
+	One   string
+	Two   string
+	Few   string
+	Many  string
+	Other string
+}
+
+// Select returns the form for the category, or Other if that form is empty.
+func (f *PluralForms) Select(c PluralCategory) string {
+	var s string
+	switch c {
+	case PluralZero:
+		s = f.Zero
+	case PluralOne:
+		s = f.One
+	case PluralTwo:
+		s = f.Two
+	case PluralFew:
+		s = f.Few
+	case PluralMany:
+		s = f.Many
+	}
+	if s == "" {
+		return f.Other
+	}
+	return s
+}
+
+// PluralFor returns the form for the count in the language given, the
+// internationalized version of Plural. For example:
+//
+//	files := &brimtext.PluralForms{One: "файл", Few: "файла", Many: "файлов", Other: "файла"}
+//	fmt.Printf("%d %s", n, brimtext.PluralFor("ru", n, files))
+func PluralFor(lang string, count int, forms *PluralForms) string {
+	return forms.Select(PluralCategoryOf(lang, count))
+}
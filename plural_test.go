@@ -0,0 +1,120 @@
+package brimtext
+
+import (
+	"testing"
+)
+
+func TestPlural(t *testing.T) {
+	for _, v := range []struct {
+		count    int
+		singular string
+		plural   string
+		exp      string
+	}{
+		{0, "file", "", "files"},
+		{1, "file", "", "file"},
+		{2, "file", "", "files"},
+		{-1, "file", "", "file"},
+		{1, "index", "indices", "index"},
+		{3, "index", "indices", "indices"},
+	} {
+		out := Plural(v.count, v.singular, v.plural)
+		if out != v.exp {
+			t.Errorf("Plural(%d, %#v, %#v) %#v != %#v", v.count, v.singular, v.plural, out, v.exp)
+		}
+	}
+}
+
+func TestPluralize(t *testing.T) {
+	for in, exp := range map[string]string{
+		"":        "",
+		"file":    "files",
+		"box":     "boxes",
+		"bus":     "buses",
+		"match":   "matches",
+		"wish":    "wishes",
+		"policy":  "policies",
+		"key":     "keys",
+		"person":  "people",
+		"Person":  "People",
+		"child":   "children",
+		"sheep":   "sheep",
+		"BOX":     "BOXES",
+		"Policy":  "Policies",
+		"leaf":    "leaves",
+		"potato":  "potatoes",
+		"Series":  "Series",
+		"quiz":    "quizzes",
+		"journey": "journeys",
+	} {
+		out := Pluralize(in)
+		if out != exp {
+			t.Errorf("Pluralize(%#v) %#v != %#v", in, out, exp)
+		}
+	}
+}
+
+func TestPluralCategoryOf(t *testing.T) {
+	for _, v := range []struct {
+		lang  string
+		count int
+		exp   PluralCategory
+	}{
+		{"en", 0, PluralOther},
+		{"en", 1, PluralOne},
+		{"en", -1, PluralOne},
+		{"en", 2, PluralOther},
+		{"ru", 1, PluralOne},
+		{"ru", 21, PluralOne},
+		{"ru", 3, PluralFew},
+		{"ru", 22, PluralFew},
+		{"ru", 5, PluralMany},
+		{"ru", 11, PluralMany},
+		{"ar", 0, PluralZero},
+		{"ar", 1, PluralOne},
+		{"ar", 2, PluralTwo},
+		{"ar", 3, PluralFew},
+		{"ar", 11, PluralMany},
+		{"ar", 100, PluralOther},
+		{"ja", 1, PluralOther},
+		{"xx-unknown", 1, PluralOther},
+	} {
+		if out := PluralCategoryOf(v.lang, v.count); out != v.exp {
+			t.Errorf("PluralCategoryOf(%#v, %d) %s != %s", v.lang, v.count, out, v.exp)
+		}
+	}
+}
+
+func TestPluralCategoryOfNumber(t *testing.T) {
+	for _, v := range []struct {
+		lang   string
+		number string
+		exp    PluralCategory
+	}{
+		{"en", "1", PluralOne},
+		{"en", "1.0", PluralOther},
+		{"en", "1,001", PluralOther},
+		{"ru", "1,021", PluralOne},
+		{"ru", "1.5", PluralOther},
+		{"ru", "-3", PluralFew},
+		{"fr", "1.5", PluralOne},
+		{"en", "n/a", PluralOther},
+	} {
+		if out := PluralCategoryOfNumber(v.lang, v.number); out != v.exp {
+			t.Errorf("PluralCategoryOfNumber(%#v, %#v) %s != %s", v.lang, v.number, out, v.exp)
+		}
+	}
+}
+
+func TestPluralFor(t *testing.T) {
+	files := &PluralForms{One: "файл", Few: "файла", Many: "файлов", Other: "файла"}
+	for count, exp := range map[int]string{1: "файл", 2: "файла", 5: "файлов", 21: "файл"} {
+		if out := PluralFor("ru", count, files); out != exp {
+			t.Errorf("PluralFor(\"ru\", %d) %#v != %#v", count, out, exp)
+		}
+	}
+	days := &PluralForms{One: "day", Other: "days"}
+	if out := PluralFor("ar", 2, days); out != "days" {
+		t.Errorf("%#v != %#v", out, "days")
+	}
+}
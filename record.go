@@ -0,0 +1,162 @@
+package brimtext
+
+import (
+	"fmt"
+	"strings"
+)
+
+// RecordField describes one field of a fixed width record; see RecordLayout.
+type RecordField struct {
+	// Name identifies the field in errors.
+	Name string
+	// Offset is the position of the field's first rune in the record,
+	// starting at 0.
+	Offset int
+	// Width is how many runes the field takes up.
+	Width int
+	// Alignment places values narrower than Width; Left, Right, or Center.
+	Alignment Alignment
+	// Pad fills out values narrower than Width; if 0, ' ' is used. With '0'
+	// and Right alignment, a leading sign is kept before the zeros, so -12
+	// in 5 gives "-0012".
+	Pad rune
+}
+
+// RecordLayout formats rows of values into fixed width records, and parses
+// such records back into values, for legacy feeds and mainframe style
+// interfaces. For example:
+//
+//	layout := &brimtext.RecordLayout{Fields: []brimtext.RecordField{
+//		{Name: "id", Offset: 0, Width: 5, Alignment: brimtext.Right, Pad: '0'},
+//		{Name: "name", Offset: 5, Width: 10},
+//		{Name: "amount", Offset: 15, Width: 8, Alignment: brimtext.Right},
+//	}}
+//	line, err := layout.Format([]string{"42", "Bob", "12.50"})
+//
+// Gives the line "00042Bob          12.50", which layout.Parse turns back into
+// "42", "Bob", and "12.50".
+type RecordLayout struct {
+	Fields []RecordField
+	// Truncate cuts off values too wide for their fields rather than
+	// returning an error.
+	Truncate bool
+}
+
+// Width returns the width of the records, the end of the field that ends
+// furthest in.
+func (l *RecordLayout) Width() int {
+	width := 0
+	for _, f := range l.Fields {
+		if f.Offset+f.Width > width {
+			width = f.Offset + f.Width
+		}
+	}
+	return width
+}
+
+// Format returns the record for the values, one for each field in order;
+// missing values are blank. Parts of the record not covered by any field are
+// spaces.
+func (l *RecordLayout) Format(values []string) (string, error) {
+	record := []rune(strings.Repeat(" ", l.Width()))
+	for i, f := range l.Fields {
+		value := ""
+		if i < len(values) {
+			value = values[i]
+		}
+		rs := []rune(value)
+		if len(rs) > f.Width {
+			if !l.Truncate {
+				return "", fmt.Errorf("brimtext: value %q for field %q is wider than %d", value, f.Name, f.Width)
+			}
+			rs = rs[:f.Width]
+		}
+		copy(record[f.Offset:], recordPad(rs, f))
+	}
+	return string(record), nil
+}
+
+// recordPad pads the runes of the value out to the width of the field.
+func recordPad(rs []rune, f RecordField) []rune {
+	pad := f.Pad
+	if pad == 0 {
+		pad = ' '
+	}
+	n := f.Width - len(rs)
+	out := make([]rune, 0, f.Width)
+	switch f.Alignment {
+	case Right:
+		if pad == '0' && len(rs) > 0 && (rs[0] == '-' || rs[0] == '+') {
+			out = append(out, rs[0])
+			rs = rs[1:]
+		}
+		for ; n > 0; n-- {
+			out = append(out, pad)
+		}
+		out = append(out, rs...)
+	case Center:
+		for i := n / 2; i > 0; i-- {
+			out = append(out, pad)
+		}
+		out = append(out, rs...)
+		for i := n - n/2; i > 0; i-- {
+			out = append(out, pad)
+		}
+	default:
+		out = append(out, rs...)
+		for ; n > 0; n-- {
+			out = append(out, pad)
+		}
+	}
+	return out
+}
+
+// Parse returns the values of the fields of the record, with their padding
+// removed. A record shorter than Width is treated as though padded with
+// spaces, as when trailing whitespace was trimmed, but an error is returned
+// if the record is longer than Width.
+func (l *RecordLayout) Parse(record string) ([]string, error) {
+	rs := []rune(strings.TrimRight(record, "\r\n"))
+	if len(rs) > l.Width() {
+		return nil, fmt.Errorf("brimtext: record is %d wide, more than %d", len(rs), l.Width())
+	}
+	values := make([]string, len(l.Fields))
+	for i, f := range l.Fields {
+		start, end := f.Offset, f.Offset+f.Width
+		if start > len(rs) {
+			start = len(rs)
+		}
+		if end > len(rs) {
+			end = len(rs)
+		}
+		values[i] = recordTrim(string(rs[start:end]), f)
+	}
+	return values, nil
+}
+
+// recordTrim removes the padding recordPad would have added.
+func recordTrim(value string, f RecordField) string {
+	pad := string(f.Pad)
+	if f.Pad == 0 {
+		pad = " "
+	}
+	switch f.Alignment {
+	case Right:
+		if f.Pad == '0' {
+			value = strings.TrimRight(value, " ")
+			sign := ""
+			if value != "" && (value[0] == '-' || value[0] == '+') {
+				sign, value = value[:1], value[1:]
+			}
+			trimmed := strings.TrimLeft(value, pad)
+			if trimmed == "" && value != "" {
+				trimmed = "0"
+			}
+			return sign + trimmed
+		}
+		return strings.TrimLeft(strings.TrimRight(value, " "), pad)
+	case Center:
+		return strings.Trim(value, pad)
+	}
+	return strings.TrimRight(value, pad)
+}
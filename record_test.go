@@ -0,0 +1,70 @@
+package brimtext
+
+import (
+	"reflect"
+	"testing"
+)
+
+func testRecordLayout() *RecordLayout {
+	return &RecordLayout{Fields: []RecordField{
+		{Name: "id", Offset: 0, Width: 5, Alignment: Right, Pad: '0'},
+		{Name: "name", Offset: 5, Width: 10},
+		{Name: "amount", Offset: 15, Width: 8, Alignment: Right},
+		{Name: "code", Offset: 24, Width: 4, Alignment: Center, Pad: '_'},
+	}}
+}
+
+func TestRecordLayout(t *testing.T) {
+	layout := testRecordLayout()
+	if w := layout.Width(); w != 28 {
+		t.Errorf("Width %d", w)
+	}
+	for _, v := range []struct {
+		values []string
+		record string
+		parsed []string
+	}{
+		{[]string{"42", "Bob", "12.50", "x"}, "00042Bob          12.50 _x__", nil},
+		{[]string{"-7", "Sue", "", "ab"}, "-0007Sue                _ab_", []string{"-7", "Sue", "", "ab"}},
+		{[]string{"0", "日本", "1"}, "00000日本               1 ____", []string{"0", "日本", "1", ""}},
+		{nil, "00000                   ____", []string{"0", "", "", ""}},
+	} {
+		record, err := layout.Format(v.values)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if record != v.record {
+			t.Errorf("Format(%#v) %#v != %#v", v.values, record, v.record)
+		}
+		parsed, err := layout.Parse(record)
+		if err != nil {
+			t.Fatal(err)
+		}
+		exp := v.parsed
+		if exp == nil {
+			exp = v.values
+		}
+		if !reflect.DeepEqual(parsed, exp) {
+			t.Errorf("Parse(%#v) %#v != %#v", record, parsed, exp)
+		}
+	}
+}
+
+func TestRecordLayoutErrors(t *testing.T) {
+	layout := testRecordLayout()
+	if _, err := layout.Format([]string{"123456"}); err == nil {
+		t.Errorf("Format of too wide value gave no error")
+	}
+	layout.Truncate = true
+	record, err := layout.Format([]string{"123456", "a name that is too long"})
+	if err != nil || record != "12345a name tha         ____" {
+		t.Errorf("Format truncated %#v %v", record, err)
+	}
+	if _, err := layout.Parse(record + "x"); err == nil {
+		t.Errorf("Parse of too long record gave no error")
+	}
+	parsed, err := layout.Parse("00042Bob\n")
+	if err != nil || !reflect.DeepEqual(parsed, []string{"42", "Bob", "", ""}) {
+		t.Errorf("Parse of short record %#v %v", parsed, err)
+	}
+}
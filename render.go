@@ -0,0 +1,100 @@
+package brimtext
+
+import (
+	"strings"
+)
+
+// Renderer is implemented by the components that can be drawn to fit a given
+// width, so they can be placed into the layouts of TUI frameworks, and into
+// each other, without reworking their output.
+type Renderer interface {
+	// Render returns the component drawn to fit within width display
+	// columns; a width < 1 gives the component at its natural size.
+	Render(width int) string
+}
+
+// Sizable is implemented by components that can report their natural size,
+// such as for a layout to divide up the space it has.
+type Sizable interface {
+	// Size returns the display width and height in lines of the component
+	// at its natural size; see MeasureBlock.
+	Size() (width int, height int)
+}
+
+// RenderFunc adapts a function to Renderer and Sizable, such as for the
+// charts, whose options already have a width:
+//
+//	bars := brimtext.RenderFunc(func(width int) string {
+//		return brimtext.BarChart(labels, values, &brimtext.BarChartOptions{Width: width})
+//	})
+//
+// Size measures what the function returns for a width of 0.
+type RenderFunc func(width int) string
+
+// Render returns f(width).
+func (f RenderFunc) Render(width int) string {
+	return f(width)
+}
+
+// Size returns the size of f(0).
+func (f RenderFunc) Size() (width int, height int) {
+	return MeasureBlock(f(0))
+}
+
+// Block is text of a fixed shape, such as the output of one of the other
+// functions here, as a Renderer. It is rendered as with FitBlock, wrapping
+// lines too wide and padding the rest out to the width.
+type Block string
+
+// Render returns the block fit to the width; see FitBlock.
+func (b Block) Render(width int) string {
+	return FitBlock(string(b), width, 0, nil)
+}
+
+// Size returns the size of the block; see MeasureBlock.
+func (b Block) Size() (width int, height int) {
+	return MeasureBlock(string(b))
+}
+
+// Render returns the table narrowed to the width, as with
+// AlignOptions.MaxTableWidth, as far as its columns allow.
+func (t *Table) Render(width int) string {
+	if width < 1 {
+		return t.String()
+	}
+	opts := t.Options.Clone()
+	if opts == nil {
+		opts = NewDefaultAlignOptions()
+	}
+	opts.MaxTableWidth = width
+	return Align(t.Rows, opts)
+}
+
+// Size returns the size of the table as String renders it.
+func (t *Table) Size() (width int, height int) {
+	return MeasureBlock(t.String())
+}
+
+// Render returns the tree as Tree does with nil options, with any lines too
+// wide for the width cut short with "…".
+func (node *TreeNode) Render(width int) string {
+	return renderCrop(Tree(node, nil), width)
+}
+
+// Size returns the size of the tree as Tree gives it with nil options.
+func (node *TreeNode) Size() (width int, height int) {
+	return MeasureBlock(Tree(node, nil))
+}
+
+// renderCrop cuts the lines of the text wider than width, ending them with
+// "…".
+func renderCrop(text string, width int) string {
+	if width < 1 {
+		return text
+	}
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		lines[i] = truncateText(line, width, WrapTruncate, "")
+	}
+	return strings.Join(lines, "\n")
+}
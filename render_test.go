@@ -0,0 +1,73 @@
+package brimtext
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenderers(t *testing.T) {
+	var _ Renderer = &Table{}
+	var _ Sizable = &Table{}
+	var _ Renderer = Block("")
+	var _ Sizable = Block("")
+	var _ Renderer = &TreeNode{}
+	var _ Sizable = &TreeNode{}
+	var _ Renderer = RenderFunc(nil)
+	var _ Sizable = RenderFunc(nil)
+}
+
+func TestBlockRender(t *testing.T) {
+	b := Block("one two three\nfour\n")
+	if w, h := b.Size(); w != 13 || h != 2 {
+		t.Errorf("%d, %d != 13, 2", w, h)
+	}
+	out := b.Render(8)
+	exp := "one two \nthree   \nfour    \n"
+	if out != exp {
+		t.Errorf("%#v != %#v", out, exp)
+	}
+}
+
+func TestTableRender(t *testing.T) {
+	table := NewTable(nil)
+	table.AddRow("Name", "Notes")
+	table.AddRow("a", "one two three")
+	if w, h := table.Size(); w != 18 || h != 2 {
+		t.Errorf("%d, %d != 18, 2", w, h)
+	}
+	if out := table.Render(0); out != table.String() {
+		t.Errorf("%#v != %#v", out, table.String())
+	}
+	out := table.Render(12)
+	exp := "Name Notes\na    one two\n     three\n"
+	if out != exp {
+		t.Errorf("%#v != %#v", out, exp)
+	}
+}
+
+func TestTreeNodeRender(t *testing.T) {
+	root := &TreeNode{Label: "root", Children: []*TreeNode{{Label: "a long label"}}}
+	if w, h := root.Size(); w != 16 || h != 2 {
+		t.Errorf("%d, %d != 16, 2", w, h)
+	}
+	out := root.Render(10)
+	exp := "root\n└── a lon…\n"
+	if out != exp {
+		t.Errorf("%#v != %#v", out, exp)
+	}
+}
+
+func TestRenderFunc(t *testing.T) {
+	f := RenderFunc(func(width int) string {
+		if width < 1 {
+			width = 5
+		}
+		return strings.Repeat("x", width) + "\n"
+	})
+	if w, h := f.Size(); w != 5 || h != 1 {
+		t.Errorf("%d, %d != 5, 1", w, h)
+	}
+	if out := f.Render(3); out != "xxx\n" {
+		t.Errorf("%#v != %#v", out, "xxx\n")
+	}
+}
@@ -0,0 +1,414 @@
+package brimtext
+
+import (
+	"sort"
+	"strings"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/collate"
+	"golang.org/x/text/language"
+)
+
+// NaturalLess returns true if a should sort before b using a "natural" order,
+// where runs of digits are compared numerically rather than character by
+// character; this orders "file2" before "file10". Leading zeros are ignored
+// for the numeric comparison but fewer leading zeros will sort first when the
+// values are otherwise equal, so "file1" comes before "file01".
+func NaturalLess(a string, b string) bool {
+	return naturalCompare(a, b) < 0
+}
+
+func naturalCompare(a string, b string) int {
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		if isDigit(a[i]) && isDigit(b[j]) {
+			ni := i
+			for ni < len(a) && isDigit(a[ni]) {
+				ni++
+			}
+			nj := j
+			for nj < len(b) && isDigit(b[nj]) {
+				nj++
+			}
+			if c := compareDigits(a[i:ni], b[j:nj]); c != 0 {
+				return c
+			}
+			i, j = ni, nj
+			continue
+		}
+		if a[i] != b[j] {
+			if a[i] < b[j] {
+				return -1
+			}
+			return 1
+		}
+		i++
+		j++
+	}
+	if len(a)-i < len(b)-j {
+		return -1
+	} else if len(a)-i > len(b)-j {
+		return 1
+	}
+	return 0
+}
+
+// compareDigits compares two runs of ASCII digits numerically, using the
+// count of leading zeros as a tie breaker.
+func compareDigits(a string, b string) int {
+	za := 0
+	for za < len(a)-1 && a[za] == '0' {
+		za++
+	}
+	zb := 0
+	for zb < len(b)-1 && b[zb] == '0' {
+		zb++
+	}
+	ta, tb := a[za:], b[zb:]
+	if len(ta) != len(tb) {
+		if len(ta) < len(tb) {
+			return -1
+		}
+		return 1
+	}
+	if ta != tb {
+		if ta < tb {
+			return -1
+		}
+		return 1
+	}
+	if za < zb {
+		return -1
+	} else if za > zb {
+		return 1
+	}
+	return 0
+}
+
+func isDigit(b byte) bool {
+	return b >= '0' && b <= '9'
+}
+
+// StringSliceNaturalSort provides a sort.Interface that will sort a []string
+// using NaturalLess, so that embedded numbers are ordered numerically.
+type StringSliceNaturalSort []string
+
+func (s StringSliceNaturalSort) Len() int {
+	return len(s)
+}
+
+func (s StringSliceNaturalSort) Swap(x int, y int) {
+	s[x], s[y] = s[y], s[x]
+}
+
+func (s StringSliceNaturalSort) Less(x int, y int) bool {
+	return NaturalLess(s[x], s[y])
+}
+
+// VersionLess returns true if a should sort before b when both are treated as
+// semantic-version-like strings, such as "1.2.10" and "v2.0.0-rc.1". An
+// optional leading "v" is ignored, as is any "+build" metadata. The
+// dot-separated core parts are compared numerically, with missing parts
+// treated as zero, and a version with a "-pre.release" tag sorts before the
+// same version without one. Values that don't look like versions still sort
+// consistently, using NaturalLess for the parts that aren't numbers.
+func VersionLess(a string, b string) bool {
+	return versionCompare(a, b) < 0
+}
+
+func versionCompare(a string, b string) int {
+	coreA, preA := splitVersion(a)
+	coreB, preB := splitVersion(b)
+	partsA := strings.Split(coreA, ".")
+	partsB := strings.Split(coreB, ".")
+	for i := 0; i < len(partsA) || i < len(partsB); i++ {
+		pa, pb := "0", "0"
+		if i < len(partsA) {
+			pa = partsA[i]
+		}
+		if i < len(partsB) {
+			pb = partsB[i]
+		}
+		if c := naturalCompare(pa, pb); c != 0 {
+			return c
+		}
+	}
+	if preA == "" || preB == "" {
+		if preA != "" {
+			return -1
+		} else if preB != "" {
+			return 1
+		}
+		return naturalCompare(a, b)
+	}
+	partsA = strings.Split(preA, ".")
+	partsB = strings.Split(preB, ".")
+	for i := 0; i < len(partsA) && i < len(partsB); i++ {
+		numA, numB := allDigits(partsA[i]), allDigits(partsB[i])
+		if numA && !numB {
+			return -1
+		} else if !numA && numB {
+			return 1
+		} else if numA {
+			if c := compareDigits(partsA[i], partsB[i]); c != 0 {
+				return c
+			}
+		} else if partsA[i] != partsB[i] {
+			if partsA[i] < partsB[i] {
+				return -1
+			}
+			return 1
+		}
+	}
+	if len(partsA) < len(partsB) {
+		return -1
+	} else if len(partsA) > len(partsB) {
+		return 1
+	}
+	return naturalCompare(a, b)
+}
+
+// splitVersion returns the core and pre-release portions of the version
+// string, dropping any leading "v" and trailing "+build" metadata.
+func splitVersion(v string) (string, string) {
+	if len(v) > 1 && (v[0] == 'v' || v[0] == 'V') && isDigit(v[1]) {
+		v = v[1:]
+	}
+	if i := strings.IndexByte(v, '+'); i != -1 {
+		v = v[:i]
+	}
+	if i := strings.IndexByte(v, '-'); i != -1 {
+		return v[:i], v[i+1:]
+	}
+	return v, ""
+}
+
+func allDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if !isDigit(s[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// StringSliceVersionSort provides a sort.Interface that will sort a []string
+// using VersionLess.
+type StringSliceVersionSort []string
+
+func (s StringSliceVersionSort) Len() int {
+	return len(s)
+}
+
+func (s StringSliceVersionSort) Swap(x int, y int) {
+	s[x], s[y] = s[y], s[x]
+}
+
+func (s StringSliceVersionSort) Less(x int, y int) bool {
+	return VersionLess(s[x], s[y])
+}
+
+// CollateLess returns a less function that orders strings using the Unicode
+// collation rules for the language tag given, such as "en", "sv", or "de-AT";
+// an unrecognized tag falls back to the root collation order. This is what to
+// use when the ordering must be correct for people rather than merely
+// consistent, as with names like "Åsa" and "Zoe." The function returned is not
+// safe for concurrent use; create one per goroutine.
+func CollateLess(lang string) func(a string, b string) bool {
+	c := collate.New(language.Make(lang))
+	return func(a string, b string) bool {
+		return c.CompareString(a, b) < 0
+	}
+}
+
+// StringSliceCollateSort sorts the values in place using CollateLess for the
+// language tag given.
+func StringSliceCollateSort(values []string, lang string) {
+	collate.New(language.Make(lang)).SortStrings(values)
+}
+
+// FoldLess returns true if a should sort before b when compared using full
+// Unicode case folding, so that "STRASSE" and "straße" are treated alike. When
+// the folded values are equal the original strings are compared, giving a
+// stable and deterministic order.
+func FoldLess(a string, b string) bool {
+	fa, fb := foldString(a), foldString(b)
+	if fa != fb {
+		return fa < fb
+	}
+	return a < b
+}
+
+func foldString(s string) string {
+	return cases.Fold().String(s)
+}
+
+// StringSliceFoldSort provides a sort.Interface that will sort a []string
+// using FoldLess. Unlike StringSliceToLowerSort, this is a proper Unicode case
+// insensitive sort.
+type StringSliceFoldSort []string
+
+func (s StringSliceFoldSort) Len() int {
+	return len(s)
+}
+
+func (s StringSliceFoldSort) Swap(x int, y int) {
+	s[x], s[y] = s[y], s[x]
+}
+
+func (s StringSliceFoldSort) Less(x int, y int) bool {
+	return FoldLess(s[x], s[y])
+}
+
+// SortByKey sorts the items in place by the value the key function returns
+// for each, such as SortByKey(items, StripANSIEscapes) to ignore any color
+// codes. The key function is called just once per item and the sort is
+// stable, so items with equal keys keep their original order.
+func SortByKey(items []string, key func(string) string) {
+	SortByKeyFunc(items, key, nil)
+}
+
+// SortByKeyFunc is like SortByKey but compares the keys with the less
+// function given, such as NaturalLess or VersionLess. If less is nil, the
+// keys are compared with the < operator.
+func SortByKeyFunc(items []string, key func(string) string, less func(a string, b string) bool) {
+	keys := make([]string, len(items))
+	for i, item := range items {
+		keys[i] = key(item)
+	}
+	if less == nil {
+		less = func(a string, b string) bool { return a < b }
+	}
+	sort.Stable(&keyedSort{items: items, keys: keys, less: less})
+}
+
+type keyedSort struct {
+	items []string
+	keys  []string
+	less  func(a string, b string) bool
+}
+
+func (s *keyedSort) Len() int {
+	return len(s.items)
+}
+
+func (s *keyedSort) Swap(x int, y int) {
+	s.items[x], s.items[y] = s.items[y], s.items[x]
+	s.keys[x], s.keys[y] = s.keys[y], s.keys[x]
+}
+
+func (s *keyedSort) Less(x int, y int) bool {
+	return s.less(s.keys[x], s.keys[y])
+}
+
+// SortMode selects how a SortKey compares cells.
+type SortMode int
+
+const (
+	// SortString compares cells as strings.
+	SortString SortMode = iota
+	// SortNumeric compares cells as numbers, allowing "," separators and a
+	// "%" suffix; cells that aren't numbers sort after those that are,
+	// whatever the direction.
+	SortNumeric
+	// SortNatural compares cells with NaturalLess.
+	SortNatural
+	// SortVersion compares cells with VersionLess.
+	SortVersion
+)
+
+// SortKey is a column to sort the rows of a table by; see
+// AlignOptions.SortBy.
+type SortKey struct {
+	Column     int
+	Descending bool
+	Mode       SortMode
+	// Less, if not nil, compares the cells instead of the Mode.
+	Less func(a string, b string) bool
+}
+
+// compare returns -1, 0, or 1 as a sorts before, with, or after b by the
+// key, the cells given without ANSI escape codes.
+func (key *SortKey) compare(a string, b string) int {
+	c := 0
+	switch {
+	case key.Less != nil:
+		if key.Less(a, b) {
+			c = -1
+		} else if key.Less(b, a) {
+			c = 1
+		}
+	case key.Mode == SortNumeric:
+		va, oka := heatValue(a)
+		vb, okb := heatValue(b)
+		switch {
+		case oka && okb:
+			if va < vb {
+				c = -1
+			} else if va > vb {
+				c = 1
+			}
+		case oka:
+			return -1
+		case okb:
+			return 1
+		default:
+			c = strings.Compare(a, b)
+		}
+	case key.Mode == SortNatural:
+		c = naturalCompare(a, b)
+	case key.Mode == SortVersion:
+		c = versionCompare(a, b)
+	default:
+		c = strings.Compare(a, b)
+	}
+	if key.Descending {
+		c = -c
+	}
+	return c
+}
+
+// alignSortOrder returns the order to put the data rows in for the keys,
+// sorting each run of rows between nil rows after the header rows and
+// before footerStart, if >= 0, leaving the other rows in place. The sort is
+// stable, with each key breaking the ties of those before it.
+func alignSortOrder(data [][]string, keys []SortKey, headerRows int, footerStart int) []int {
+	end := len(data)
+	if footerStart >= 0 {
+		end = footerStart
+	}
+	order := make([]int, len(data))
+	for i := range order {
+		order[i] = i
+	}
+	cell := func(r int, col int) string {
+		if col >= 0 && col < len(data[r]) {
+			return StripANSIEscapes(data[r][col])
+		}
+		return ""
+	}
+	for start := headerRows; start < end; {
+		if data[start] == nil {
+			start++
+			continue
+		}
+		stop := start
+		for stop < end && data[stop] != nil {
+			stop++
+		}
+		run := order[start:stop]
+		sort.SliceStable(run, func(a int, b int) bool {
+			for k := range keys {
+				if c := keys[k].compare(cell(run[a], keys[k].Column), cell(run[b], keys[k].Column)); c != 0 {
+					return c < 0
+				}
+			}
+			return false
+		})
+		start = stop
+	}
+	return order
+}
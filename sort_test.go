@@ -0,0 +1,175 @@
+package brimtext
+
+import (
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestNaturalLess(t *testing.T) {
+	for _, v := range [][2]string{
+		{"", "a"},
+		{"a", "b"},
+		{"file2", "file10"},
+		{"file1", "file01"},
+		{"file01", "file2"},
+		{"host9.example.com", "host10.example.com"},
+		{"v1.2.9", "v1.2.10"},
+		{"a1b2", "a1b10"},
+		{"a1", "a1b"},
+		{"9", "a"},
+	} {
+		if !NaturalLess(v[0], v[1]) {
+			t.Errorf("NaturalLess(%#v, %#v) was false", v[0], v[1])
+		}
+		if NaturalLess(v[1], v[0]) {
+			t.Errorf("NaturalLess(%#v, %#v) was true", v[1], v[0])
+		}
+	}
+	if NaturalLess("file10", "file10") {
+		t.Errorf("NaturalLess of equal values was true")
+	}
+}
+
+func TestStringSliceNaturalSort(t *testing.T) {
+	out := []string{"file10", "file2", "File3", "file1", "file02"}
+	sort.Sort(StringSliceNaturalSort(out))
+	exp := []string{"File3", "file1", "file2", "file02", "file10"}
+	for i := 0; i < len(out); i++ {
+		if out[i] != exp[i] {
+			t.Fatalf("StringSliceNaturalSort fail at index %d %#v != %#v", i, out[i], exp[i])
+		}
+	}
+}
+
+func TestVersionLess(t *testing.T) {
+	for _, v := range [][2]string{
+		{"1.2.9", "1.2.10"},
+		{"1.2", "1.2.1"},
+		{"1.9.0", "1.10.0"},
+		{"v1.0.0", "v2.0.0"},
+		{"1.0.0", "v1.0.1"},
+		{"1.0.0-alpha", "1.0.0"},
+		{"1.0.0-alpha", "1.0.0-alpha.1"},
+		{"1.0.0-alpha.1", "1.0.0-alpha.beta"},
+		{"1.0.0-alpha.beta", "1.0.0-beta"},
+		{"1.0.0-beta.2", "1.0.0-beta.11"},
+		{"1.0.0-rc.1", "1.0.0"},
+		{"1.0.0", "1.0.1-rc.1"},
+	} {
+		if !VersionLess(v[0], v[1]) {
+			t.Errorf("VersionLess(%#v, %#v) was false", v[0], v[1])
+		}
+		if VersionLess(v[1], v[0]) {
+			t.Errorf("VersionLess(%#v, %#v) was true", v[1], v[0])
+		}
+	}
+	if VersionLess("1.0.0+build.2", "1.0.0+build.2") {
+		t.Errorf("VersionLess of equal values was true")
+	}
+}
+
+func TestStringSliceVersionSort(t *testing.T) {
+	out := []string{"1.10.0", "1.2.10", "1.2.9", "1.2.10-rc.1", "0.9"}
+	sort.Sort(StringSliceVersionSort(out))
+	exp := []string{"0.9", "1.2.9", "1.2.10-rc.1", "1.2.10", "1.10.0"}
+	for i := 0; i < len(out); i++ {
+		if out[i] != exp[i] {
+			t.Fatalf("StringSliceVersionSort fail at index %d %#v != %#v", i, out[i], exp[i])
+		}
+	}
+}
+
+func TestCollateLess(t *testing.T) {
+	less := CollateLess("en")
+	if !less("Åsa", "Zoe") {
+		t.Errorf("en: Åsa should sort before Zoe")
+	}
+	less = CollateLess("sv")
+	if less("Åsa", "Zoe") {
+		t.Errorf("sv: Åsa should sort after Zoe")
+	}
+	less = CollateLess("not a language")
+	if !less("abc", "abd") {
+		t.Errorf("fallback: abc should sort before abd")
+	}
+}
+
+func TestStringSliceCollateSort(t *testing.T) {
+	out := []string{"Zoe", "Åsa", "adam", "Örjan", "Bob"}
+	StringSliceCollateSort(out, "sv")
+	exp := []string{"adam", "Bob", "Zoe", "Åsa", "Örjan"}
+	for i := 0; i < len(out); i++ {
+		if out[i] != exp[i] {
+			t.Fatalf("StringSliceCollateSort sv fail at index %d %#v != %#v", i, out[i], exp[i])
+		}
+	}
+	StringSliceCollateSort(out, "en")
+	exp = []string{"adam", "Åsa", "Bob", "Örjan", "Zoe"}
+	for i := 0; i < len(out); i++ {
+		if out[i] != exp[i] {
+			t.Fatalf("StringSliceCollateSort en fail at index %d %#v != %#v", i, out[i], exp[i])
+		}
+	}
+}
+
+func TestFoldLess(t *testing.T) {
+	for _, v := range [][2]string{
+		{"abc", "ABD"},
+		{"ABC", "abc"},
+		{"STRASSE", "straße"},
+		{"strasse", "straße"},
+		{"straße", "strat"},
+		{"ǅ", "ǆ"},
+	} {
+		if !FoldLess(v[0], v[1]) {
+			t.Errorf("FoldLess(%#v, %#v) was false", v[0], v[1])
+		}
+		if FoldLess(v[1], v[0]) {
+			t.Errorf("FoldLess(%#v, %#v) was true", v[1], v[0])
+		}
+	}
+}
+
+func TestStringSliceFoldSort(t *testing.T) {
+	out := []string{"strat", "straße", "STRASSE", "Straw", "strasse"}
+	sort.Sort(StringSliceFoldSort(out))
+	exp := []string{"STRASSE", "strasse", "straße", "strat", "Straw"}
+	for i := 0; i < len(out); i++ {
+		if out[i] != exp[i] {
+			t.Fatalf("StringSliceFoldSort fail at index %d %#v != %#v", i, out[i], exp[i])
+		}
+	}
+}
+
+func TestSortByKey(t *testing.T) {
+	red := string(ANSIEscape.FRed)
+	reset := string(ANSIEscape.Reset)
+	out := []string{red + "zed" + reset, "bob", red + "amy" + reset, "carl"}
+	SortByKey(out, StripANSIEscapes)
+	exp := []string{red + "amy" + reset, "bob", "carl", red + "zed" + reset}
+	for i := 0; i < len(out); i++ {
+		if out[i] != exp[i] {
+			t.Fatalf("SortByKey fail at index %d %#v != %#v", i, out[i], exp[i])
+		}
+	}
+	out = []string{"b:2", "a:1", "c:1", "d:2"}
+	SortByKey(out, func(s string) string { return s[2:] })
+	exp = []string{"a:1", "c:1", "b:2", "d:2"}
+	for i := 0; i < len(out); i++ {
+		if out[i] != exp[i] {
+			t.Fatalf("SortByKey stable fail at index %d %#v != %#v", i, out[i], exp[i])
+		}
+	}
+}
+
+func TestSortByKeyFunc(t *testing.T) {
+	out := []string{"  file10", "file2 ", " file1"}
+	SortByKeyFunc(out, strings.TrimSpace, NaturalLess)
+	exp := []string{" file1", "file2 ", "  file10"}
+	for i := 0; i < len(out); i++ {
+		if out[i] != exp[i] {
+			t.Fatalf("SortByKeyFunc fail at index %d %#v != %#v", i, out[i], exp[i])
+		}
+	}
+}
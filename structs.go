@@ -0,0 +1,212 @@
+package brimtext
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// StructFormats maps the names that may be given with format= in the
+// brimtext struct tags read by AlignStructs to their ColumnFormatters; add
+// to it to define application specific formats.
+var StructFormats = map[string]ColumnFormatter{
+	"bool":      BooleanFormatter("", ""),
+	"bytes":     BytesFormatter(1024),
+	"bytes1000": BytesFormatter(1000),
+	"duration":  DurationFormatter(time.Second),
+	"percent":   PercentFormatter(1),
+	"thousands": ThousandsFormatter(","),
+}
+
+// structColumn is one column AlignStructs builds from a struct field.
+type structColumn struct {
+	index     []int
+	title     string
+	order     int
+	orderSet  bool
+	align     Alignment
+	alignSet  bool
+	width     int
+	formatter ColumnFormatter
+}
+
+// AlignStructs formats a slice of structs, or pointers to structs, as a
+// table with a header row of field names, so callers needn't flatten their
+// values into [][]string first; nil elements are skipped. Each exported
+// field is a column, with the fields of embedded structs included as though
+// they were the outer struct's. Field values are turned into cells according
+// to opts.Types; see TypeRegistry.
+//
+// Columns are controlled with struct tags such as:
+//
+//	type Job struct {
+//		Name string        `brimtext:"Job Name"`
+//		Size int64         `brimtext:",align=right,format=bytes"`
+//		Took time.Duration `brimtext:"Duration,order=-1"`
+//		Note string        `brimtext:",width=20"`
+//		id   int
+//		Skip string        `brimtext:"-"`
+//	}
+//
+// The first part of the tag is the column title, the field name if empty,
+// or "-" to leave the field out. The options are:
+//
+//	align=left|right|center|justify|decimal
+//	                                 the column's alignment
+//	width=N                          wraps the column's cells to N
+//	format=NAME                      a ColumnFormatter from StructFormats
+//	order=N                          sorts the column to position N, with
+//	                                 untagged fields at their index
+//
+// The alignments, widths, and formatters so chosen replace those of opts,
+// and a nil row is put after the header row. If opts is nil,
+// NewDefaultAlignOptions is used. An error is returned if v isn't a slice
+// of structs or a tag can't be parsed.
+func AlignStructs(v interface{}, opts *AlignOptions) (string, error) {
+	if opts == nil {
+		opts = NewDefaultAlignOptions()
+	}
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return "", fmt.Errorf("brimtext: AlignStructs needs a slice of structs, not %T", v)
+	}
+	t := rv.Type().Elem()
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return "", fmt.Errorf("brimtext: AlignStructs needs a slice of structs, not %T", v)
+	}
+	columns, err := structColumns(t, nil)
+	if err != nil {
+		return "", err
+	}
+	for c := range columns {
+		if !columns[c].orderSet {
+			columns[c].order = c
+		}
+	}
+	sort.SliceStable(columns, func(a int, b int) bool {
+		return columns[a].order < columns[b].order
+	})
+	types := opts.Types
+	if types == nil {
+		types = DefaultTypeRegistry
+	}
+	opts = opts.Clone()
+	opts.Alignments = make([]Alignment, len(columns))
+	opts.Widths = make([]int, len(columns))
+	opts.Formatters = make([]ColumnFormatter, len(columns))
+	header := make([]string, len(columns))
+	for c, column := range columns {
+		header[c] = column.title
+		opts.Alignments[c] = column.align
+		if !column.alignSet {
+			opts.Alignments[c] = types.Style(t.FieldByIndex(column.index).Type).Alignment
+		}
+		opts.Widths[c] = column.width
+		opts.Formatters[c] = column.formatter
+	}
+	data := make([][]string, 0, rv.Len()+2)
+	data = append(data, header, nil)
+	for i := 0; i < rv.Len(); i++ {
+		if elem := rv.Index(i); elem.Kind() == reflect.Ptr && elem.IsNil() {
+			continue
+		}
+		row := make([]string, len(columns))
+		for c, column := range columns {
+			if field, ok := structField(rv.Index(i), column.index); ok {
+				row[c] = types.Format(field.Interface())
+			}
+		}
+		data = append(data, row)
+	}
+	return Align(data, opts), nil
+}
+
+// structColumns returns the columns for the exported fields of the struct
+// type, with index prefixing their field indexes.
+func structColumns(t reflect.Type, index []int) ([]structColumn, error) {
+	var columns []structColumn
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		fieldIndex := append(append([]int(nil), index...), i)
+		tag, tagged := f.Tag.Lookup("brimtext")
+		if f.Anonymous && !tagged && f.Type.Kind() == reflect.Struct {
+			embedded, err := structColumns(f.Type, fieldIndex)
+			if err != nil {
+				return nil, err
+			}
+			columns = append(columns, embedded...)
+			continue
+		}
+		if f.PkgPath != "" || tag == "-" {
+			continue
+		}
+		column := structColumn{index: fieldIndex, title: f.Name}
+		parts := strings.Split(tag, ",")
+		if parts[0] != "" {
+			column.title = parts[0]
+		}
+		for _, part := range parts[1:] {
+			name, value := part, ""
+			if j := strings.IndexByte(part, '='); j >= 0 {
+				name, value = part[:j], part[j+1:]
+			}
+			var err error
+			switch name {
+			case "align":
+				column.alignSet = true
+				switch value {
+				case "left":
+					column.align = Left
+				case "right":
+					column.align = Right
+				case "center":
+					column.align = Center
+				case "justify":
+					column.align = Justify
+				case "decimal":
+					column.align = Decimal
+				default:
+					err = fmt.Errorf("unknown alignment %q", value)
+				}
+			case "width":
+				column.width, err = strconv.Atoi(value)
+			case "order":
+				column.orderSet = true
+				column.order, err = strconv.Atoi(value)
+			case "format":
+				column.formatter = StructFormats[value]
+				if column.formatter == nil {
+					err = fmt.Errorf("unknown format %q", value)
+				}
+			default:
+				err = fmt.Errorf("unknown option %q", name)
+			}
+			if err != nil {
+				return nil, fmt.Errorf("brimtext: tag of field %s: %v", f.Name, err)
+			}
+		}
+		columns = append(columns, column)
+	}
+	return columns, nil
+}
+
+// structField returns the field of the struct value v at the index,
+// following pointers; ok is false if a nil pointer is in the way.
+func structField(v reflect.Value, index []int) (field reflect.Value, ok bool) {
+	for _, i := range index {
+		for v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				return v, false
+			}
+			v = v.Elem()
+		}
+		v = v.Field(i)
+	}
+	return v, true
+}
@@ -0,0 +1,53 @@
+package brimtext
+
+import (
+	"testing"
+	"time"
+)
+
+type testStructBase struct {
+	ID int `brimtext:"ID,order=-1"`
+}
+
+type testStructJob struct {
+	testStructBase
+	Name  string
+	Size  ByteSize `brimtext:"Bytes"`
+	Took  time.Duration
+	Note  string `brimtext:",width=5"`
+	Count int    `brimtext:",align=left,format=thousands"`
+	Skip  string `brimtext:"-"`
+	note  string
+}
+
+func TestAlignStructs(t *testing.T) {
+	jobs := []*testStructJob{
+		{testStructBase{7}, "build", 1234567, 90 * time.Second, "slow job", 1234, "x", "y"},
+		nil,
+		{testStructBase{12}, "test", 512, time.Second, "", 5, "", ""},
+	}
+	out, err := AlignStructs(jobs, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp := "ID Name    Bytes  Took Note Count\n" +
+		"\n" +
+		" 7 build 1.18MiB 1m30s slow 1,234\n" +
+		"                       job  \n" +
+		"12 test     512B    1s      5\n"
+	if out != exp {
+		t.Errorf("%#v != %#v", out, exp)
+	}
+	if _, err = AlignStructs([]int{1}, nil); err == nil {
+		t.Error("expected an error for a slice of ints")
+	}
+	if _, err = AlignStructs(struct{}{}, nil); err == nil {
+		t.Error("expected an error for a struct")
+	}
+	type bad struct {
+		A string `brimtext:",align=up"`
+	}
+	if _, err = AlignStructs([]bad{{}}, nil); err == nil || err.Error() != `brimtext: tag of field A: unknown alignment "up"` {
+		t.Errorf("%v", err)
+	}
+}
@@ -0,0 +1,492 @@
+package brimtext
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// Table collects rows to render with Align and its configured options. Its
+// Header, Row, and Separator methods return the table, so one can be built
+// up without keeping track of nil rows:
+//
+//	s := brimtext.NewTable(brimtext.NewSimpleAlignOptions()).
+//		Header("Name", "Age").
+//		Row("Bob", "42").
+//		Row("Alice", "37").
+//		String()
+//
+// Render gives the table narrowed to a width instead; Render(0) is the same
+// as String.
+type Table struct {
+	// Rows are the rows as given to Align; nil rows are separators.
+	Rows [][]string
+	// Options are used when rendering; if nil, NewDefaultAlignOptions is
+	// used.
+	Options *AlignOptions
+
+	// headers is how many rows Header has put at the top of Rows.
+	headers int
+}
+
+// NewTable returns an empty Table that will render with opts.
+func NewTable(opts *AlignOptions) *Table {
+	return &Table{Options: opts}
+}
+
+// AddRow appends a row of cells to the table.
+func (t *Table) AddRow(cells ...string) {
+	if cells == nil {
+		cells = []string{}
+	}
+	t.Rows = append(t.Rows, cells)
+}
+
+// AddSeparator appends a nil row to the table, which the options may render
+// as a separator line, such as between a header and the rest of the rows.
+func (t *Table) AddSeparator() {
+	t.Rows = append(t.Rows, nil)
+}
+
+// Header adds a header row at the top of the table, after any added by
+// earlier Header calls; the first call also adds a separator after it. It may
+// be called at any time, such as after the rows are known.
+func (t *Table) Header(cells ...string) *Table {
+	if cells == nil {
+		cells = []string{}
+	}
+	if t.headers == 0 {
+		t.Rows = append([][]string{cells, nil}, t.Rows...)
+		t.headers = 1
+		return t
+	}
+	t.Rows = append(t.Rows, nil)
+	copy(t.Rows[t.headers+1:], t.Rows[t.headers:])
+	t.Rows[t.headers] = cells
+	t.headers++
+	return t
+}
+
+// Row is AddRow returning the table.
+func (t *Table) Row(cells ...string) *Table {
+	t.AddRow(cells...)
+	return t
+}
+
+// Separator is AddSeparator returning the table.
+func (t *Table) Separator() *Table {
+	t.AddSeparator()
+	return t
+}
+
+// String returns the table as rendered by Align, letting a Table be given
+// directly to fmt.Println and the like.
+func (t *Table) String() string {
+	return Align(t.Rows, t.Options)
+}
+
+// MarshalText implements encoding.TextMarshaler, returning the same output
+// as String.
+func (t *Table) MarshalText() ([]byte, error) {
+	return []byte(t.String()), nil
+}
+
+// TableWriter outputs a table to an io.Writer as rows are added, rather than
+// holding the whole table and its rendering in memory as Align does.
+//
+// By default, rows are held until Flush so the column widths are exact. With
+// SampleRows set, only that many rows are held to choose the column widths;
+// the rest are output as they are added, with cells wider than their column
+// wrapped, or truncated if Truncate is set, and cells beyond the columns
+// measured dropped, trading perfect widths for bounded memory on unbounded
+// input. With FixedWidths set, no rows are held at all.
+// AlignOptions.FooterRows and FooterStyle only apply when all the rows are
+// held, as the last rows aren't known until Flush otherwise.
+//
+// Rows output before the first nil row is added are taken as header rows, for
+// AlignOptions.Redact, HeaderStyle, and the like, as Align takes the rows
+// before the first nil row; unlike with Align, this is so even if no nil row
+// follows.
+type TableWriter struct {
+	// SampleRows is how many rows to measure before output begins; if < 1,
+	// all rows are held until Flush.
+	SampleRows int
+	// Truncate cuts off cells too wide for their column, ending them with
+	// the AlignOptions.Ellipsis, rather than wrapping them onto more lines.
+	Truncate bool
+	// FixedWidths, if not empty, sets the width of each column up front, so
+	// output begins with the first row, with cells too wide wrapped or
+	// truncated as with SampleRows. Widths < 1 are taken as 1.
+	FixedWidths []int
+	w           io.Writer
+	opts        *AlignOptions
+	rows        [][]string
+	renderer    *alignRenderer
+	wrote       bool
+	// dataRows and headerRows count the rows added for the
+	// RowCountFooter; headerRows is -1 until a nil row is added.
+	dataRows   int
+	headerRows int
+}
+
+// NewTableWriter returns a TableWriter that writes to w, formatted according
+// to opts as with Align. If opts is nil, NewDefaultAlignOptions is used.
+func NewTableWriter(w io.Writer, opts *AlignOptions) *TableWriter {
+	if opts == nil {
+		opts = NewDefaultAlignOptions()
+	}
+	return &TableWriter{w: w, opts: opts, headerRows: -1}
+}
+
+// AddRow adds a row of cells to the table; a nil row is a separator, as with
+// Align. Any error is from writing to the underlying io.Writer.
+func (tw *TableWriter) AddRow(cells []string) error {
+	if cells != nil {
+		tw.dataRows++
+	} else if tw.headerRows < 0 {
+		tw.headerRows = tw.dataRows
+	}
+	if tw.renderer == nil && len(tw.FixedWidths) > 0 {
+		if err := tw.startFixed(); err != nil {
+			return err
+		}
+	}
+	if tw.renderer == nil {
+		tw.rows = append(tw.rows, cells)
+		if tw.SampleRows < 1 || len(tw.rows) < tw.SampleRows {
+			return nil
+		}
+		return tw.start()
+	}
+	var buf bytes.Buffer
+	tw.row(&buf, cells)
+	return tw.write(&buf)
+}
+
+// Flush outputs any held rows and the end of the table. Rows added after
+// Flush begin a new table.
+func (tw *TableWriter) Flush() error {
+	if tw.renderer == nil {
+		if len(tw.rows) == 0 {
+			return nil
+		}
+		if err := tw.start(); err != nil {
+			return err
+		}
+	}
+	tw.renderer.rowCount = tw.dataRows
+	if tw.opts.NilBetweenEveryRow {
+		tw.renderer.rowCount--
+	} else if tw.headerRows > 0 {
+		tw.renderer.rowCount -= tw.headerRows
+	}
+	if tw.renderer.rowCount < 0 {
+		tw.renderer.rowCount = 0
+	}
+	var buf bytes.Buffer
+	tw.renderer.last(&buf)
+	tw.renderer = nil
+	tw.wrote = false
+	tw.dataRows = 0
+	tw.headerRows = -1
+	return tw.write(&buf)
+}
+
+// WriteRows adds each row from next, as with AddRow, and then calls Flush;
+// with SampleRows set, this renders rows from a database cursor, a large
+// file, or the like without holding them all in memory.
+func (tw *TableWriter) WriteRows(next RowSource) error {
+	return tw.WriteRowsContext(context.Background(), next)
+}
+
+// WriteRowsContext is like WriteRows but stops with ctx.Err() if ctx is done
+// before all the rows are written. Rows held to measure the column widths
+// are then dropped, and a table already begun is ended as though by Flush,
+// so the output isn't left partway through a row or without its last line.
+func (tw *TableWriter) WriteRowsContext(ctx context.Context, next RowSource) error {
+	for {
+		if err := ctx.Err(); err != nil {
+			if tw.renderer == nil {
+				tw.rows = nil
+				tw.dataRows = 0
+				tw.headerRows = -1
+				return err
+			}
+			if ferr := tw.Flush(); ferr != nil {
+				return ferr
+			}
+			return err
+		}
+		row, ok := next()
+		if !ok {
+			break
+		}
+		if err := tw.AddRow(row); err != nil {
+			return err
+		}
+	}
+	return tw.Flush()
+}
+
+// start measures the held rows to fix the column widths and outputs them.
+func (tw *TableWriter) start() error {
+	opts := tw.opts
+	headers := -1
+	if tw.SampleRows > 0 && len(tw.rows) >= tw.SampleRows {
+		opts = opts.Clone()
+		opts.FooterRows = 0
+		opts.FooterStyle = nil
+		if tw.headerRows < 0 && !opts.NilBetweenEveryRow {
+			headers = len(tw.rows)
+		}
+	}
+	layout, _ := newAlignLayout(context.Background(), tw.rows, nil, nil, headers, opts)
+	tw.renderer = newAlignRenderer(tw.opts, layout.Widths, layout.Alignments)
+	var buf bytes.Buffer
+	tw.renderer.first(&buf)
+	for i, row := range layout.Rows {
+		tw.renderer.row(&buf, row, layoutRowAlignments(layout, i), nil)
+	}
+	tw.wrote = len(layout.Rows) > 0
+	tw.rows = nil
+	return tw.write(&buf)
+}
+
+// startFixed begins the table with the FixedWidths.
+func (tw *TableWriter) startFixed() error {
+	widths := make([]int, len(tw.FixedWidths))
+	alignments := make([]Alignment, len(tw.FixedWidths))
+	for c, width := range tw.FixedWidths {
+		if width < 1 {
+			width = 1
+		}
+		widths[c] = width
+		if c < len(tw.opts.Alignments) {
+			alignments[c] = tw.opts.Alignments[c]
+		}
+	}
+	tw.renderer = newAlignRenderer(tw.opts, widths, alignments)
+	var buf bytes.Buffer
+	tw.renderer.first(&buf)
+	return tw.write(&buf)
+}
+
+// row outputs a row after the column widths are fixed, fitting its cells to
+// them.
+func (tw *TableWriter) row(buf *bytes.Buffer, cells []string) {
+	r := tw.renderer
+	if cells == nil {
+		if !tw.opts.NilBetweenEveryRow {
+			r.row(buf, nil, nil, nil)
+		}
+		return
+	}
+	if tw.opts.NilBetweenEveryRow && tw.wrote {
+		r.row(buf, nil, nil, nil)
+	}
+	tw.wrote = true
+	if len(cells) > len(r.widths) {
+		cells = cells[:len(r.widths)]
+	}
+	opts := tw.opts.Clone()
+	opts.NilBetweenEveryRow = false
+	opts.MaxTableWidth = 0
+	opts.FitWidth = false
+	opts.WidthFor = nil
+	opts.FooterRows = 0
+	opts.FooterStyle = nil
+	if !tw.Truncate {
+		opts.Widths = make([]int, len(cells))
+		for c := range cells {
+			if c < len(r.widths) {
+				opts.Widths[c] = r.widths[c]
+			}
+			if c < len(tw.opts.Widths) && tw.opts.Widths[c] > 0 && (opts.Widths[c] == 0 || tw.opts.Widths[c] < opts.Widths[c]) {
+				opts.Widths[c] = tw.opts.Widths[c]
+			}
+		}
+	}
+	headers := 0
+	if tw.opts.NilBetweenEveryRow && tw.dataRows == 1 || !tw.opts.NilBetweenEveryRow && tw.headerRows < 0 {
+		headers = 1
+	}
+	layout, _ := newAlignLayout(context.Background(), [][]string{cells}, nil, nil, headers, opts)
+	for i, line := range layout.Rows {
+		var fitted [][]string
+		for c, cell := range line {
+			width := r.widths[c]
+			if DisplayWidth(cell) <= width || width < 1 {
+				fitted = tableWriterSet(fitted, 0, len(line), c, cell)
+				continue
+			}
+			if tw.Truncate {
+				fitted = tableWriterSet(fitted, 0, len(line), c, truncateText(cell, width, WrapTruncate, tw.opts.Ellipsis))
+				continue
+			}
+			for i, part := range wrapRunes(cell, width) {
+				fitted = tableWriterSet(fitted, i, len(line), c, part)
+			}
+		}
+		for _, f := range fitted {
+			r.row(buf, f, layoutRowAlignments(layout, i), nil)
+		}
+	}
+}
+
+// layoutRowAlignments returns the alignments overriding the columns' for the
+// row of the layout, if any.
+func layoutRowAlignments(layout *AlignLayout, row int) []Alignment {
+	if layout.RowAlignments == nil {
+		return nil
+	}
+	return layout.RowAlignments[row]
+}
+
+// layoutRowSpans returns the spans of the cells of the row of the layout, if
+// any.
+func layoutRowSpans(layout *AlignLayout, row int) []int {
+	if layout.Spans == nil {
+		return nil
+	}
+	return layout.Spans[row]
+}
+
+// layoutNextSpans returns the spans of the first row of the layout from row
+// on that isn't a separator, if any.
+func layoutNextSpans(layout *AlignLayout, row int) []int {
+	if layout.Spans == nil {
+		return nil
+	}
+	for ; row < len(layout.Rows); row++ {
+		if layout.Rows[row] != nil {
+			return layout.Spans[row]
+		}
+	}
+	return nil
+}
+
+// tableWriterSet sets the cell of the line, adding blank lines as needed.
+func tableWriterSet(lines [][]string, line int, cells int, c int, cell string) [][]string {
+	for len(lines) <= line {
+		lines = append(lines, make([]string, cells))
+	}
+	lines[line][c] = cell
+	return lines
+}
+
+// truncateText cuts s to width display columns, as WrapTruncate,
+// WrapTruncateHead, or WrapTruncateMiddle give by mode, marking the cut with
+// the ellipsis, "…" if empty. The ANSI escape codes of s are all kept, those
+// of the removed text placed with the ellipsis, so styling carries on as it
+// would have.
+func truncateText(s string, width int, mode WrapMode, ellipsis string) string {
+	if DisplayWidth(s) <= width {
+		return s
+	}
+	if ellipsis == "" {
+		ellipsis = "…"
+	}
+	if width < 1 {
+		return ""
+	}
+	ew := DisplayWidth(ellipsis)
+	if ew >= width {
+		return wrapRunes(ellipsis, width)[0]
+	}
+	keep := width - ew
+	head, tail := keep, 0
+	switch mode {
+	case WrapTruncateHead:
+		head, tail = 0, keep
+	case WrapTruncateMiddle:
+		head, tail = keep-keep/2, keep/2
+	}
+	type token struct {
+		text  string
+		width int
+	}
+	var tokens []token
+	total := 0
+	for i := 0; i < len(s); {
+		if n := sgrLen(s[i:]); n > 0 {
+			tokens = append(tokens, token{s[i : i+n], -1})
+			i += n
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		tokens = append(tokens, token{s[i : i+size], RuneWidth(r)})
+		total += RuneWidth(r)
+		i += size
+	}
+	var start, cut, end strings.Builder
+	at := 0
+	for _, t := range tokens {
+		inHead := at < head
+		if t.width >= 0 {
+			inHead = at+t.width <= head
+		}
+		switch {
+		case inHead:
+			start.WriteString(t.text)
+		case tail > 0 && at >= total-tail:
+			end.WriteString(t.text)
+		case t.width < 0:
+			cut.WriteString(t.text)
+		}
+		if t.width > 0 {
+			at += t.width
+		}
+	}
+	return start.String() + cut.String() + ellipsis + end.String()
+}
+
+func (tw *TableWriter) write(buf *bytes.Buffer) error {
+	if buf.Len() == 0 {
+		return nil
+	}
+	_, err := tw.w.Write(buf.Bytes())
+	return err
+}
+
+// RowSource produces the rows of a table one at a time, such as from a
+// database cursor, returning false once there are no more. A nil row is a
+// separator, as with Align.
+type RowSource func() ([]string, bool)
+
+// SliceRowSource returns a RowSource giving each of the rows in order.
+func SliceRowSource(rows [][]string) RowSource {
+	i := 0
+	return func() ([]string, bool) {
+		if i >= len(rows) {
+			return nil, false
+		}
+		i++
+		return rows[i-1], true
+	}
+}
+
+// ChannelRowSource returns a RowSource giving each row received from ch
+// until it is closed.
+func ChannelRowSource(ch <-chan []string) RowSource {
+	return func() ([]string, bool) {
+		row, ok := <-ch
+		return row, ok
+	}
+}
+
+// AlignRows is like Align but with the rows read from next. All the rows are
+// held to measure the column widths; use TableWriter.WriteRows with
+// SampleRows set for bounded memory.
+func AlignRows(next RowSource, opts *AlignOptions) string {
+	var data [][]string
+	for {
+		row, ok := next()
+		if !ok {
+			break
+		}
+		data = append(data, row)
+	}
+	return Align(data, opts)
+}
@@ -0,0 +1,401 @@
+package brimtext
+
+import (
+	"bytes"
+	"context"
+	"encoding"
+	"fmt"
+	"testing"
+)
+
+var _ fmt.Stringer = &Table{}
+var _ encoding.TextMarshaler = &Table{}
+
+func TestTable(t *testing.T) {
+	tbl := NewTable(NewSimpleAlignOptions())
+	tbl.AddRow("Name", "Age")
+	tbl.AddSeparator()
+	tbl.AddRow("Bob", "42")
+	exp := `+------+-----+
+| Name | Age |
++------+-----+
+| Bob  | 42  |
++------+-----+
+`
+	if out := fmt.Sprint(tbl); out != exp {
+		t.Errorf("%#v != %#v", out, exp)
+	}
+	b, err := tbl.MarshalText()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != exp {
+		t.Errorf("%#v != %#v", string(b), exp)
+	}
+	if out := NewTable(nil).String(); out != "" {
+		t.Errorf("%#v", out)
+	}
+	tbl = &Table{Rows: [][]string{{"a", "b"}}}
+	if out := tbl.String(); out != "a b\n" {
+		t.Errorf("%#v", out)
+	}
+}
+
+func TestTableWriter(t *testing.T) {
+	data := [][]string{
+		{"Name", "Count"},
+		nil,
+		{"alpha", "1"},
+		{"beta", "22"},
+		{"gamma\ndelta", "333"},
+	}
+	var buf bytes.Buffer
+	tw := NewTableWriter(&buf, NewSimpleAlignOptions())
+	for _, row := range data {
+		if err := tw.AddRow(row); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if buf.Len() != 0 {
+		t.Errorf("TableWriter wrote before Flush: %#v", buf.String())
+	}
+	if err := tw.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	exp := Align(data, NewSimpleAlignOptions())
+	if buf.String() != exp {
+		t.Errorf("%#v != %#v", buf.String(), exp)
+	}
+	buf.Reset()
+	if err := tw.Flush(); err != nil || buf.Len() != 0 {
+		t.Errorf("empty Flush: %v %#v", err, buf.String())
+	}
+}
+
+func TestTableWriterSampleRows(t *testing.T) {
+	var buf bytes.Buffer
+	tw := NewTableWriter(&buf, NewSimpleAlignOptions())
+	tw.SampleRows = 3
+	tw.AddRow([]string{"Name", "Count"})
+	tw.AddRow(nil)
+	if buf.Len() != 0 {
+		t.Errorf("TableWriter wrote before sampling: %#v", buf.String())
+	}
+	tw.AddRow([]string{"alpha", "1"})
+	exp := `+-------+-------+
+| Name  | Count |
++-------+-------+
+| alpha | 1     |
+`
+	if buf.String() != exp {
+		t.Errorf("%#v != %#v", buf.String(), exp)
+	}
+	tw.AddRow([]string{"longer name", "42"})
+	tw.AddRow([]string{"x", "3", "extra"})
+	tw.Flush()
+	exp += `| longe | 42    |
+| r     |       |
+| name  |       |
+| x     | 3     |
++-------+-------+
+`
+	if buf.String() != exp {
+		t.Errorf("%#v != %#v", buf.String(), exp)
+	}
+	buf.Reset()
+	tw = NewTableWriter(&buf, nil)
+	tw.SampleRows = 1
+	tw.Truncate = true
+	tw.AddRow([]string{"abc", "de"})
+	tw.AddRow([]string{"abcdef", "\x1b[1mdefg\x1b[0m"})
+	tw.Flush()
+	exp = "abc de\n" +
+		"ab… \x1b[1md\x1b[0m…\n"
+	if buf.String() != exp {
+		t.Errorf("%#v != %#v", buf.String(), exp)
+	}
+}
+
+func TestTableWriterFixedWidths(t *testing.T) {
+	var buf bytes.Buffer
+	tw := NewTableWriter(&buf, NewSimpleAlignOptions())
+	tw.FixedWidths = []int{5, 3}
+	tw.AddRow([]string{"Name", "Count"})
+	exp := `+-------+-----+
+| Name  | Cou |
+|       | nt  |
+`
+	if buf.String() != exp {
+		t.Errorf("%#v != %#v", buf.String(), exp)
+	}
+	tw.AddRow(nil)
+	tw.AddRow([]string{"alpha", "1"})
+	tw.Flush()
+	exp += `+-------+-----+
+| alpha | 1   |
++-------+-----+
+`
+	if buf.String() != exp {
+		t.Errorf("%#v != %#v", buf.String(), exp)
+	}
+	buf.Reset()
+	tw.AddRow([]string{"a", "b", "extra"})
+	tw.Flush()
+	exp = `+-------+-----+
+| a     | b   |
++-------+-----+
+`
+	if buf.String() != exp {
+		t.Errorf("%#v != %#v", buf.String(), exp)
+	}
+}
+
+func TestTableWriterNilBetweenEveryRow(t *testing.T) {
+	data := [][]string{{"a", "b"}, {"cc", "d"}, {"e", "f"}}
+	var buf bytes.Buffer
+	tw := NewTableWriter(&buf, NewBoxedAlignOptions())
+	tw.SampleRows = 1
+	for _, row := range data {
+		tw.AddRow(row)
+	}
+	tw.Flush()
+	exp := `+===+===+
+| a | b |
++===+===+
+| c | d |
+| c |   |
++---+---+
+| e | f |
++===+===+
+`
+	if buf.String() != exp {
+		t.Errorf("%#v != %#v", buf.String(), exp)
+	}
+}
+
+func TestTableWriterFooterRows(t *testing.T) {
+	opts := NewSimpleAlignOptions()
+	opts.FooterRows = 1
+	opts.FooterStyle = &HeaderStyle{Bold: true}
+	var buf bytes.Buffer
+	tw := NewTableWriter(&buf, opts)
+	tw.SampleRows = 1
+	for _, row := range [][]string{{"a", "b"}, {"c", "d"}, {"e", "f"}} {
+		tw.AddRow(row)
+	}
+	tw.Flush()
+	exp := "+---+---+\n| a | b |\n| c | d |\n| e | f |\n+---+---+\n"
+	if buf.String() != exp {
+		t.Errorf("%#v != %#v", buf.String(), exp)
+	}
+}
+
+func TestTableWriterHeaderRows(t *testing.T) {
+	data := [][]string{
+		{"password", "count"},
+		{"(redacted)", "n"},
+		nil,
+		{"hunter2", "1"},
+		{"letmein", "22"},
+	}
+	opts := NewSimpleAlignOptions()
+	opts.Redact = []int{0}
+	opts.HeaderStyle = &HeaderStyle{Bold: true}
+	opts.Alignments = []Alignment{Left, Right}
+	opts.HeaderAlignments = []Alignment{Center, Center}
+	opts.Formatters = []ColumnFormatter{nil, func(v string) string { return "#" + v }}
+	exp := Align(data, opts)
+	for _, tw := range []*TableWriter{{SampleRows: 2}, {FixedWidths: []int{10, 5}}} {
+		var buf bytes.Buffer
+		tw.w, tw.opts, tw.headerRows = &buf, opts, -1
+		for _, row := range data {
+			tw.AddRow(row)
+		}
+		tw.Flush()
+		if buf.String() != exp {
+			t.Errorf("%#v != %#v", buf.String(), exp)
+		}
+	}
+}
+
+func TestTableBuilder(t *testing.T) {
+	tbl := NewTable(NewSimpleAlignOptions()).
+		Row("Bob", "42").
+		Header("Name", "Age").
+		Separator().
+		Row("Alice", "37")
+	tbl.Header("", "(years)")
+	exp := `+-------+---------+
+| Name  | Age     |
+|       | (years) |
++-------+---------+
+| Bob   | 42      |
+
+| Alice | 37      |
++-------+---------+
+`
+	if out := tbl.Render(0); out != exp {
+		t.Errorf("%#v != %#v", out, exp)
+	}
+	if out := NewTable(nil).Header("a", "b").String(); out != "a b\n\n" {
+		t.Errorf("%#v", out)
+	}
+	tbl = NewTable(nil).Row("1", "2").Separator().Row("3", "4")
+	tbl.Header("a", "b")
+	tbl.Header("c", "d")
+	exp = "a b\nc d\n\n1 2\n\n3 4\n"
+	if out := tbl.String(); out != exp {
+		t.Errorf("%#v != %#v", out, exp)
+	}
+}
+
+type errWriter struct{}
+
+func (errWriter) Write(p []byte) (int, error) {
+	return 0, fmt.Errorf("write failed")
+}
+
+func TestTableWriterRowCountFooter(t *testing.T) {
+	data := [][]string{
+		{"name", "count"},
+		nil,
+		{"alpha", "1"},
+		{"beta", "22"},
+	}
+	var buf bytes.Buffer
+	tw := NewTableWriter(&buf, NewPSQLAlignOptions())
+	for i := 0; i < 2; i++ {
+		buf.Reset()
+		for _, row := range data {
+			if err := tw.AddRow(row); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := tw.Flush(); err != nil {
+			t.Fatal(err)
+		}
+		if exp := Align(data, NewPSQLAlignOptions()); buf.String() != exp {
+			t.Errorf("%d: %#v != %#v", i, buf.String(), exp)
+		}
+	}
+	buf.Reset()
+	opts := NewPSQLAlignOptions()
+	opts.NilBetweenEveryRow = true
+	tw = NewTableWriter(&buf, opts)
+	tw.FixedWidths = []int{4}
+	tw.AddRow(nil)
+	tw.Flush()
+	if exp := "(0 rows)\n"; buf.String() != exp {
+		t.Errorf("%#v != %#v", buf.String(), exp)
+	}
+}
+
+func TestTableWriterError(t *testing.T) {
+	tw := NewTableWriter(errWriter{}, nil)
+	tw.SampleRows = 1
+	if err := tw.AddRow([]string{"a"}); err == nil {
+		t.Errorf("expected an error")
+	}
+}
+
+func TestRowSources(t *testing.T) {
+	data := [][]string{
+		{"Name", "Count"},
+		nil,
+		{"alpha", "1"},
+		{"beta", "22"},
+	}
+	exp := Align(data, NewSimpleAlignOptions())
+	if out := AlignRows(SliceRowSource(data), NewSimpleAlignOptions()); out != exp {
+		t.Errorf("%#v != %#v", out, exp)
+	}
+	ch := make(chan []string)
+	go func() {
+		for _, row := range data {
+			ch <- row
+		}
+		close(ch)
+	}()
+	var buf bytes.Buffer
+	tw := NewTableWriter(&buf, NewSimpleAlignOptions())
+	tw.SampleRows = 3
+	if err := tw.WriteRows(ChannelRowSource(ch)); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != exp {
+		t.Errorf("%#v != %#v", buf.String(), exp)
+	}
+	if out := AlignRows(SliceRowSource(nil), nil); out != "" {
+		t.Errorf("%#v", out)
+	}
+}
+
+func TestTableWriterWriteRowsContext(t *testing.T) {
+	data := [][]string{
+		{"Name", "Count"},
+		nil,
+		{"alpha", "1"},
+		{"beta", "22"},
+		{"gamma", "333"},
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	source := SliceRowSource(data)
+	calls := 0
+	next := func() ([]string, bool) {
+		calls++
+		if calls == 4 {
+			cancel()
+		}
+		return source()
+	}
+	var buf bytes.Buffer
+	tw := NewTableWriter(&buf, NewSimpleAlignOptions())
+	tw.SampleRows = 3
+	if err := tw.WriteRowsContext(ctx, next); err != context.Canceled {
+		t.Fatal(err)
+	}
+	if exp := Align(data[:4], NewSimpleAlignOptions()); buf.String() != exp {
+		t.Errorf("%#v != %#v", buf.String(), exp)
+	}
+	buf.Reset()
+	tw.SampleRows = 0
+	if err := tw.WriteRowsContext(ctx, SliceRowSource(data)); err != context.Canceled {
+		t.Fatal(err)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("%#v", buf.String())
+	}
+	if err := tw.WriteRows(SliceRowSource(data[:1])); err != nil {
+		t.Fatal(err)
+	}
+	if exp := Align(data[:1], NewSimpleAlignOptions()); buf.String() != exp {
+		t.Errorf("%#v != %#v", buf.String(), exp)
+	}
+}
+
+func TestTruncateText(t *testing.T) {
+	b, z := string(ANSIEscape.Bold), string(ANSIEscape.Reset)
+	for _, v := range []struct {
+		in       string
+		width    int
+		mode     WrapMode
+		ellipsis string
+		exp      string
+	}{
+		{"abcdefgh", 8, WrapTruncate, "", "abcdefgh"},
+		{"abcdefgh", 5, WrapTruncate, "", "abcd…"},
+		{"abcdefgh", 5, WrapTruncateHead, "", "…efgh"},
+		{"abcdefgh", 5, WrapTruncateMiddle, "", "ab…gh"},
+		{"abcdefgh", 6, WrapTruncateMiddle, "", "abc…gh"},
+		{"abcdefgh", 6, WrapTruncate, "...", "abc..."},
+		{"abcdefgh", 2, WrapTruncate, "...", ".."},
+		{"abcdefgh", 1, WrapTruncate, "", "…"},
+		{"日本語です", 5, WrapTruncate, "", "日本…"},
+		{b + "abc" + z + "defgh", 5, WrapTruncateHead, "", b + z + "…efgh"},
+		{"ab" + b + "cdefg" + z + "h", 5, WrapTruncateMiddle, "", "ab" + b + "…g" + z + "h"},
+	} {
+		if out := truncateText(v.in, v.width, v.mode, v.ellipsis); out != v.exp {
+			t.Errorf("truncateText(%#v, %d, %d, %#v) %#v != %#v", v.in, v.width, v.mode, v.ellipsis, out, v.exp)
+		}
+	}
+}
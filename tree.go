@@ -0,0 +1,180 @@
+package brimtext
+
+import (
+	"bytes"
+	"strings"
+)
+
+// TreeNode is a single node for Tree to render.
+type TreeNode struct {
+	Label    string
+	Children []*TreeNode
+}
+
+// TreeOptions controls the output of Tree.
+type TreeOptions struct {
+	// Branch prefixes each node that has following siblings, LastBranch the
+	// last node of its siblings.
+	Branch     string
+	LastBranch string
+	// Vertical continues the lines under a Branch, Space under a LastBranch.
+	// These should be the same display width as Branch and LastBranch.
+	Vertical string
+	Space    string
+	// MaxDepth limits the depth of the nodes output, with the root at depth
+	// 0; a MaxDepth of 0 means no limit.
+	MaxDepth int
+	// Style, if set, is called for each node to give the label to output,
+	// such as to color directories differently than files.
+	Style func(node *TreeNode, depth int) string
+}
+
+// NewTreeOptions gives:
+//
+//	&TreeOptions{
+//	    Branch:     "├── ",
+//	    LastBranch: "└── ",
+//	    Vertical:   "│   ",
+//	    Space:      "    ",
+//	}
+//
+// Which will format trees like:
+//
+//	root
+//	├── one
+//	│   └── one-a
+//	└── two
+func NewTreeOptions() *TreeOptions {
+	return &TreeOptions{
+		Branch:     "├── ",
+		LastBranch: "└── ",
+		Vertical:   "│   ",
+		Space:      "    ",
+	}
+}
+
+// NewASCIITreeOptions gives:
+//
+//	&TreeOptions{
+//	    Branch:     "|-- ",
+//	    LastBranch: "`-- ",
+//	    Vertical:   "|   ",
+//	    Space:      "    ",
+//	}
+//
+// Which will format trees like:
+//
+//	root
+//	|-- one
+//	|   `-- one-a
+//	`-- two
+func NewASCIITreeOptions() *TreeOptions {
+	return &TreeOptions{
+		Branch:     "|-- ",
+		LastBranch: "`-- ",
+		Vertical:   "|   ",
+		Space:      "    ",
+	}
+}
+
+// Tree will format the tree of nodes according to opts, one node per line
+// with connectors showing the structure. Labels with multiple lines have
+// their extra lines indented to match. If opts is nil, NewTreeOptions is
+// used.
+func Tree(root *TreeNode, opts *TreeOptions) string {
+	if root == nil {
+		return ""
+	}
+	if opts == nil {
+		opts = NewTreeOptions()
+	}
+	var buf bytes.Buffer
+	treeNode(&buf, root, 0, "", "", "", opts)
+	return buf.String()
+}
+
+func treeNode(buf *bytes.Buffer, node *TreeNode, depth int, prefix string, connector string, continuation string, opts *TreeOptions) {
+	label := node.Label
+	if opts.Style != nil {
+		label = opts.Style(node, depth)
+	}
+	childPrefix := prefix + continuation
+	for i, line := range strings.Split(strings.Replace(label, "\r\n", "\n", -1), "\n") {
+		if i == 0 {
+			buf.WriteString(prefix + connector)
+		} else {
+			buf.WriteString(childPrefix)
+			if len(node.Children) > 0 && (opts.MaxDepth == 0 || depth < opts.MaxDepth) {
+				buf.WriteString(opts.Vertical)
+			}
+		}
+		buf.WriteString(line)
+		buf.WriteByte('\n')
+	}
+	if opts.MaxDepth > 0 && depth >= opts.MaxDepth {
+		return
+	}
+	for i, child := range node.Children {
+		if i == len(node.Children)-1 {
+			treeNode(buf, child, depth+1, childPrefix, opts.LastBranch, opts.Space, opts)
+		} else {
+			treeNode(buf, child, depth+1, childPrefix, opts.Branch, opts.Vertical, opts)
+		}
+	}
+}
+
+// TreeFromPaths builds a tree from slash separated paths, such as those from
+// filepath.ToSlash, merging the common directories the way the tree command
+// does. The root node is "/" if all the paths are absolute and "." otherwise.
+// Nodes are kept in the order first seen, so sort the paths beforehand if
+// desired.
+//
+// If annotate is not nil, it is called with each of the paths given and any
+// non-empty value returned is appended to that node's label, such as:
+//
+//	root := brimtext.TreeFromPaths(paths, func(p string) string {
+//	    return "(" + brimtext.HumanSize1024(float64(sizes[p])) + ")"
+//	})
+//	fmt.Print(brimtext.Tree(root, nil))
+func TreeFromPaths(paths []string, annotate func(path string) string) *TreeNode {
+	root := &TreeNode{Label: "."}
+	if len(paths) > 0 {
+		absolute := true
+		for _, p := range paths {
+			if !strings.HasPrefix(p, "/") {
+				absolute = false
+				break
+			}
+		}
+		if absolute {
+			root.Label = "/"
+		}
+	}
+	index := map[*TreeNode]map[string]*TreeNode{}
+	for _, p := range paths {
+		node := root
+		for _, part := range strings.Split(p, "/") {
+			if part == "" || part == "." {
+				continue
+			}
+			children := index[node]
+			if children == nil {
+				children = map[string]*TreeNode{}
+				index[node] = children
+			}
+			child := children[part]
+			if child == nil {
+				child = &TreeNode{Label: part}
+				children[part] = child
+				node.Children = append(node.Children, child)
+			}
+			node = child
+		}
+		if annotate != nil {
+			if a := annotate(p); a != "" {
+				node.Label += " " + a
+			}
+		}
+	}
+	return root
+}
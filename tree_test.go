@@ -0,0 +1,93 @@
+package brimtext
+
+import (
+	"testing"
+)
+
+func testTree() *TreeNode {
+	return &TreeNode{Label: "root", Children: []*TreeNode{
+		{Label: "one", Children: []*TreeNode{
+			{Label: "one-a"},
+			{Label: "one-b", Children: []*TreeNode{{Label: "deep"}}},
+		}},
+		{Label: "two\nlines"},
+		{Label: "three"},
+	}}
+}
+
+func TestTree(t *testing.T) {
+	out := Tree(testTree(), nil)
+	exp := `root
+├── one
+│   ├── one-a
+│   └── one-b
+│       └── deep
+├── two
+│   lines
+└── three
+`
+	if out != exp {
+		t.Errorf("Tree %#v != %#v", out, exp)
+	}
+	out = Tree(testTree(), NewASCIITreeOptions())
+	exp = "root\n|-- one\n|   |-- one-a\n|   `-- one-b\n|       `-- deep\n|-- two\n|   lines\n`-- three\n"
+	if out != exp {
+		t.Errorf("Tree ASCII %#v != %#v", out, exp)
+	}
+	if out = Tree(nil, nil); out != "" {
+		t.Errorf("Tree(nil) %#v", out)
+	}
+}
+
+func TestTreeMaxDepthAndStyle(t *testing.T) {
+	opts := NewASCIITreeOptions()
+	opts.MaxDepth = 1
+	opts.Style = func(node *TreeNode, depth int) string {
+		if len(node.Children) > 0 {
+			return node.Label + "/"
+		}
+		return node.Label
+	}
+	out := Tree(testTree(), opts)
+	exp := "root/\n|-- one/\n|-- two\n|   lines\n`-- three\n"
+	if out != exp {
+		t.Errorf("Tree MaxDepth %#v != %#v", out, exp)
+	}
+	out = Tree(&TreeNode{Label: "multi\nline", Children: []*TreeNode{{Label: "child"}}}, NewASCIITreeOptions())
+	exp = "multi\n|   line\n`-- child\n"
+	if out != exp {
+		t.Errorf("Tree multiline root %#v != %#v", out, exp)
+	}
+}
+
+func TestTreeFromPaths(t *testing.T) {
+	paths := []string{"cmd/app/main.go", "README.md", "cmd/app/flags.go", "./internal/x.go", "cmd/tool/"}
+	sizes := map[string]float64{"README.md": 1234, "cmd/app/main.go": 2048}
+	root := TreeFromPaths(paths, func(p string) string {
+		if s, ok := sizes[p]; ok {
+			return "(" + HumanSize1024(s) + ")"
+		}
+		return ""
+	})
+	out := Tree(root, NewASCIITreeOptions())
+	exp := ".\n" +
+		"|-- cmd\n" +
+		"|   |-- app\n" +
+		"|   |   |-- main.go (2K)\n" +
+		"|   |   `-- flags.go\n" +
+		"|   `-- tool\n" +
+		"|-- README.md (1.21K)\n" +
+		"`-- internal\n" +
+		"    `-- x.go\n"
+	if out != exp {
+		t.Errorf("TreeFromPaths %#v != %#v", out, exp)
+	}
+	out = Tree(TreeFromPaths([]string{"/etc/hosts", "/etc/passwd"}, nil), nil)
+	exp = "/\n└── etc\n    ├── hosts\n    └── passwd\n"
+	if out != exp {
+		t.Errorf("TreeFromPaths absolute %#v != %#v", out, exp)
+	}
+	if out = Tree(TreeFromPaths(nil, nil), nil); out != ".\n" {
+		t.Errorf("TreeFromPaths(nil) %#v", out)
+	}
+}
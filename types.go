@@ -0,0 +1,136 @@
+package brimtext
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// ByteSize is a count of bytes, shown as with HumanSizeBytes1024 by the
+// DefaultTypeRegistry.
+type ByteSize int64
+
+// TypeStyle is how the values of a Go type are shown as table cells; see
+// TypeRegistry.
+type TypeStyle struct {
+	// Format turns a value into its cell; if nil, fmt.Sprint is used.
+	Format func(value interface{}) string
+	// Formatter, if not nil, is applied to the cell Format gives, so the
+	// ColumnFormatters such as BytesFormatter can be reused.
+	Formatter ColumnFormatter
+	// Alignment is the alignment of columns of the type.
+	Alignment Alignment
+}
+
+// TypeRegistry maps Go types to how their values are rendered, consulted
+// when tables are built from Go values rather than strings, so the rendering
+// conventions are defined once per application. For example:
+//
+//	type Celsius float64
+//	brimtext.DefaultTypeRegistry.Register(Celsius(0), &brimtext.TypeStyle{
+//		Format:    func(v interface{}) string { return fmt.Sprintf("%.1f°C", v) },
+//		Alignment: brimtext.Right,
+//	})
+//
+// Types without a style of their own are shown with fmt.Sprint, right
+// aligned if they are numbers and left aligned otherwise. A TypeRegistry is
+// safe for concurrent use.
+type TypeRegistry struct {
+	lock   sync.RWMutex
+	styles map[reflect.Type]*TypeStyle
+}
+
+// DefaultTypeRegistry is the TypeRegistry used when no other is given.
+var DefaultTypeRegistry = NewTypeRegistry()
+
+// NewTypeRegistry returns a TypeRegistry with styles for time.Time (RFC 3339,
+// with the zero time blank), time.Duration (right aligned), and ByteSize
+// (HumanSizeBytes1024, right aligned).
+func NewTypeRegistry() *TypeRegistry {
+	r := &TypeRegistry{styles: map[reflect.Type]*TypeStyle{}}
+	r.Register(time.Time{}, &TypeStyle{Format: func(v interface{}) string {
+		t := v.(time.Time)
+		if t.IsZero() {
+			return ""
+		}
+		return t.Format(time.RFC3339)
+	}})
+	r.Register(time.Duration(0), &TypeStyle{Alignment: Right})
+	r.Register(ByteSize(0), &TypeStyle{Formatter: BytesFormatter(1024), Alignment: Right})
+	return r
+}
+
+// Register sets the style for the type of the sample value given, such as
+// time.Time{} or ByteSize(0); a nil style removes the type's style.
+func (r *TypeRegistry) Register(sample interface{}, style *TypeStyle) {
+	t := reflect.TypeOf(sample)
+	r.lock.Lock()
+	if style == nil {
+		delete(r.styles, t)
+	} else {
+		r.styles[t] = style
+	}
+	r.lock.Unlock()
+}
+
+// Style returns the style for the type; pointer types use the style of what
+// they point to. It is never nil, falling back to the defaults described for
+// TypeRegistry.
+func (r *TypeRegistry) Style(t reflect.Type) *TypeStyle {
+	for t != nil {
+		r.lock.RLock()
+		style := r.styles[t]
+		r.lock.RUnlock()
+		if style != nil {
+			return style
+		}
+		if t.Kind() != reflect.Ptr {
+			break
+		}
+		t = t.Elem()
+	}
+	if t != nil {
+		switch t.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+			reflect.Float32, reflect.Float64:
+			return &TypeStyle{Alignment: Right}
+		}
+	}
+	return &TypeStyle{}
+}
+
+// Format returns the value as a cell, using the style for its type. Nil
+// values, including nil pointers, give an empty cell, and other pointers are
+// followed to what they point to.
+func (r *TypeRegistry) Format(value interface{}) string {
+	rv := reflect.ValueOf(value)
+	for rv.IsValid() && rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return ""
+		}
+		r.lock.RLock()
+		_, ok := r.styles[rv.Type()]
+		r.lock.RUnlock()
+		if ok {
+			break
+		}
+		rv = rv.Elem()
+	}
+	if !rv.IsValid() {
+		return ""
+	}
+	value = rv.Interface()
+	style := r.Style(rv.Type())
+	var cell string
+	if style.Format != nil {
+		cell = style.Format(value)
+	} else {
+		cell = fmt.Sprint(value)
+	}
+	if style.Formatter != nil {
+		cell = style.Formatter(cell)
+	}
+	return cell
+}
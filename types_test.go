@@ -0,0 +1,54 @@
+package brimtext
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type testCelsius float64
+
+func TestTypeRegistry(t *testing.T) {
+	r := NewTypeRegistry()
+	r.Register(testCelsius(0), &TypeStyle{
+		Format:    func(v interface{}) string { return fmt.Sprintf("%.1f°C", v) },
+		Alignment: Right,
+	})
+	r.Register(false, &TypeStyle{Formatter: BooleanFormatter("", "")})
+	when := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	temp := testCelsius(21.55)
+	var nilTemp *testCelsius
+	for _, v := range []struct {
+		value interface{}
+		exp   string
+		align Alignment
+	}{
+		{when, "2020-01-02T03:04:05Z", Left},
+		{time.Time{}, "", Left},
+		{90 * time.Second, "1m30s", Right},
+		{ByteSize(1234567), "1.18MiB", Right},
+		{true, "✓", Left},
+		{temp, "21.6°C", Right},
+		{&temp, "21.6°C", Right},
+		{nilTemp, "", Right},
+		{42, "42", Right},
+		{uint8(7), "7", Right},
+		{"text", "text", Left},
+		{nil, "", Left},
+	} {
+		if out := r.Format(v.value); out != v.exp {
+			t.Errorf("%#v: %#v != %#v", v.value, out, v.exp)
+		}
+		if out := r.Style(reflect.TypeOf(v.value)).Alignment; out != v.align {
+			t.Errorf("%#v: %#v != %#v", v.value, out, v.align)
+		}
+	}
+	r.Register(testCelsius(0), nil)
+	if out := r.Format(temp); out != "21.55" {
+		t.Errorf("%#v != %#v", out, "21.55")
+	}
+	if out := DefaultTypeRegistry.Format(true); out != "true" {
+		t.Errorf("%#v != %#v", out, "true")
+	}
+}
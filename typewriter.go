@@ -0,0 +1,85 @@
+package brimtext
+
+import (
+	"io"
+	"os"
+	"time"
+
+	"golang.org/x/crypto/ssh/terminal"
+)
+
+// TypewriterWriter is an io.Writer that reveals the text written through it
+// a rune, or a word, at a time with a delay between each, for the typewriter
+// effect of tutorial and demo programs. ANSI escape sequences are written
+// without delay, and when the underlying writer isn't a terminal the text is
+// written straight through.
+type TypewriterWriter struct {
+	// Delay is the pause before each rune, or word if PerWord is set, after
+	// the first.
+	Delay time.Duration
+	// PerWord pauses only at the start of each word rather than at every
+	// rune.
+	PerWord bool
+	w       io.Writer
+	tty     bool
+	sleep   func(time.Duration)
+	// esc is 1 after an escape byte and 2 within a control sequence.
+	esc     int
+	pending bool
+	space   bool
+}
+
+// NewTypewriterWriter returns a TypewriterWriter writing to w with the delay
+// before each rune.
+func NewTypewriterWriter(w io.Writer, delay time.Duration) *TypewriterWriter {
+	tty := false
+	if f, ok := w.(*os.File); ok {
+		tty = terminal.IsTerminal(int(f.Fd()))
+	}
+	return &TypewriterWriter{Delay: delay, w: w, tty: tty, sleep: time.Sleep, space: true}
+}
+
+// Write writes p to the underlying writer, pausing as described for
+// TypewriterWriter.
+func (tw *TypewriterWriter) Write(p []byte) (int, error) {
+	if !tw.tty || tw.Delay <= 0 {
+		return tw.w.Write(p)
+	}
+	start := 0
+	for i, b := range p {
+		switch {
+		case tw.esc == 1:
+			tw.esc = 0
+			if b == '[' {
+				tw.esc = 2
+			}
+			continue
+		case tw.esc == 2:
+			if b >= 0x40 && b <= 0x7e {
+				tw.esc = 0
+			}
+			continue
+		case b == 27:
+			tw.esc = 1
+			continue
+		case b >= 0x80 && b < 0xc0:
+			continue
+		}
+		space := b == ' ' || b == '\t' || b == '\n' || b == '\r'
+		unit := !tw.PerWord || (tw.space && !space)
+		tw.space = space
+		if !unit {
+			continue
+		}
+		if tw.pending {
+			if n, err := tw.w.Write(p[start:i]); err != nil {
+				return start + n, err
+			}
+			start = i
+			tw.sleep(tw.Delay)
+		}
+		tw.pending = true
+	}
+	n, err := tw.w.Write(p[start:])
+	return start + n, err
+}
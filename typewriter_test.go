@@ -0,0 +1,59 @@
+package brimtext
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestTypewriterWriter(t *testing.T) {
+	for _, v := range []struct {
+		in      []string
+		perWord bool
+		exp     []string
+	}{
+		{[]string{"abc"}, false, []string{"a", "ab"}},
+		{[]string{"ab", "c"}, false, []string{"a", "ab"}},
+		{[]string{"\x1b[31mab\x1b[0m"}, false, []string{"\x1b[31ma"}},
+		{[]string{"\x1b[", "31mab"}, false, []string{"\x1b[31ma"}},
+		{[]string{"日本"}, false, []string{"日"}},
+		{[]string{"one two  three"}, true, []string{"one ", "one two  "}},
+		{[]string{"one ", "two"}, true, []string{"one "}},
+	} {
+		var buf bytes.Buffer
+		var at []string
+		tw := NewTypewriterWriter(&buf, time.Millisecond)
+		tw.PerWord = v.perWord
+		tw.tty = true
+		tw.sleep = func(d time.Duration) {
+			if d != time.Millisecond {
+				t.Errorf("slept %v", d)
+			}
+			at = append(at, buf.String())
+		}
+		want := ""
+		for _, s := range v.in {
+			n, err := tw.Write([]byte(s))
+			if n != len(s) || err != nil {
+				t.Errorf("Write(%#v) %d, %v", s, n, err)
+			}
+			want += s
+		}
+		if buf.String() != want {
+			t.Errorf("%#v: wrote %#v", v.in, buf.String())
+		}
+		if !reflect.DeepEqual(at, v.exp) {
+			t.Errorf("%#v: paused after %#v != %#v", v.in, at, v.exp)
+		}
+	}
+}
+
+func TestTypewriterWriterNotTerminal(t *testing.T) {
+	var buf bytes.Buffer
+	tw := NewTypewriterWriter(&buf, time.Hour)
+	tw.sleep = func(time.Duration) { t.Errorf("slept") }
+	if _, err := tw.Write([]byte("abc")); err != nil || buf.String() != "abc" {
+		t.Errorf("%v %#v", err, buf.String())
+	}
+}
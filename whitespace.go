@@ -0,0 +1,127 @@
+package brimtext
+
+import (
+	"bytes"
+	"strings"
+	"unicode/utf8"
+)
+
+// ExpandTabs replaces each tab in the text with the spaces needed to reach the
+// next tab stop, every tabstop columns; a tabstop less than 1 means 8. Columns
+// are counted by display width, so wide runes and ANSI escape sequences are
+// accounted for, and each line starts at column 0.
+func ExpandTabs(s string, tabstop int) string {
+	if !strings.Contains(s, "\t") {
+		return s
+	}
+	if tabstop < 1 {
+		tabstop = 8
+	}
+	var buf bytes.Buffer
+	col := 0
+	for i := 0; i < len(s); {
+		if s[i] == '\x1b' {
+			j := strings.IndexByte(s[i:], 'm')
+			if j != -1 {
+				buf.WriteString(s[i : i+j+1])
+				i += j + 1
+				continue
+			}
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		i += size
+		switch r {
+		case '\t':
+			n := tabstop - col%tabstop
+			buf.WriteString(strings.Repeat(" ", n))
+			col += n
+		case '\n':
+			buf.WriteRune(r)
+			col = 0
+		default:
+			buf.WriteRune(r)
+			col += RuneWidth(r)
+		}
+	}
+	return buf.String()
+}
+
+// UnexpandTabs is the reverse of ExpandTabs, replacing runs of two or more
+// spaces that end at a tab stop with a tab; a tabstop less than 1 means 8.
+func UnexpandTabs(s string, tabstop int) string {
+	if tabstop < 1 {
+		tabstop = 8
+	}
+	var buf bytes.Buffer
+	col := 0
+	spaces := 0
+	flush := func() {
+		buf.WriteString(strings.Repeat(" ", spaces))
+		spaces = 0
+	}
+	for i := 0; i < len(s); {
+		if s[i] == '\x1b' {
+			j := strings.IndexByte(s[i:], 'm')
+			if j != -1 {
+				flush()
+				buf.WriteString(s[i : i+j+1])
+				i += j + 1
+				continue
+			}
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		i += size
+		switch r {
+		case ' ':
+			spaces++
+			col++
+			if col%tabstop == 0 {
+				if spaces > 1 {
+					buf.WriteByte('\t')
+					spaces = 0
+				} else {
+					flush()
+				}
+			}
+		case '\t':
+			spaces = 0
+			buf.WriteByte('\t')
+			col += tabstop - col%tabstop
+		case '\n':
+			flush()
+			buf.WriteRune(r)
+			col = 0
+		default:
+			flush()
+			buf.WriteRune(r)
+			col += RuneWidth(r)
+		}
+	}
+	flush()
+	return buf.String()
+}
+
+// NormalizeSpace collapses each run of Unicode whitespace in the text,
+// including newlines, into a single space and trims any leading and trailing
+// whitespace. See NormalizeSpaceLines to keep line breaks.
+func NormalizeSpace(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// NormalizeSpaceLines is like NormalizeSpace but keeps the line breaks; each
+// line has its whitespace collapsed and trimmed, "\r\n" becomes "\n", and any
+// leading and trailing blank lines are removed.
+func NormalizeSpaceLines(s string) string {
+	s = strings.Replace(s, "\r\n", "\n", -1)
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		lines[i] = NormalizeSpace(line)
+	}
+	for len(lines) > 0 && lines[0] == "" {
+		lines = lines[1:]
+	}
+	for len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,98 @@
+package brimtext
+
+import (
+	"testing"
+)
+
+func TestExpandTabs(t *testing.T) {
+	for _, v := range []struct {
+		in      string
+		tabstop int
+		exp     string
+	}{
+		{"", 8, ""},
+		{"no tabs", 8, "no tabs"},
+		{"\tx", 8, "        x"},
+		{"a\tb", 4, "a   b"},
+		{"abcd\tb", 4, "abcd    b"},
+		{"a\tb\tc", 0, "a       b       c"},
+		{"a\tb\nab\tc", 4, "a   b\nab  c"},
+		{"日\tx", 4, "日  x"},
+		{"\x1b[1mab\x1b[0m\tx", 4, "\x1b[1mab\x1b[0m  x"},
+	} {
+		out := ExpandTabs(v.in, v.tabstop)
+		if out != v.exp {
+			t.Errorf("ExpandTabs(%#v, %d) %#v != %#v", v.in, v.tabstop, out, v.exp)
+		}
+	}
+}
+
+func TestUnexpandTabs(t *testing.T) {
+	for _, v := range []struct {
+		in      string
+		tabstop int
+		exp     string
+	}{
+		{"", 8, ""},
+		{"        x", 8, "\tx"},
+		{"a   b", 4, "a\tb"},
+		{"abc b", 4, "abc b"},
+		{"a b", 4, "a b"},
+		{"a   b\nab  c", 4, "a\tb\nab\tc"},
+		{"日  x", 4, "日\tx"},
+		{"trailing  ", 4, "trailing  "},
+	} {
+		out := UnexpandTabs(v.in, v.tabstop)
+		if out != v.exp {
+			t.Errorf("UnexpandTabs(%#v, %d) %#v != %#v", v.in, v.tabstop, out, v.exp)
+		}
+	}
+	in := "a\tbc\tg"
+	if out := UnexpandTabs(ExpandTabs(in, 4), 4); out != in {
+		t.Errorf("UnexpandTabs(ExpandTabs(%#v)) %#v != %#v", in, out, in)
+	}
+}
+
+func TestExpandTabsInWrapAndAlign(t *testing.T) {
+	out := Wrap("a\tb c", 79, "", "")
+	exp := "a b c"
+	if out != exp {
+		t.Errorf("Wrap with tabs %#v != %#v", out, exp)
+	}
+	out = Align([][]string{{"x\ty", "z"}, {"abc", "d"}}, nil)
+	exp = "x       y z\nabc       d\n"
+	if out != exp {
+		t.Errorf("Align with tabs %#v != %#v", out, exp)
+	}
+}
+
+func TestNormalizeSpace(t *testing.T) {
+	for in, exp := range map[string]string{
+		"":                                "",
+		"   ":                             "",
+		"a":                               "a",
+		"  a   b  ":                       "a b",
+		"a\tb\nc\r\nd":                    "a b c d",
+		"a\u00a0\u00a0b\u2003c\u3000d":    "a b c d",
+		"\n\n  scraped \t  text \n here ": "scraped text here",
+	} {
+		out := NormalizeSpace(in)
+		if out != exp {
+			t.Errorf("NormalizeSpace(%#v) %#v != %#v", in, out, exp)
+		}
+	}
+}
+
+func TestNormalizeSpaceLines(t *testing.T) {
+	for in, exp := range map[string]string{
+		"":                          "",
+		"  a   b  ":                 "a b",
+		"\n\n a  b \r\n\n  c\td \n": "a b\n\nc d",
+		"one\ntwo":                  "one\ntwo",
+	} {
+		out := NormalizeSpaceLines(in)
+		if out != exp {
+			t.Errorf("NormalizeSpaceLines(%#v) %#v != %#v", in, out, exp)
+		}
+	}
+}
@@ -0,0 +1,204 @@
+package brimtext
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+	"golang.org/x/text/width"
+)
+
+// RuneWidth returns the number of terminal columns the rune occupies: 2 for
+// East Asian wide and fullwidth runes, 0 for combining marks, zero width
+// formatting runes, and control characters, and 1 otherwise.
+func RuneWidth(r rune) int {
+	if r < 0x20 || r == 0x7f || (r >= 0x80 && r < 0xa0) {
+		return 0
+	}
+	if unicode.In(r, unicode.Mn, unicode.Me, unicode.Cf) {
+		return 0
+	}
+	switch width.LookupRune(r).Kind() {
+	case width.EastAsianWide, width.EastAsianFullwidth:
+		return 2
+	}
+	return 1
+}
+
+// DisplayWidth returns the number of terminal columns the string would occupy
+// once output, ignoring ANSI escape sequences and accounting for wide and zero
+// width runes; see RuneWidth. Unlike RuneLenStripANSIEscapes, this gives the
+// true width of text like "日本語" (6 rather than 3).
+func DisplayWidth(s string) int {
+	w := 0
+	for _, r := range StripANSIEscapes(s) {
+		w += RuneWidth(r)
+	}
+	return w
+}
+
+// NormalizeUnicode returns the text in Unicode Normalization Form C, so that
+// visually identical strings, such as "é" written as one rune or as "e" plus
+// a combining accent, compare and measure identically.
+func NormalizeUnicode(s string) string {
+	return norm.NFC.String(s)
+}
+
+// StripInvisible removes zero width formatting runes, such as U+200B, and
+// control characters from the text. Newlines, tabs, and ANSI SGR escape
+// sequences (the ones StripANSIEscapes would remove) are kept.
+func StripInvisible(s string) string {
+	var buf bytes.Buffer
+	for i := 0; i < len(s); {
+		if s[i] == '\x1b' {
+			if j := sgrLen(s[i:]); j > 0 {
+				buf.WriteString(s[i : i+j])
+				i += j
+				continue
+			}
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		i += size
+		if r == '\n' || r == '\t' || RuneWidth(r) > 0 {
+			buf.WriteRune(r)
+		} else if unicode.In(r, unicode.Mn, unicode.Me) {
+			buf.WriteRune(r)
+		}
+	}
+	return buf.String()
+}
+
+// sgrLen returns the length of the ANSI SGR escape sequence, such as
+// "\x1b[1;31m", at the start of s or 0 if there isn't one.
+func sgrLen(s string) int {
+	if len(s) < 3 || s[0] != '\x1b' || s[1] != '[' {
+		return 0
+	}
+	for i := 2; i < len(s); i++ {
+		c := s[i]
+		if c == 'm' {
+			return i + 1
+		}
+		if (c < '0' || c > '9') && c != ';' {
+			return 0
+		}
+	}
+	return 0
+}
+
+// sgrUnterminated returns true if the last ANSI SGR escape sequence in s
+// leaves styling in effect, that is, it isn't a reset.
+func sgrUnterminated(s string) bool {
+	last := ""
+	for i := strings.IndexByte(s, '\x1b'); i >= 0; {
+		if n := sgrLen(s[i:]); n > 0 {
+			last = s[i : i+n]
+			i += n
+		} else {
+			i++
+		}
+		j := strings.IndexByte(s[i:], '\x1b')
+		if j < 0 {
+			break
+		}
+		i += j
+	}
+	return last != "" && last != "\x1b[0m" && last != "\x1b[m"
+}
+
+// wrapRunes splits the text into lines of at most width display columns,
+// breaking between any two runes rather than at spaces. ANSI SGR sequences
+// in effect at a break are reset at the end of the line and reapplied at the
+// start of the next.
+func wrapRunes(s string, width int) []string {
+	if width < 1 || DisplayWidth(s) <= width {
+		return []string{s}
+	}
+	var lines []string
+	var cur bytes.Buffer
+	curWidth := 0
+	active := ""
+	for i := 0; i < len(s); {
+		if n := sgrLen(s[i:]); n > 0 {
+			code := s[i : i+n]
+			cur.WriteString(code)
+			if code == "\x1b[0m" || code == "\x1b[m" {
+				active = ""
+			} else {
+				active += code
+			}
+			i += n
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		i += size
+		rw := RuneWidth(r)
+		if curWidth+rw > width && curWidth > 0 {
+			if active != "" {
+				cur.Write(ANSIEscape.Reset)
+			}
+			lines = append(lines, cur.String())
+			cur.Reset()
+			cur.WriteString(active)
+			curWidth = 0
+		}
+		cur.WriteRune(r)
+		curWidth += rw
+	}
+	return append(lines, cur.String())
+}
+
+// ControlChars selects how SanitizeControl treats control characters.
+type ControlChars int
+
+const (
+	// KeepControlChars leaves control characters as they are.
+	KeepControlChars ControlChars = iota
+	// StripControlChars removes control characters.
+	StripControlChars
+	// EscapeControlChars replaces control characters with a visible form,
+	// caret notation such as "^G" for C0 controls and DEL, or "\x9b" style
+	// for C1 controls.
+	EscapeControlChars
+)
+
+// SanitizeControl strips or escapes, according to mode, the control
+// characters in s that would corrupt terminal output, such as bells,
+// backspaces, and stray escapes. Newlines, tabs, carriage returns followed
+// by newlines, and ANSI SGR escape sequences are kept.
+func SanitizeControl(s string, mode ControlChars) string {
+	if mode == KeepControlChars {
+		return s
+	}
+	var buf bytes.Buffer
+	for i := 0; i < len(s); {
+		if s[i] == '\x1b' {
+			if j := sgrLen(s[i:]); j > 0 {
+				buf.WriteString(s[i : i+j])
+				i += j
+				continue
+			}
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		i += size
+		if r == '\n' || r == '\t' || (r == '\r' && i < len(s) && s[i] == '\n') || !unicode.IsControl(r) {
+			buf.WriteRune(r)
+			continue
+		}
+		if mode == EscapeControlChars {
+			switch {
+			case r < 0x20:
+				buf.WriteByte('^')
+				buf.WriteByte(byte(r) + '@')
+			case r == 0x7f:
+				buf.WriteString("^?")
+			default:
+				fmt.Fprintf(&buf, "\\x%02x", r)
+			}
+		}
+	}
+	return buf.String()
+}
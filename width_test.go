@@ -0,0 +1,217 @@
+package brimtext
+
+import (
+	"testing"
+)
+
+func TestRuneWidth(t *testing.T) {
+	for in, exp := range map[rune]int{
+		'a':      1,
+		'À':      1,
+		'\uff21': 2,
+		'日':      2,
+		'\u0301': 0,
+		'\u200b': 0,
+		'\x07':   0,
+		'\t':     0,
+		'𝐀':      1,
+	} {
+		out := RuneWidth(in)
+		if out != exp {
+			t.Errorf("RuneWidth(%#v) %d != %d", in, out, exp)
+		}
+	}
+}
+
+func TestDisplayWidth(t *testing.T) {
+	for in, exp := range map[string]int{
+		"":                         0,
+		"abc":                      3,
+		"日本語":                      6,
+		"e\u0301":                  1,
+		"\x1b[1mbold\x1b[0m":       4,
+		"\x1b[31m日本\x1b[0m and me": 11,
+	} {
+		out := DisplayWidth(in)
+		if out != exp {
+			t.Errorf("DisplayWidth(%#v) %d != %d", in, out, exp)
+		}
+	}
+}
+
+func TestNormalizeUnicode(t *testing.T) {
+	in := "e\u0301t\u00e9"
+	out := NormalizeUnicode(in)
+	exp := "\u00e9t\u00e9"
+	if out != exp {
+		t.Errorf("NormalizeUnicode(%#v) %#v != %#v", in, out, exp)
+	}
+}
+
+func TestStripInvisible(t *testing.T) {
+	for in, exp := range map[string]string{
+		"":                        "",
+		"plain":                   "plain",
+		"zero\u200bwidth\ufeff":   "zerowidth",
+		"bell\a and\bback":        "bell andback",
+		"keep\n\tthese":           "keep\n\tthese",
+		"\x1b[1;31mred\x1b[0m":    "\x1b[1;31mred\x1b[0m",
+		"stray\x1b escape":        "stray escape",
+		"e\u0301 stays":           "e\u0301 stays",
+		"\u0085next line\u2060ok": "next lineok",
+	} {
+		out := StripInvisible(in)
+		if out != exp {
+			t.Errorf("StripInvisible(%#v) %#v != %#v", in, out, exp)
+		}
+	}
+}
+
+func TestWrapWithOptionsUnicode(t *testing.T) {
+	out := WrapWithOptions("ab\u200bcd ef", &WrapOptions{Width: 5, StripInvisible: true})
+	exp := "abcd\nef"
+	if out != exp {
+		t.Errorf("WrapWithOptions StripInvisible %#v != %#v", out, exp)
+	}
+}
+
+func TestAlignUnicodeOptions(t *testing.T) {
+	opts := NewDefaultAlignOptions()
+	opts.NormalizeUnicode = true
+	opts.StripInvisible = true
+	out := Align([][]string{{"e\u0301", "x"}, {"a\u200bb", "y"}}, opts)
+	exp := "\u00e9  x\nab y\n"
+	if out != exp {
+		t.Errorf("Align NormalizeUnicode/StripInvisible %#v != %#v", out, exp)
+	}
+}
+
+func TestWrapRunes(t *testing.T) {
+	for _, v := range []struct {
+		in    string
+		width int
+		exp   []string
+	}{
+		{"", 3, []string{""}},
+		{"abc", 3, []string{"abc"}},
+		{"abcdefg", 3, []string{"abc", "def", "g"}},
+		{"  indented", 4, []string{"  in", "dent", "ed"}},
+		{"日本語", 4, []string{"日本", "語"}},
+		{"\x1b[31mabcd\x1b[0mef", 3, []string{"\x1b[31mabc\x1b[0m", "\x1b[31md\x1b[0mef"}},
+		{"abc", 0, []string{"abc"}},
+	} {
+		out := wrapRunes(v.in, v.width)
+		if len(out) != len(v.exp) {
+			t.Errorf("wrapRunes(%#v, %d) %#v != %#v", v.in, v.width, out, v.exp)
+			continue
+		}
+		for i := range out {
+			if out[i] != v.exp[i] {
+				t.Errorf("wrapRunes(%#v, %d) %#v != %#v", v.in, v.width, out, v.exp)
+				break
+			}
+		}
+	}
+}
+
+func TestSGRUnterminated(t *testing.T) {
+	for in, exp := range map[string]bool{
+		"":                            false,
+		"plain":                       false,
+		"\x1b[31mred":                 true,
+		"\x1b[31mred\x1b[0m":          false,
+		"\x1b[1m\x1b[31mx\x1b[m":      false,
+		"\x1b[31mred\x1b[0m \x1b[1mb": true,
+		"\x1b[2Jclear":                false,
+		"\x1b":                        false,
+	} {
+		if out := sgrUnterminated(in); out != exp {
+			t.Errorf("sgrUnterminated(%#v) %v != %v", in, out, exp)
+		}
+	}
+}
+
+func TestSanitizeControl(t *testing.T) {
+	for _, v := range []struct {
+		in     string
+		strip  string
+		escape string
+	}{
+		{"", "", ""},
+		{"plain", "plain", "plain"},
+		{"ding\a!", "ding!", "ding^G!"},
+		{"back\bspace", "backspace", "back^Hspace"},
+		{"a\tb\nc\r\nd", "a\tb\nc\r\nd", "a\tb\nc\r\nd"},
+		{"over\rwrite", "overwrite", "over^Mwrite"},
+		{"\x1b[1mbold\x1b[0m", "\x1b[1mbold\x1b[0m", "\x1b[1mbold\x1b[0m"},
+		{"\x1b]0;title\a", "]0;title", "^[]0;title^G"},
+		{"del\x7f \u009b", "del ", "del^? \\x9b"},
+		{"日本", "日本", "日本"},
+	} {
+		if out := SanitizeControl(v.in, StripControlChars); out != v.strip {
+			t.Errorf("SanitizeControl(%#v, Strip) %#v != %#v", v.in, out, v.strip)
+		}
+		if out := SanitizeControl(v.in, EscapeControlChars); out != v.escape {
+			t.Errorf("SanitizeControl(%#v, Escape) %#v != %#v", v.in, out, v.escape)
+		}
+		if out := SanitizeControl(v.in, KeepControlChars); out != v.in {
+			t.Errorf("SanitizeControl(%#v, Keep) %#v", v.in, out)
+		}
+	}
+}
+
+func TestAlignControlChars(t *testing.T) {
+	data := [][]string{{"a\a", "b"}, {"cc", "d"}}
+	opts := NewDefaultAlignOptions()
+	opts.ControlChars = EscapeControlChars
+	out := Align(data, opts)
+	exp := "a^G b\ncc  d\n"
+	if out != exp {
+		t.Errorf("Align EscapeControlChars %#v != %#v", out, exp)
+	}
+	opts.ControlChars = StripControlChars
+	out = Align(data, opts)
+	exp = "a  b\ncc d\n"
+	if out != exp {
+		t.Errorf("Align StripControlChars %#v != %#v", out, exp)
+	}
+}
+
+func TestWrapWithOptionsControlChars(t *testing.T) {
+	in := "log: \x1b]0;pwned\a payload\b\b ok \x1b[31mred\x1b[0m"
+	out := WrapWithOptions(in, &WrapOptions{Width: 20, ControlChars: EscapeControlChars})
+	exp := "log: ^[]0;pwned^G\npayload^H^H ok \x1b[31mred\x1b[0m"
+	if out != exp {
+		t.Errorf("WrapWithOptions EscapeControlChars %#v != %#v", out, exp)
+	}
+	out = WrapWithOptions(in, &WrapOptions{Width: 20, ControlChars: StripControlChars})
+	exp = "log: ]0;pwned\npayload ok \x1b[31mred\x1b[0m"
+	if out != exp {
+		t.Errorf("WrapWithOptions StripControlChars %#v != %#v", out, exp)
+	}
+}
+
+func TestAlignResetCells(t *testing.T) {
+	data := [][]string{
+		{"\x1b[31mred", "x"},
+		{"\x1b[1mok\x1b[0m", "y"},
+	}
+	opts := NewSimpleAlignOptions()
+	opts.ResetCells = true
+	out := Align(data, opts)
+	exp := "+-----+---+\n" +
+		"| \x1b[31mred\x1b[0m | x |\n" +
+		"| \x1b[1mok\x1b[0m  | y |\n" +
+		"+-----+---+\n"
+	if out != exp {
+		t.Errorf("%#v != %#v", out, exp)
+	}
+	opts.ResetCells = false
+	if out = Align(data, opts); out == exp {
+		t.Errorf("ResetCells false still reset")
+	}
+	opts.HeatScales = []*HeatScale{nil, nil}
+	if out = Align(data, opts); out != exp {
+		t.Errorf("HeatScales %#v != %#v", out, exp)
+	}
+}
@@ -0,0 +1,72 @@
+package brimtext
+
+import (
+	"bytes"
+	"io"
+)
+
+// WrappingWriter is an io.Writer that wraps each line of the text written
+// through it, as Wrap does, so code that writes long lines with fmt.Fprintf
+// gives readable output just by swapping the writer. Text is held until the
+// end of each line, so lines and ANSI escape codes split across writes are
+// handled; use Flush to output a final line that has no newline.
+type WrappingWriter struct {
+	// Width has the same meaning as the width given to Wrap. When < 1, the
+	// terminal's width is checked for each line, so the output follows the
+	// terminal as it is resized.
+	Width int
+	// Indent1 is the prefix for the first line of each line written.
+	Indent1 string
+	// Indent2 is the prefix for the lines each line written wraps onto.
+	Indent2 string
+	w       io.Writer
+	line    []byte
+}
+
+// NewWrappingWriter returns a WrappingWriter writing to w with the width
+// given, which has the same meaning as the width given to Wrap.
+func NewWrappingWriter(w io.Writer, width int) *WrappingWriter {
+	return &WrappingWriter{Width: width, w: w}
+}
+
+// Write wraps and writes each complete line of p, holding any partial line
+// until the rest of it is written or Flush is called.
+func (ww *WrappingWriter) Write(p []byte) (int, error) {
+	n := 0
+	for {
+		i := bytes.IndexByte(p[n:], '\n')
+		if i < 0 {
+			break
+		}
+		ww.line = append(ww.line, p[n:n+i]...)
+		if err := ww.writeLine(true); err != nil {
+			return n, err
+		}
+		n += i + 1
+	}
+	ww.line = append(ww.line, p[n:]...)
+	return len(p), nil
+}
+
+// Flush wraps and writes any partial line held, without adding a newline.
+func (ww *WrappingWriter) Flush() error {
+	if len(ww.line) == 0 {
+		return nil
+	}
+	return ww.writeLine(false)
+}
+
+func (ww *WrappingWriter) writeLine(newline bool) error {
+	width := ww.Width
+	if width < 1 {
+		width = GetTTYWidth() - 1 + width
+	}
+	out := wrap(bytes.TrimSuffix(ww.line, []byte{'\r'}), width, []byte(ww.Indent1), []byte(ww.Indent2), nil)
+	out = bytes.Trim(out, "\n")
+	if newline {
+		out = append(out, '\n')
+	}
+	ww.line = ww.line[:0]
+	_, err := ww.w.Write(out)
+	return err
+}
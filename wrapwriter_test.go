@@ -0,0 +1,40 @@
+package brimtext
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestWrappingWriter(t *testing.T) {
+	for _, v := range []struct {
+		in      []string
+		indent1 string
+		indent2 string
+		exp     string
+	}{
+		{[]string{"one two three four\n"}, "", "", "one two\nthree four\n"},
+		{[]string{"one two ", "three four\nfive\n"}, "", "", "one two\nthree four\nfive\n"},
+		{[]string{"one\n\ntwo\n"}, "", "", "one\n\ntwo\n"},
+		{[]string{"one two three\r\n"}, "", "", "one two\nthree\n"},
+		{[]string{"one two three four\n"}, "* ", "  ", "* one two\n  three\n  four\n"},
+		{[]string{"\x1b[31mone", " two\x1b[0m three\n"}, "", "", "\x1b[31mone two\x1b[0m\nthree\n"},
+		{[]string{"one two three"}, "", "", "one two\nthree"},
+	} {
+		var buf bytes.Buffer
+		ww := NewWrappingWriter(&buf, 10)
+		ww.Indent1 = v.indent1
+		ww.Indent2 = v.indent2
+		for _, s := range v.in {
+			n, err := ww.Write([]byte(s))
+			if n != len(s) || err != nil {
+				t.Errorf("Write(%#v) %d, %v", s, n, err)
+			}
+		}
+		if err := ww.Flush(); err != nil {
+			t.Error(err)
+		}
+		if out := buf.String(); out != v.exp {
+			t.Errorf("%#v: %#v != %#v", v.in, out, v.exp)
+		}
+	}
+}